@@ -36,12 +36,20 @@ export const buildChartVariables = (
           browser: filter.browser ?? null,
           device: filter.device ?? null,
           os: filter.os ?? null,
+          screenSize: filter.screenSize ?? null,
           page: filter.page ?? null,
           country: filter.country ?? null,
           eventType: filter.eventType ?? null,
           eventDefinitionId: filter.eventDefinitionId ?? null,
           eventName: filter.eventName ?? null,
           eventPath: filter.eventPath ?? null,
+          referrerDomain: filter.referrerDomain ?? null,
+          channel: filter.channel ?? null,
+          utmSource: filter.utmSource ?? null,
+          utmMedium: filter.utmMedium ?? null,
+          utmCampaign: filter.utmCampaign ?? null,
+          utmTerm: filter.utmTerm ?? null,
+          utmContent: filter.utmContent ?? null,
         },
   bucket: bucketValue,
   paging: { limit: BATCH_SIZE, offset: 0 },
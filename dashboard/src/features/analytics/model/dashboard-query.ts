@@ -103,10 +103,18 @@ export const buildEventCountsVariables = (
     page: filter?.page ?? null,
     country: filter?.country ?? null,
     os: filter?.os ?? null,
+    screenSize: filter?.screenSize ?? null,
     eventType: [PREDEFINED_EVENT_TYPE],
     eventDefinitionId: filter?.eventDefinitionId ?? null,
     eventName: filter?.eventName ?? null,
     eventPath: filter?.eventPath ?? null,
+    referrerDomain: filter?.referrerDomain ?? null,
+    channel: filter?.channel ?? null,
+    utmSource: filter?.utmSource ?? null,
+    utmMedium: filter?.utmMedium ?? null,
+    utmCampaign: filter?.utmCampaign ?? null,
+    utmTerm: filter?.utmTerm ?? null,
+    utmContent: filter?.utmContent ?? null,
   },
   paging: {
     limit: EVENTS_COUNT_PAGE_SIZE,
@@ -47,12 +47,20 @@ export function buildFilterInput(filter: Record<string, string[]>): FilterInput
     browser: getFilter('browser'),
     device: getFilter('device'),
     os: getFilter('os'),
+    screenSize: null,
     page: getFilter('page'),
     country: getFilter('country'),
     eventType: null,
     eventDefinitionId: getFilter('eventDefinitionId'),
     eventName: getFilter('eventName'),
     eventPath: getFilter('eventPath'),
+    referrerDomain: null,
+    channel: null,
+    utmSource: null,
+    utmMedium: null,
+    utmCampaign: null,
+    utmTerm: null,
+    utmContent: null,
   };
 }
 
@@ -78,6 +78,7 @@ export const SiteSettingsScreen = (): React.ReactNode => {
           name: nameValue,
           domains: domainsValue,
           trackCountry: null,
+          timezone: null,
           blockedIPs: null,
           blockedCountries: null,
         },
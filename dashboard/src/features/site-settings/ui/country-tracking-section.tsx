@@ -47,6 +47,7 @@ export const CountryTrackingSection = ({
           input: {
             name: siteName,
             trackCountry: enabled,
+            timezone: null,
             domains: null,
             blockedIPs: null,
             blockedCountries: null,
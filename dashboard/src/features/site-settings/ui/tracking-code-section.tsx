@@ -34,12 +34,16 @@ export const TrackingCodeSection = ({
   const { trackingScript, trackingSnippet } = useMemo(() => {
     const basePath = window.__ENV__?.BASE_PATH ?? '';
     const trackerUrl = `${window.location.origin}${basePath}/tracker.js`;
+    const pixelUrl = `${window.location.origin}${basePath}/api/pixel.gif?site_key=${publicKey}`;
 
     const scriptTag = `<script
   defer
   src="${trackerUrl}"
   data-site-key="${publicKey}"
-></script>`;
+></script>
+<noscript>
+  <img src="${pixelUrl}" alt="" width="1" height="1" referrerpolicy="no-referrer-when-downgrade" />
+</noscript>`;
     const scriptSnippet = `(function () {
   var script = document.createElement('script');
   script.defer = true;
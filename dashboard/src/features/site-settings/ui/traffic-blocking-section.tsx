@@ -29,6 +29,7 @@ export const TrafficBlockingSection = ({
           name: siteName,
           blockedIPs,
           trackCountry: null,
+          timezone: null,
           domains: null,
           blockedCountries: null,
         },
@@ -44,6 +45,7 @@ export const TrafficBlockingSection = ({
           name: siteName,
           blockedCountries,
           trackCountry: null,
+          timezone: null,
           domains: null,
           blockedIPs: null,
         },
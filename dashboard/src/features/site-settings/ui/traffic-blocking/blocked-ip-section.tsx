@@ -62,7 +62,7 @@ const BlockedIPSection: FunctionComponent<BlockedIPSectionProps> = ({
       <div className='space-y-1'>
         <div className='flex items-center gap-2'>
           <Input
-            placeholder='203.0.113.10'
+            placeholder='203.0.113.10 or 10.0.0.0/8'
             value={newIPValue}
             onChange={(event) => {
               setNewIPValue(normalizeIPInput(event.currentTarget.value));
@@ -4,8 +4,10 @@ export const EMPTY_COUNT = 0;
 export const EMPTY_STRING = '';
 export const FIRST_INDEX = 0;
 export const ID_OFFSET = 1;
+export const IPV4_MAX_PREFIX_BITS = 32;
 export const IPV4_MAX_VALUE = 255;
 export const IPV4_PARTS_COUNT = 4;
+export const IPV6_MAX_PREFIX_BITS = 128;
 export const MAX_COUNTRIES = 250;
 export const MAX_COUNTRY_MATCHES = 8;
 export const MAX_IPS = 500;
@@ -4,8 +4,10 @@ import {
   EMPTY_STRING,
   FIRST_INDEX,
   ID_OFFSET,
+  IPV4_MAX_PREFIX_BITS,
   IPV4_MAX_VALUE,
   IPV4_PARTS_COUNT,
+  IPV6_MAX_PREFIX_BITS,
   MAX_IPS,
   SEARCH_SINGLE_MATCH_COUNT,
 } from './constants';
@@ -17,11 +19,18 @@ export const buildBlockedIPEntries = (values: string[]): BlockedIPEntry[] =>
 export const blockedIPValues = (entries: BlockedIPEntry[]): string[] =>
   getNormalizedBlockedIPs(entries.map(({ value }) => value));
 
-const isValidIP = (value: string): boolean => {
-  if (value === EMPTY_STRING) return false;
+const isValidPrefixBits = (bits: string, maxBits: number): boolean =>
+  /^\d{1,3}$/v.test(bits) && Number(bits) <= maxBits;
+
+// Accepts a single address or a CIDR range such as 10.0.0.0/8 or 2001:db8::/32.
+const isValidIP = (entry: string): boolean => {
+  const [value = EMPTY_STRING, bits, ...rest] = entry.split('/');
+  if (value === EMPTY_STRING || rest.length > EMPTY_COUNT) return false;
   if (value.includes(':')) {
+    if (bits !== undefined && !isValidPrefixBits(bits, IPV6_MAX_PREFIX_BITS)) return false;
     return /^(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}$/iv.test(value);
   }
+  if (bits !== undefined && !isValidPrefixBits(bits, IPV4_MAX_PREFIX_BITS)) return false;
   const parts = value.split('.');
   if (parts.length !== IPV4_PARTS_COUNT) return false;
   return parts.every((part) => {
@@ -45,7 +54,7 @@ export const validateNewIP = (
   count: number
 ): string => {
   if (value === EMPTY_STRING) return 'Enter a valid IP before saving.';
-  if (!isValidIP(value)) return 'Enter a valid IP address.';
+  if (!isValidIP(value)) return 'Enter a valid IP address or CIDR range.';
   if (blockedIPValues(blockedIPs).includes(value)) return 'That IP is already blocked.';
   if (count + 1 > MAX_IPS) return 'Blocked IP list can include up to 500 entries';
   return '';
@@ -24,7 +24,7 @@ export function CreateSiteScreen(): React.ReactNode {
   });
   const form = useCreateSiteForm({
     onCreate: async (name, domains) => {
-      const { data } = await createSite({ variables: { input: { name, domains } } });
+      const { data } = await createSite({ variables: { input: { name, domains, timezone: null } } });
       if (data === undefined) throw new Error('Site creation returned no data');
       const site = readFragment(SiteSummaryFieldsFragmentDoc, data.createSite);
       rememberSite(site.id);
@@ -4,9 +4,23 @@ type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };
 /** Internal type. DO NOT USE DIRECTLY. */
 export type Incremental<T> = T | { [P in keyof T]?: P extends ' $fragmentName' | '__typename' ? T[P] : never };
 import type { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';
+export type ComparisonInput = {
+  /** Required when mode is CUSTOM */
+  dateRange: DateRangeInput | null | undefined;
+  mode: ComparisonMode;
+};
+
+export type ComparisonMode =
+  /** The range given in ComparisonInput.dateRange */
+  | 'CUSTOM'
+  /** The range of equal length ending just before dateRange starts */
+  | 'PREVIOUS_PERIOD';
+
 export type CreateSiteInput = {
   domains: Array<string>;
   name: string;
+  /** IANA timezone, defaults to UTC */
+  timezone: string | null | undefined;
 };
 
 export type DateRangeInput = {
@@ -38,6 +52,8 @@ export type EventType =
 export type FilterInput = {
   /** Filter by browser type */
   browser: Array<string> | null | undefined;
+  /** Filter by acquisition channel (direct, email, paid, referral, search, social) */
+  channel: Array<string> | null | undefined;
   /** Filter by ISO country code */
   country: Array<string> | null | undefined;
   /** Filter by device type (desktop, mobile, tablet, smart-tv, console, watch) */
@@ -56,8 +72,37 @@ export type FilterInput = {
   page: Array<string> | null | undefined;
   /** Filter by specific referrer */
   referrer: Array<string> | null | undefined;
+  /** Filter by normalized referrer host */
+  referrerDomain: Array<string> | null | undefined;
+  /** Filter by screen size (watch, xs, sm, md, lg, xl) */
+  screenSize: Array<string> | null | undefined;
+  /** Filter by session utm_campaign */
+  utmCampaign: Array<string> | null | undefined;
+  /** Filter by session utm_content */
+  utmContent: Array<string> | null | undefined;
+  /** Filter by session utm_medium */
+  utmMedium: Array<string> | null | undefined;
+  /** Filter by session utm_source */
+  utmSource: Array<string> | null | undefined;
+  /** Filter by session utm_term */
+  utmTerm: Array<string> | null | undefined;
 };
 
+export type FunnelInput = {
+  name: string;
+  /** Ordered steps, between 2 and 8 */
+  steps: Array<FunnelStepInput>;
+};
+
+export type FunnelStepInput = {
+  type: FunnelStepType;
+  value: string;
+};
+
+export type FunnelStepType =
+  | 'EVENT'
+  | 'PAGE';
+
 export type GeoIpState =
   | 'DISABLED'
   | 'DOWNLOADING'
@@ -65,6 +110,16 @@ export type GeoIpState =
   | 'MISSING'
   | 'READY';
 
+export type GoalInput = {
+  name: string;
+  type: GoalType;
+  value: string;
+};
+
+export type GoalType =
+  | 'EVENT'
+  | 'PAGE';
+
 export type LoginInput = {
   password: string;
   username: string;
@@ -82,16 +137,20 @@ export type RegisterInput = {
 
 export type TimeBucket =
   | 'DAILY'
-  | 'HOURLY';
+  | 'HOURLY'
+  | 'MONTHLY'
+  | 'WEEKLY';
 
 export type UpdateSiteInput = {
   /** Full list of blocked country codes */
   blockedCountries: Array<string> | null | undefined;
-  /** Full list of blocked IPs */
+  /** Full list of blocked IPs and CIDR ranges */
   blockedIPs: Array<string> | null | undefined;
   /** Full list of tracked domains (includes primary) */
   domains: Array<string> | null | undefined;
   name: string;
+  /** IANA timezone used for dashboard days and hours */
+  timezone: string | null | undefined;
   trackCountry: boolean | null | undefined;
 };
 
@@ -8,6 +8,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// Site timezones must resolve in minimal images that ship without a zoneinfo database.
+	_ "time/tzdata"
 
 	"github.com/lovely-eye/server/internal/app"
 	"github.com/lovely-eye/server/internal/platform/config"
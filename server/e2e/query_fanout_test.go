@@ -65,6 +65,7 @@ func TestDashboardQueryFanOut(t *testing.T) {
 	require.NoError(t, err)
 
 	queryCount := counter.count.Load()
-	require.LessOrEqual(t, queryCount, int64(11))
+	// One of the queries checks that the browser session behind the access token is still active.
+	require.LessOrEqual(t, queryCount, int64(12))
 	t.Logf("dashboard GraphQL operation executed %d SQL queries", queryCount)
 }
@@ -3,7 +3,7 @@ package analytics
 import (
 	"errors"
 	"log/slog"
-	"net"
+	"net/netip"
 	"net/url"
 	"strings"
 
@@ -70,13 +70,13 @@ func (s *Service) isIPBlocked(blocked []*site.BlockedIP, ip string) bool {
 	if len(blocked) == 0 {
 		return false
 	}
-	parsed := net.ParseIP(strings.TrimSpace(ip))
-	if parsed == nil {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
 		return false
 	}
-	normalized := parsed.String()
+	addr = addr.WithZone("").Unmap()
 	for _, entry := range blocked {
-		if entry != nil && entry.IP == normalized {
+		if entry != nil && entry.Prefix.Contains(addr) {
 			return true
 		}
 	}
@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lovely-eye/server/internal/event"
+	"github.com/lovely-eye/server/internal/site"
+	"github.com/uptrace/bun"
+)
+
+// Visitor is the client that every item of a batch belongs to.
+type Visitor struct {
+	UserAgent string
+	IP        string
+	Origin    string
+	Referer   string
+}
+
+// BatchItem is a page view, or a custom event when Name is set.
+type BatchItem struct {
+	Name        string
+	Path        string
+	Properties  string
+	Referrer    string
+	Exit        bool
+	UTMSource   string
+	UTMMedium   string
+	UTMCampaign string
+	UTMTerm     string
+	UTMContent  string
+	// ViewportWidth is the page view's CollectInput.ViewportWidth.
+	ViewportWidth int
+}
+
+type batchEvent struct {
+	definition     *event.Definition
+	sanitizedProps string
+}
+
+// CollectBatchForSite records a visitor's page views and events in one transaction, applying the
+// analytics bot, origin, and blocking rules once for the whole batch. It reports which items
+// were accepted; events without a matching definition are not.
+func (s *Service) CollectBatchForSite(ctx context.Context, resolvedSite *site.Site, visitor Visitor, items []BatchItem) ([]bool, error) {
+	if !s.acceptsAnalyticsRequest(resolvedSite, visitor.UserAgent, visitor.Origin, visitor.Referer, visitor.IP) {
+		return make([]bool, len(items)), nil
+	}
+	return s.collectAcceptedBatch(ctx, resolvedSite, visitor, items)
+}
+
+// CollectServerBatch is the batch form of CollectServerPageView and CollectServerEvent.
+func (s *Service) CollectServerBatch(ctx context.Context, resolvedSite *site.Site, visitor Visitor, items []BatchItem) ([]bool, error) {
+	if !s.acceptsServerRequest(resolvedSite, visitor.UserAgent, visitor.IP) {
+		return make([]bool, len(items)), nil
+	}
+	return s.collectAcceptedBatch(ctx, resolvedSite, visitor, items)
+}
+
+func (s *Service) collectAcceptedBatch(ctx context.Context, site *site.Site, visitor Visitor, items []BatchItem) ([]bool, error) {
+	events, err := s.batchEvents(ctx, site.ID, visitor, items)
+	if err != nil {
+		return make([]bool, len(items)), err
+	}
+
+	trackCountry := false
+	viewportWidth := 0
+	for _, item := range items {
+		trackCountry = trackCountry || item.Name != "" || !item.Exit
+		if viewportWidth == 0 && item.Name == "" {
+			viewportWidth = item.ViewportWidth
+		}
+	}
+	// Every item shares the visitor's client, so the first reported viewport sets its screen size.
+	dimensions := parseClientDimensions(visitor.UserAgent, viewportWidth)
+	now := s.now()
+	nowUnix := now.Unix()
+	country := s.collectCountry(site, visitor.IP, trackCountry)
+
+	accepted := make([]bool, len(items))
+	if err := s.analyticsRepo.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		for i, item := range items {
+			if item.Name == "" {
+				input := item.collectInput(visitor)
+				if err := s.collectPageViewTx(ctx, tx, site.ID, input, dimensions, country, now, nowUnix); err != nil {
+					return err
+				}
+				accepted[i] = true
+				continue
+			}
+			prepared, ok := events[i]
+			if !ok {
+				continue
+			}
+			input := item.eventInput(visitor)
+			if err := s.collectEventTx(ctx, tx, site.ID, input, dimensions, country, now, nowUnix, prepared.definition, prepared.sanitizedProps); err != nil {
+				return err
+			}
+			accepted[i] = true
+		}
+		return nil
+	}); err != nil {
+		// The transaction rolled back, so no item of the batch was recorded.
+		return make([]bool, len(items)), fmt.Errorf("collect batch transaction: %w", err)
+	}
+	return accepted, nil
+}
+
+// batchEvents resolves event definitions before the transaction starts, keyed by item index.
+func (s *Service) batchEvents(ctx context.Context, siteID int64, visitor Visitor, items []BatchItem) (map[int]batchEvent, error) {
+	events := make(map[int]batchEvent)
+	if s.eventDefinitionStore == nil {
+		return events, nil
+	}
+	for i, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		definition, sanitizedProps, ok, err := s.eventDefinitionForCollect(ctx, siteID, item.eventInput(visitor))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			events[i] = batchEvent{definition: definition, sanitizedProps: sanitizedProps}
+		}
+	}
+	return events, nil
+}
+
+func (item BatchItem) collectInput(visitor Visitor) CollectInput {
+	return CollectInput{
+		Path:          item.Path,
+		Exit:          item.Exit,
+		Referrer:      item.Referrer,
+		UserAgent:     visitor.UserAgent,
+		IP:            visitor.IP,
+		Origin:        visitor.Origin,
+		Referer:       visitor.Referer,
+		UTMSource:     item.UTMSource,
+		UTMMedium:     item.UTMMedium,
+		UTMCampaign:   item.UTMCampaign,
+		UTMTerm:       item.UTMTerm,
+		UTMContent:    item.UTMContent,
+		ViewportWidth: item.ViewportWidth,
+	}
+}
+
+func (item BatchItem) eventInput(visitor Visitor) EventInput {
+	return EventInput{
+		Name:       item.Name,
+		Path:       item.Path,
+		Properties: item.Properties,
+		UserAgent:  visitor.UserAgent,
+		IP:         visitor.IP,
+		Origin:     visitor.Origin,
+		Referer:    visitor.Referer,
+	}
+}
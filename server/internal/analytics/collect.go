@@ -47,8 +47,18 @@ func (s *Service) CollectPageViewForSite(ctx context.Context, resolvedSite *site
 	return s.collectAcceptedPageView(ctx, resolvedSite, input)
 }
 
+// CollectServerPageView records a page view that a trusted backend submitted on behalf of a visitor.
+// The caller authenticated with an ingestion key, so the origin check is skipped, while bot and
+// blocking rules, deduplication, and sessionization still apply to the visitor's IP and user agent.
+func (s *Service) CollectServerPageView(ctx context.Context, resolvedSite *site.Site, input CollectInput) error {
+	if !s.acceptsServerRequest(resolvedSite, input.UserAgent, input.IP) {
+		return nil
+	}
+	return s.collectAcceptedPageView(ctx, resolvedSite, input)
+}
+
 func (s *Service) collectAcceptedPageView(ctx context.Context, site *site.Site, input CollectInput) error {
-	dimensions := parseClientDimensions(input.UserAgent)
+	dimensions := parseClientDimensions(input.UserAgent, input.ViewportWidth)
 	now := s.now()
 	nowUnix := now.Unix()
 	country := s.collectCountry(site, input.IP, !input.Exit)
@@ -87,19 +97,29 @@ func (s *Service) acceptsAnalyticsRequest(site *site.Site, userAgent, origin, re
 	return !s.botDetector.IsBot(userAgent) && s.acceptsSiteRequest(site, origin, referer, ip)
 }
 
+func (s *Service) acceptsServerRequest(site *site.Site, userAgent, ip string) bool {
+	return site != nil && !s.botDetector.IsBot(userAgent) && !s.isBlockedRequest(site, ip)
+}
+
 func (s *Service) acceptsSiteRequest(site *site.Site, origin, referer, ip string) bool {
 	return site != nil &&
 		IsAllowedDomain(origin, referer, site.Domains) &&
 		!s.isBlockedRequest(site, ip)
 }
 
-func parseClientDimensions(userAgent string) clientDimensions {
+// parseClientDimensions derives the client dimensions from the user agent and, when the tracker
+// reported one, the viewport width. A zero width leaves the screen size unknown.
+func parseClientDimensions(userAgent string, viewportWidth int) clientDimensions {
 	ua := useragent.Parse(userAgent)
+	screenSize := analyticspersistence.ClientScreenSizeUnknown
+	if viewportWidth > 0 {
+		screenSize = categorizeScreenSize(viewportWidth)
+	}
 	return clientDimensions{
 		device:     categorizeDevice(ua),
 		browser:    normalizeBrowser(ua),
 		os:         normalizeOS(ua),
-		screenSize: analyticspersistence.ClientScreenSizeUnknown,
+		screenSize: screenSize,
 	}
 }
 
@@ -220,6 +240,7 @@ func (s *Service) createPageViewSessionTx(
 	if input.Exit {
 		return nil, false, nil
 	}
+	referrerHost := normalizeReferrerHost(input.Referrer)
 	session := &analyticspersistence.Session{
 		SiteID:        siteID,
 		ClientID:      clientID,
@@ -232,9 +253,13 @@ func (s *Service) createPageViewSessionTx(
 		ExitDay:       nowUnix / 86400,
 		ExitPath:      input.Path,
 		Referrer:      input.Referrer,
+		ReferrerHost:  referrerHost,
+		Channel:       classifyChannel(referrerHost, input.UTMSource, input.UTMMedium),
 		UTMSource:     input.UTMSource,
 		UTMMedium:     input.UTMMedium,
 		UTMCampaign:   input.UTMCampaign,
+		UTMTerm:       input.UTMTerm,
+		UTMContent:    input.UTMContent,
 		Duration:      0,
 		PageViewCount: 1,
 	}
@@ -376,6 +401,15 @@ func (s *Service) CollectEventForSite(ctx context.Context, resolvedSite *site.Si
 	return s.collectAcceptedEvent(ctx, resolvedSite, input)
 }
 
+// CollectServerEvent records a custom event that a trusted backend submitted on behalf of a visitor,
+// applying the same rules as CollectServerPageView.
+func (s *Service) CollectServerEvent(ctx context.Context, resolvedSite *site.Site, input EventInput) error {
+	if s.eventDefinitionStore == nil || !s.acceptsServerRequest(resolvedSite, input.UserAgent, input.IP) {
+		return nil
+	}
+	return s.collectAcceptedEvent(ctx, resolvedSite, input)
+}
+
 func (s *Service) collectAcceptedEvent(ctx context.Context, site *site.Site, input EventInput) error {
 	definition, sanitizedProps, ok, err := s.eventDefinitionForCollect(ctx, site.ID, input)
 	if err != nil {
@@ -385,7 +419,7 @@ func (s *Service) collectAcceptedEvent(ctx context.Context, site *site.Site, inp
 		return nil
 	}
 
-	dimensions := parseClientDimensions(input.UserAgent)
+	dimensions := parseClientDimensions(input.UserAgent, 0)
 	now := s.now()
 	nowUnix := now.Unix()
 	country := s.collectCountry(site, input.IP, true)
@@ -404,14 +438,28 @@ func (s *Service) eventDefinitionForCollect(
 	siteID int64,
 	input EventInput,
 ) (*event.Definition, string, bool, error) {
+	builtinFields, builtin := event.BuiltinFields(input.Name)
 	definition, err := s.eventDefinitionStore.GetByName(ctx, siteID, input.Name)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, "", false, nil
+		if !builtin {
+			return nil, "", false, nil
+		}
+		// Built-in events get their definition on first use, so they appear in event counts like any other.
+		definition, err = s.eventDefinitionStore.Upsert(ctx, siteID, input.Name, builtinFields)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("create built-in event definition: %w", err)
+		}
 	}
 	if err != nil {
 		return nil, "", false, fmt.Errorf("get event definition by name: %w", err)
 	}
-	sanitizedProps, ok, err := sanitizeEventProperties(input.Properties, definition.Fields)
+	fields := definition.Fields
+	if builtin {
+		// A definition stored before the name became reserved may carry other fields; the tracker
+		// sends the built-in ones, so only those decide whether the event is accepted.
+		fields = builtinFields
+	}
+	sanitizedProps, ok, err := sanitizeEventProperties(input.Properties, fields)
 	if err != nil {
 		return nil, "", false, fmt.Errorf("sanitize event properties: %w", err)
 	}
@@ -505,9 +553,13 @@ func (s *Service) createEventSessionTx(
 		ExitDay:       nowUnix / 86400,
 		ExitPath:      entryPath,
 		Referrer:      "",
+		ReferrerHost:  "",
+		Channel:       analyticspersistence.SessionChannelDirect,
 		UTMSource:     "",
 		UTMMedium:     "",
 		UTMCampaign:   "",
+		UTMTerm:       "",
+		UTMContent:    "",
 		Duration:      0,
 		PageViewCount: 0,
 	}
@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OverviewComparison pairs the overview of the selected range with a comparison range.
+type OverviewComparison struct {
+	Current  Overview
+	Previous Overview
+	Deltas   OverviewDeltas
+}
+
+type OverviewDeltas struct {
+	Visitors    MetricDelta
+	PageViews   MetricDelta
+	Sessions    MetricDelta
+	BounceRate  MetricDelta
+	AvgDuration MetricDelta
+}
+
+// MetricDelta is the change from the comparison value to the current one.
+// PercentChange is nil when the comparison value is zero.
+type MetricDelta struct {
+	Change        float64
+	PercentChange *float64
+}
+
+// TimeSeriesComparison pairs a bucket with the bucket at the same position in the comparison range.
+type TimeSeriesComparison struct {
+	TimeSeriesStats
+	Comparison TimeSeriesStats
+}
+
+// PreviousPeriod returns the range of equal length that ends just before from.
+func PreviousPeriod(from, to time.Time) (time.Time, time.Time) {
+	previousTo := from.Add(-time.Second)
+	return previousTo.Add(-to.Sub(from)), previousTo
+}
+
+func (s *Service) CompareDashboardOverviewWithFilter(
+	ctx context.Context,
+	query Query,
+	compareFrom,
+	compareTo time.Time,
+) (*OverviewComparison, error) {
+	current, err := s.GetDashboardOverviewWithFilter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	previousQuery := query
+	previousQuery.From = compareFrom
+	previousQuery.To = compareTo
+	previous, err := s.GetDashboardOverviewWithFilter(ctx, previousQuery)
+	if err != nil {
+		return nil, fmt.Errorf("get comparison overview: %w", err)
+	}
+
+	return &OverviewComparison{
+		Current:  *current,
+		Previous: *previous,
+		Deltas: OverviewDeltas{
+			Visitors:    metricDelta(float64(current.Visitors), float64(previous.Visitors)),
+			PageViews:   metricDelta(float64(current.PageViews), float64(previous.PageViews)),
+			Sessions:    metricDelta(float64(current.Sessions), float64(previous.Sessions)),
+			BounceRate:  metricDelta(current.BounceRate, previous.BounceRate),
+			AvgDuration: metricDelta(current.AvgDuration, previous.AvgDuration),
+		},
+	}, nil
+}
+
+// CompareTimeSeriesStatsWithFilter returns the requested page of buckets, each paired with
+// the bucket at the same offset from compareFrom; missing comparison buckets are zero.
+func (s *Service) CompareTimeSeriesStatsWithFilter(
+	ctx context.Context,
+	query Query,
+	compareFrom,
+	compareTo time.Time,
+) ([]TimeSeriesComparison, error) {
+	current, err := s.GetTimeSeriesStatsWithFilter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	previousQuery := query
+	previousQuery.From = compareFrom
+	previousQuery.To = compareTo
+	previousQuery.Limit = 0
+	previousQuery.Offset = 0
+	previous, err := s.GetTimeSeriesStatsWithFilter(ctx, previousQuery)
+	if err != nil {
+		return nil, fmt.Errorf("get comparison time series: %w", err)
+	}
+
+	previousByBucket := make(map[int64]TimeSeriesStats, len(previous))
+	for _, stat := range previous {
+		previousByBucket[stat.DateBucket] = stat
+	}
+	shift := localTimeBucket(compareFrom, query.Bucket, query.Location) - localTimeBucket(query.From, query.Bucket, query.Location)
+
+	result := make([]TimeSeriesComparison, 0, len(current))
+	for _, stat := range current {
+		comparisonBucket := stat.DateBucket + shift
+		comparison, ok := previousByBucket[comparisonBucket]
+		if !ok {
+			comparison = TimeSeriesStats{
+				DateBucket: comparisonBucket,
+				Date:       timeBucketStart(comparisonBucket, query.Bucket, query.Location),
+			}
+		}
+		result = append(result, TimeSeriesComparison{TimeSeriesStats: stat, Comparison: comparison})
+	}
+	return result, nil
+}
+
+func metricDelta(current, previous float64) MetricDelta {
+	delta := MetricDelta{Change: current - previous}
+	if previous != 0 {
+		percent := (current - previous) / previous * 100
+		delta.PercentChange = &percent
+	}
+	return delta
+}
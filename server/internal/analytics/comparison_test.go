@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	sitepersistence "github.com/lovely-eye/server/internal/site/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviousPeriodEndsBeforeRangeStarts(t *testing.T) {
+	from := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 14, 23, 59, 59, 0, time.UTC)
+
+	previousFrom, previousTo := PreviousPeriod(from, to)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), previousFrom)
+	require.Equal(t, time.Date(2026, 3, 7, 23, 59, 59, 0, time.UTC), previousTo)
+}
+
+func TestMetricDelta(t *testing.T) {
+	delta := metricDelta(150, 100)
+	require.InDelta(t, 50, delta.Change, 0.001)
+	require.NotNil(t, delta.PercentChange)
+	require.InDelta(t, 50, *delta.PercentChange, 0.001)
+
+	delta = metricDelta(3, 0)
+	require.InDelta(t, 3, delta.Change, 0.001)
+	require.Nil(t, delta.PercentChange)
+}
+
+func TestCompareDashboardWithPreviousPeriod(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	site := createAnalyticsIdentitySite(t, db)
+	repo := analyticspersistence.New(db)
+	service := NewService(repo, sitepersistence.New(db), eventpersistence.New(db), nil, nil, testAnalyticsIdentitySecret)
+
+	from := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 9, 23, 59, 59, 0, time.UTC)
+	visit := func(hash string, at time.Time) {
+		client := &analyticspersistence.Client{SiteID: site.ID, Hash: hash}
+		_, err := db.NewInsert().Model(client).Exec(ctx)
+		require.NoError(t, err)
+		enter := at.Unix()
+		require.NoError(t, repo.CreateSession(ctx, &analyticspersistence.Session{
+			SiteID: site.ID, ClientID: client.ID,
+			EnterTime: enter, EnterHour: enter / 3600, EnterDay: enter / 86400, EnterPath: "/",
+			ExitTime: enter, ExitHour: enter / 3600, ExitDay: enter / 86400, ExitPath: "/",
+			PageViewCount: 1,
+		}))
+	}
+	visit("current-1", from.Add(2*time.Hour))
+	visit("current-2", from.Add(26*time.Hour))
+	visit("current-3", from.Add(27*time.Hour))
+	visit("previous-1", from.Add(-46*time.Hour))
+	visit("previous-2", from.Add(-20*time.Hour))
+
+	query := Query{SiteID: site.ID, From: from, To: to, Bucket: TimeBucketDaily}
+	compareFrom, compareTo := PreviousPeriod(from, to)
+	overview, err := service.CompareDashboardOverviewWithFilter(ctx, query, compareFrom, compareTo)
+	require.NoError(t, err)
+	require.Equal(t, 3, overview.Current.Visitors)
+	require.Equal(t, 2, overview.Previous.Visitors)
+	require.InDelta(t, 1, overview.Deltas.Visitors.Change, 0.001)
+	require.InDelta(t, 50, *overview.Deltas.Visitors.PercentChange, 0.001)
+
+	series, err := service.CompareTimeSeriesStatsWithFilter(ctx, query, compareFrom, compareTo)
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	require.Equal(t, from.Unix()/86400, series[0].DateBucket)
+	require.Equal(t, 1, series[0].Visitors)
+	require.Equal(t, compareFrom.Unix()/86400, series[0].Comparison.DateBucket)
+	require.Equal(t, 1, series[0].Comparison.Visitors)
+	require.Equal(t, 2, series[1].Visitors)
+	require.Equal(t, 1, series[1].Comparison.Visitors)
+}
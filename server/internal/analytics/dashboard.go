@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/goal"
 )
 
 func buildAnalyticsQuery(siteID int64, from, to time.Time, filter Filter) Query {
@@ -122,6 +126,44 @@ func (s *Service) GetTopPagesWithFilterPaged(
 	return pageStats(stats), total, nil
 }
 
+func (s *Service) GetEntryPagesWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]EntryPageStats, int, error) {
+	stats, total, err := s.analyticsRepo.GetEntryPagesWithFilterPaged(ctx, repositoryAnalyticsQuery(query))
+	if err != nil {
+		return nil, 0, fmt.Errorf("get entry pages with filter paged: %w", err)
+	}
+	return entryPageStats(stats), total, nil
+}
+
+func (s *Service) GetExitPagesWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]ExitPageStats, int, error) {
+	stats, total, err := s.analyticsRepo.GetExitPagesWithFilterPaged(ctx, repositoryAnalyticsQuery(query))
+	if err != nil {
+		return nil, 0, fmt.Errorf("get exit pages with filter paged: %w", err)
+	}
+	return exitPageStats(stats), total, nil
+}
+
+func (s *Service) GetUTMStatsWithFilterPaged(
+	ctx context.Context,
+	query Query,
+	dimension UTMDimension,
+) ([]UTMStats, int, error) {
+	stats, total, err := s.analyticsRepo.GetUTMStatsWithFilterPaged(
+		ctx,
+		repositoryAnalyticsQuery(query),
+		analyticspersistence.UTMDimension(dimension),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get utm stats with filter paged: %w", err)
+	}
+	return utmStats(stats), total, nil
+}
+
 func (s *Service) GetTopReferrersWithFilterPaged(
 	ctx context.Context,
 	query Query,
@@ -133,6 +175,28 @@ func (s *Service) GetTopReferrersWithFilterPaged(
 	return referrerStats(stats), total, nil
 }
 
+func (s *Service) GetReferrerDomainsWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]ReferrerStats, int, error) {
+	stats, total, err := s.analyticsRepo.GetReferrerDomainsWithFilterPaged(ctx, repositoryAnalyticsQuery(query))
+	if err != nil {
+		return nil, 0, fmt.Errorf("get referrer domains with filter paged: %w", err)
+	}
+	return referrerStats(stats), total, nil
+}
+
+func (s *Service) GetChannelStatsWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]ChannelStats, int, int, error) {
+	stats, total, totalVisitors, err := s.analyticsRepo.GetChannelStatsWithFilterPaged(ctx, repositoryAnalyticsQuery(query))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get channel stats with filter paged: %w", err)
+	}
+	return channelStats(stats), total, totalVisitors, nil
+}
+
 func (s *Service) GetDeviceStatsWithFilterPaged(
 	ctx context.Context,
 	query Query,
@@ -158,6 +222,17 @@ func (s *Service) GetOperatingSystemStatsWithFilterPaged(
 	return operatingSystemStats(stats), total, totalVisitors, nil
 }
 
+func (s *Service) GetScreenSizeStatsWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]ScreenSizeStats, int, int, error) {
+	stats, total, totalVisitors, err := s.analyticsRepo.GetScreenSizeStatsWithFilterPaged(ctx, repositoryAnalyticsQuery(query))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get screen size stats with filter paged: %w", err)
+	}
+	return screenSizeStats(stats), total, totalVisitors, nil
+}
+
 func (s *Service) GetCountryStatsWithFilterPaged(
 	ctx context.Context,
 	query Query,
@@ -169,6 +244,41 @@ func (s *Service) GetCountryStatsWithFilterPaged(
 	return countryStats(stats), total, totalVisitors, nil
 }
 
+// GetGoalStatsWithFilterPaged reports conversion rates against the unique visitors matching the same query.
+func (s *Service) GetGoalStatsWithFilterPaged(
+	ctx context.Context,
+	query Query,
+) ([]GoalStats, int, int, error) {
+	repositoryQuery := repositoryAnalyticsQuery(query)
+	stats, total, err := s.analyticsRepo.GetGoalStatsWithFilterPaged(
+		ctx,
+		repositoryQuery,
+		int8(goal.TypePage),
+		int8(goal.TypeEvent),
+	)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get goal stats with filter paged: %w", err)
+	}
+	visitors, err := s.analyticsRepo.GetVisitorCountWithFilter(ctx, repositoryQuery)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get visitor count with filter: %w", err)
+	}
+	return goalStats(query.SiteID, stats, visitors), total, visitors, nil
+}
+
+// GetFunnelStatsWithFilter reports visitors per funnel step for sessions entered within the query range.
+func (s *Service) GetFunnelStatsWithFilter(
+	ctx context.Context,
+	query Query,
+	steps []funnel.Step,
+) ([]FunnelStepStats, error) {
+	visitors, err := s.analyticsRepo.GetFunnelStepVisitors(ctx, repositoryAnalyticsQuery(query), funnelSteps(steps))
+	if err != nil {
+		return nil, fmt.Errorf("get funnel step visitors: %w", err)
+	}
+	return funnelStepStats(steps, visitors), nil
+}
+
 func (s *Service) GetBrowserStatsWithFilter(
 	ctx context.Context,
 	query Query,
@@ -188,7 +298,7 @@ func (s *Service) GetTimeSeriesStatsWithFilter(
 	if err != nil {
 		return nil, fmt.Errorf("get time series stats with filter: %w", err)
 	}
-	return timeSeriesStats(stats), nil
+	return timeSeriesStats(stats, query.Bucket, query.Location), nil
 }
 
 func (s *Service) GetRealtimeVisitors(ctx context.Context, siteID int64) (int, error) {
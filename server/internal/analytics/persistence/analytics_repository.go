@@ -14,15 +14,23 @@ type Repository struct {
 
 type AnalyticsFilter struct {
 	Referrer           []string
+	ReferrerDomain     []string
+	Channel            []string
 	Browser            []string
 	Device             []string
 	OS                 []string
+	ScreenSize         []string
 	Page               []string
 	Country            []string
 	EventTypes         []EventType
 	EventName          []string
 	EventPath          []string
 	EventDefinitionIDs []int64
+	UTMSource          []string
+	UTMMedium          []string
+	UTMCampaign        []string
+	UTMTerm            []string
+	UTMContent         []string
 }
 
 type EventType string
@@ -39,7 +47,9 @@ type AnalyticsQuery struct {
 	Limit  int
 	Offset int
 	Bucket TimeBucket
-	Filter AnalyticsFilter
+	// Location selects the wall clock for time series buckets; nil means UTC.
+	Location *time.Location
+	Filter   AnalyticsFilter
 }
 
 func New(db *bun.DB) *Repository {
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+func TestEntryAndExitPagesGroupSessionsByBoundaryPaths(t *testing.T) {
+	db := setupTestDB(t)
+	repo := New(db)
+	site := createTestSite(t, db)
+	now := time.Now().UTC()
+
+	reader := createTestClient(t, db, site.ID, "entry-reader", "desktop", "chrome", "linux")
+	readerVisit := insertSessionWithPath(t, db, site.ID, reader, "/", now.Add(-3*time.Hour), 120, 2)
+	setSessionExitPath(t, db, readerVisit, "/blog")
+	insertPageViewEvent(t, db, readerVisit, "/", now.Add(-3*time.Hour))
+	insertPageViewEvent(t, db, readerVisit, "/blog", now.Add(-3*time.Hour+time.Minute))
+
+	bouncer := createTestClient(t, db, site.ID, "entry-bouncer", "mobile", "safari", "ios")
+	bounceVisit := insertSessionWithPath(t, db, site.ID, bouncer, "/", now.Add(-2*time.Hour), 0, 1)
+	insertPageViewEvent(t, db, bounceVisit, "/", now.Add(-2*time.Hour))
+
+	blogReader := createTestClient(t, db, site.ID, "entry-blog", "desktop", "firefox", "windows")
+	blogVisit := insertSessionWithPath(t, db, site.ID, blogReader, "/blog", now.Add(-time.Hour), 120, 2)
+	setSessionExitPath(t, db, blogVisit, "/pricing")
+	insertPageViewEvent(t, db, blogVisit, "/blog", now.Add(-time.Hour))
+	insertPageViewEvent(t, db, blogVisit, "/pricing", now.Add(-time.Hour+time.Minute))
+
+	query := AnalyticsQuery{
+		SiteID: site.ID,
+		From:   now.Add(-24 * time.Hour),
+		To:     now,
+		Limit:  10,
+	}
+	entries, total, err := repo.GetEntryPagesWithFilterPaged(context.Background(), query)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, []EntryPageStats{
+		{Path: "/", Sessions: 2, Visitors: 2, Bounces: 1, Total: 2},
+		{Path: "/blog", Sessions: 1, Visitors: 1, Bounces: 0, Total: 2},
+	}, entries)
+
+	exits, total, err := repo.GetExitPagesWithFilterPaged(context.Background(), query)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []ExitPageStats{
+		{Path: "/", Exits: 1, Visitors: 1, Views: 2, Total: 3},
+		{Path: "/blog", Exits: 1, Visitors: 1, Views: 2, Total: 3},
+		{Path: "/pricing", Exits: 1, Visitors: 1, Views: 1, Total: 3},
+	}, exits)
+
+	query.Filter = AnalyticsFilter{Device: []string{"mobile"}}
+	exits, total, err = repo.GetExitPagesWithFilterPaged(context.Background(), query)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []ExitPageStats{{Path: "/", Exits: 1, Visitors: 1, Views: 1, Total: 1}}, exits)
+
+	query.Filter = AnalyticsFilter{}
+	query.Offset = 99
+	entries, total, err = repo.GetEntryPagesWithFilterPaged(context.Background(), query)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, 2, total)
+}
+
+func setSessionExitPath(t *testing.T, db *bun.DB, sessionID int64, path string) {
+	t.Helper()
+
+	_, err := db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("exit_path = ?", path).
+		Where("id = ?", sessionID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
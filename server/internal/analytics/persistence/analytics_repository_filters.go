@@ -51,9 +51,29 @@ func applySessionFilters(q *bun.SelectQuery, filter AnalyticsFilter) *bun.Select
 
 		q = q.Where("s.referrer IN (?)", bun.List(filter.Referrer))
 	}
+	if len(filter.ReferrerDomain) > 0 {
+		q = q.Where("COALESCE(s.referrer_host, '') IN (?)", bun.List(filter.ReferrerDomain))
+	}
+	q = applyEnumFilter(q, filter.Channel, ParseSessionChannelFilters, "s.channel IN (?)")
+	if len(filter.UTMSource) > 0 {
+		q = q.Where("s.utm_source IN (?)", bun.List(filter.UTMSource))
+	}
+	if len(filter.UTMMedium) > 0 {
+		q = q.Where("s.utm_medium IN (?)", bun.List(filter.UTMMedium))
+	}
+	if len(filter.UTMCampaign) > 0 {
+		q = q.Where("s.utm_campaign IN (?)", bun.List(filter.UTMCampaign))
+	}
+	if len(filter.UTMTerm) > 0 {
+		q = q.Where("s.utm_term IN (?)", bun.List(filter.UTMTerm))
+	}
+	if len(filter.UTMContent) > 0 {
+		q = q.Where("s.utm_content IN (?)", bun.List(filter.UTMContent))
+	}
 	q = applyEnumFilter(q, filter.Browser, ParseClientBrowserFilters, "s.client_id IN (SELECT id FROM clients WHERE browser IN (?))")
 	q = applyEnumFilter(q, filter.Device, ParseClientDeviceFilters, "s.client_id IN (SELECT id FROM clients WHERE device IN (?))")
 	q = applyEnumFilter(q, filter.OS, ParseClientOSFilters, "s.client_id IN (SELECT id FROM clients WHERE os IN (?))")
+	q = applyEnumFilter(q, filter.ScreenSize, ParseClientScreenSizeFilters, "s.client_id IN (SELECT id FROM clients WHERE screen_size IN (?))")
 	if len(filter.Page) > 0 {
 		q = q.Where("s.id IN (SELECT DISTINCT session_id FROM events WHERE definition_id IS NULL AND path IN (?))", bun.List(filter.Page))
 	}
@@ -93,14 +113,34 @@ func applyEventFilters(q *bun.SelectQuery, filter AnalyticsFilter) *bun.SelectQu
 	if len(filter.Page) > 0 {
 		q = q.Where("e.path IN (?)", bun.List(filter.Page))
 	}
-	if len(filter.Referrer) > 0 || len(filter.Browser) > 0 || len(filter.Device) > 0 || len(filter.OS) > 0 || len(filter.Country) > 0 || len(filter.EventTypes) > 0 || len(filter.EventName) > 0 || len(filter.EventPath) > 0 || len(filter.EventDefinitionIDs) > 0 {
+	if len(filter.Referrer) > 0 || len(filter.ReferrerDomain) > 0 || len(filter.Channel) > 0 || len(filter.Browser) > 0 || len(filter.Device) > 0 || len(filter.OS) > 0 || len(filter.ScreenSize) > 0 || len(filter.Country) > 0 || len(filter.EventTypes) > 0 || len(filter.EventName) > 0 || len(filter.EventPath) > 0 || len(filter.EventDefinitionIDs) > 0 || len(filter.UTMSource) > 0 || len(filter.UTMMedium) > 0 || len(filter.UTMCampaign) > 0 || len(filter.UTMTerm) > 0 || len(filter.UTMContent) > 0 {
 
 		if len(filter.Referrer) > 0 {
 			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE referrer IN (?))", bun.List(filter.Referrer))
 		}
+		if len(filter.ReferrerDomain) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE COALESCE(referrer_host, '') IN (?))", bun.List(filter.ReferrerDomain))
+		}
+		q = applyEnumFilter(q, filter.Channel, ParseSessionChannelFilters, "e.session_id IN (SELECT id FROM sessions WHERE channel IN (?))")
+		if len(filter.UTMSource) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE utm_source IN (?))", bun.List(filter.UTMSource))
+		}
+		if len(filter.UTMMedium) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE utm_medium IN (?))", bun.List(filter.UTMMedium))
+		}
+		if len(filter.UTMCampaign) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE utm_campaign IN (?))", bun.List(filter.UTMCampaign))
+		}
+		if len(filter.UTMTerm) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE utm_term IN (?))", bun.List(filter.UTMTerm))
+		}
+		if len(filter.UTMContent) > 0 {
+			q = q.Where("e.session_id IN (SELECT id FROM sessions WHERE utm_content IN (?))", bun.List(filter.UTMContent))
+		}
 		q = applyEnumFilter(q, filter.Browser, ParseClientBrowserFilters, "e.session_id IN (SELECT s.id FROM sessions s INNER JOIN clients c ON s.client_id = c.id WHERE c.browser IN (?))")
 		q = applyEnumFilter(q, filter.Device, ParseClientDeviceFilters, "e.session_id IN (SELECT s.id FROM sessions s INNER JOIN clients c ON s.client_id = c.id WHERE c.device IN (?))")
 		q = applyEnumFilter(q, filter.OS, ParseClientOSFilters, "e.session_id IN (SELECT s.id FROM sessions s INNER JOIN clients c ON s.client_id = c.id WHERE c.os IN (?))")
+		q = applyEnumFilter(q, filter.ScreenSize, ParseClientScreenSizeFilters, "e.session_id IN (SELECT s.id FROM sessions s INNER JOIN clients c ON s.client_id = c.id WHERE c.screen_size IN (?))")
 		if len(filter.Country) > 0 {
 			q = q.Where("e.session_id IN (SELECT s.id FROM sessions s INNER JOIN clients c ON s.client_id = c.id WHERE COALESCE(NULLIF(c.country, ''), '-') IN (?))", bun.List(normalizeCountryCodes(filter.Country)))
 		}
@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
+	"github.com/uptrace/bun"
+)
+
+type FunnelStep struct {
+	Type  funnelpersistence.StepType
+	Value string
+}
+
+// GetFunnelStepVisitors counts distinct visitors reaching each step in order within a single session.
+// Each step keeps the earliest matching event after the previous step, so later steps never look back in time.
+func (r *Repository) GetFunnelStepVisitors(ctx context.Context, query AnalyticsQuery, steps []FunnelStep) ([]int, error) {
+	visitors := make([]int, 0, len(steps))
+	var reached *bun.SelectQuery
+	for index, step := range steps {
+		q := r.db.NewSelect().
+			TableExpr("events e").
+			ColumnExpr("e.session_id")
+		if reached == nil {
+			q = q.Join("INNER JOIN sessions s ON s.id = e.session_id").
+				ColumnExpr("s.client_id").
+				Where("s.site_id = ?", query.SiteID).
+				Where("s.enter_time >= ?", query.From.Unix()).
+				Where("s.enter_time <= ?", query.To.Unix()).
+				Group("e.session_id", "s.client_id")
+			q = applySessionFilters(q, query.Filter)
+		} else {
+			q = q.Join("INNER JOIN (?) AS prev ON prev.session_id = e.session_id", reached).
+				ColumnExpr("prev.client_id").
+				Where("e.time >= prev.reached_at").
+				Group("e.session_id", "prev.client_id")
+		}
+		q = q.ColumnExpr("MIN(e.time) AS reached_at")
+		switch step.Type {
+		case funnelpersistence.StepTypeEvent:
+			q = q.Join("INNER JOIN event_definitions ed ON e.definition_id = ed.id").
+				Where("ed.name = ?", step.Value)
+		default:
+			q = q.Where("e.definition_id IS NULL").
+				Where("e.path = ?", step.Value)
+		}
+
+		var count int
+		err := r.db.NewSelect().
+			TableExpr("(?) AS reached", q).
+			ColumnExpr("COUNT(DISTINCT reached.client_id)").
+			Scan(ctx, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funnel step %d visitors: %w", index+1, err)
+		}
+		visitors = append(visitors, count)
+		reached = q
+	}
+	return visitors, nil
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFunnelStepVisitorsRequiresOrderedStepsWithinSession(t *testing.T) {
+	db := setupTestDB(t)
+	repo := New(db)
+	ctx := context.Background()
+	site := createTestSite(t, db)
+	now := time.Now().UTC()
+
+	definition := &eventpersistence.Definition{SiteID: site.ID, Name: "signup_completed"}
+	_, err := db.NewInsert().Model(definition).Exec(ctx)
+	require.NoError(t, err)
+	insertSignupEvent := func(sessionID int64, at time.Time) {
+		eventUnix := at.Unix()
+		_, err := db.NewInsert().Model(&Event{
+			SessionID:    sessionID,
+			Time:         eventUnix,
+			Hour:         eventUnix / 3600,
+			Day:          eventUnix / 86400,
+			Path:         "/signup",
+			DefinitionID: &definition.ID,
+		}).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	completed := createTestClient(t, db, site.ID, "funnel-completed", "mobile", "safari", "ios")
+	completedVisit := insertSessionWithPath(t, db, site.ID, completed, "/pricing", now.Add(-3*time.Hour), 120, 2)
+	insertPageViewEvent(t, db, completedVisit, "/pricing", now.Add(-3*time.Hour))
+	insertPageViewEvent(t, db, completedVisit, "/signup", now.Add(-3*time.Hour+time.Minute))
+	insertSignupEvent(completedVisit, now.Add(-3*time.Hour+2*time.Minute))
+
+	dropped := createTestClient(t, db, site.ID, "funnel-dropped", "desktop", "chrome", "linux")
+	droppedVisit := insertSessionWithPath(t, db, site.ID, dropped, "/pricing", now.Add(-2*time.Hour), 60, 2)
+	insertPageViewEvent(t, db, droppedVisit, "/pricing", now.Add(-2*time.Hour))
+	insertPageViewEvent(t, db, droppedVisit, "/signup", now.Add(-2*time.Hour+time.Minute))
+
+	outOfOrder := createTestClient(t, db, site.ID, "funnel-out-of-order", "desktop", "firefox", "windows")
+	outOfOrderVisit := insertSessionWithPath(t, db, site.ID, outOfOrder, "/signup", now.Add(-time.Hour), 60, 2)
+	insertPageViewEvent(t, db, outOfOrderVisit, "/signup", now.Add(-time.Hour))
+	insertPageViewEvent(t, db, outOfOrderVisit, "/pricing", now.Add(-time.Hour+time.Minute))
+
+	splitSessions := createTestClient(t, db, site.ID, "funnel-split", "desktop", "edge", "windows")
+	firstSplit := insertSessionWithPath(t, db, site.ID, splitSessions, "/pricing", now.Add(-50*time.Minute), 0, 1)
+	insertPageViewEvent(t, db, firstSplit, "/pricing", now.Add(-50*time.Minute))
+	secondSplit := insertSessionWithPath(t, db, site.ID, splitSessions, "/signup", now.Add(-10*time.Minute), 0, 1)
+	insertPageViewEvent(t, db, secondSplit, "/signup", now.Add(-10*time.Minute))
+
+	steps := []FunnelStep{
+		{Type: funnelpersistence.StepTypePage, Value: "/pricing"},
+		{Type: funnelpersistence.StepTypePage, Value: "/signup"},
+		{Type: funnelpersistence.StepTypeEvent, Value: "signup_completed"},
+	}
+	query := AnalyticsQuery{
+		SiteID: site.ID,
+		From:   now.Add(-24 * time.Hour),
+		To:     now,
+	}
+	visitors, err := repo.GetFunnelStepVisitors(ctx, query, steps)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 2, 1}, visitors)
+
+	query.Filter = AnalyticsFilter{Device: []string{"mobile"}}
+	visitors, err = repo.GetFunnelStepVisitors(ctx, query, steps)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 1, 1}, visitors)
+}
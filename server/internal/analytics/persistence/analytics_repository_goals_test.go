@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	goalpersistence "github.com/lovely-eye/server/internal/goal/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGoalStatsCountsDistinctConvertingVisitors(t *testing.T) {
+	db := setupTestDB(t)
+	repo := New(db)
+	ctx := context.Background()
+	site := createTestSite(t, db)
+	now := time.Now().UTC()
+
+	definition := &eventpersistence.Definition{SiteID: site.ID, Name: "signup_completed"}
+	_, err := db.NewInsert().Model(definition).Exec(ctx)
+	require.NoError(t, err)
+	goals := []*goalpersistence.Goal{
+		{SiteID: site.ID, Name: "Checkout", Type: goalpersistence.GoalTypePage, Value: "/checkout/thanks"},
+		{SiteID: site.ID, Name: "Signup", Type: goalpersistence.GoalTypeEvent, Value: "signup_completed"},
+		{SiteID: site.ID, Name: "Unreached", Type: goalpersistence.GoalTypePage, Value: "/never"},
+	}
+	_, err = db.NewInsert().Model(&goals).Exec(ctx)
+	require.NoError(t, err)
+
+	buyer := createTestClient(t, db, site.ID, "goal-buyer", "desktop", "chrome", "linux")
+	firstVisit := insertSessionWithPath(t, db, site.ID, buyer, "/pricing", now.Add(-3*time.Hour), 60, 2)
+	insertPageViewEvent(t, db, firstVisit, "/pricing", now.Add(-3*time.Hour))
+	insertPageViewEvent(t, db, firstVisit, "/checkout/thanks", now.Add(-3*time.Hour+time.Minute))
+	secondVisit := insertSessionWithPath(t, db, site.ID, buyer, "/checkout/thanks", now.Add(-2*time.Hour), 60, 1)
+	insertPageViewEvent(t, db, secondVisit, "/checkout/thanks", now.Add(-2*time.Hour))
+
+	signup := createTestClient(t, db, site.ID, "goal-signup", "mobile", "safari", "ios")
+	signupVisit := insertSessionWithPath(t, db, site.ID, signup, "/signup", now.Add(-time.Hour), 60, 1)
+	insertPageViewEvent(t, db, signupVisit, "/signup", now.Add(-time.Hour))
+	eventUnix := now.Add(-time.Hour + time.Minute).Unix()
+	_, err = db.NewInsert().Model(&Event{
+		SessionID:    signupVisit,
+		Time:         eventUnix,
+		Hour:         eventUnix / 3600,
+		Day:          eventUnix / 86400,
+		Path:         "/signup",
+		DefinitionID: &definition.ID,
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	bouncer := createTestClient(t, db, site.ID, "goal-bouncer", "desktop", "firefox", "windows")
+	bounce := insertSessionWithPath(t, db, site.ID, bouncer, "/", now.Add(-30*time.Minute), 0, 1)
+	insertPageViewEvent(t, db, bounce, "/", now.Add(-30*time.Minute))
+
+	pageGoalType := int8(goalpersistence.GoalTypePage)
+	eventGoalType := int8(goalpersistence.GoalTypeEvent)
+	query := AnalyticsQuery{
+		SiteID: site.ID,
+		From:   now.Add(-24 * time.Hour),
+		To:     now,
+		Limit:  10,
+	}
+	stats, total, err := repo.GetGoalStatsWithFilterPaged(ctx, query, pageGoalType, eventGoalType)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, stats, 3)
+	require.Equal(t, "Checkout", stats[0].Name)
+	require.Equal(t, 1, stats[0].Conversions)
+	require.Equal(t, "Signup", stats[1].Name)
+	require.Equal(t, eventGoalType, stats[1].Type)
+	require.Equal(t, 1, stats[1].Conversions)
+	require.Equal(t, "Unreached", stats[2].Name)
+	require.Equal(t, 0, stats[2].Conversions)
+
+	query.Filter = AnalyticsFilter{Device: []string{"mobile"}}
+	stats, _, err = repo.GetGoalStatsWithFilterPaged(ctx, query, pageGoalType, eventGoalType)
+	require.NoError(t, err)
+	require.Equal(t, "Signup", stats[0].Name)
+	require.Equal(t, 1, stats[0].Conversions)
+	require.Equal(t, 0, stats[1].Conversions)
+
+	query.Filter = AnalyticsFilter{}
+	query.Limit = 1
+	query.Offset = 99
+	stats, total, err = repo.GetGoalStatsWithFilterPaged(ctx, query, pageGoalType, eventGoalType)
+	require.NoError(t, err)
+	require.Empty(t, stats)
+	require.Equal(t, 3, total)
+}
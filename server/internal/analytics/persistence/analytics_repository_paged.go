@@ -108,6 +108,234 @@ func (r *Repository) GetTopReferrersWithFilterPaged(ctx context.Context, query A
 	return stats, total, nil
 }
 
+// GetReferrerDomainsWithFilterPaged groups sessions by normalized referrer host so one source is counted once across URLs.
+func (r *Repository) GetReferrerDomainsWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]ReferrerStats, int, error) {
+	var stats []ReferrerStats
+	var total int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		ColumnExpr("COALESCE(NULLIF(s.referrer_host, ''), '(direct)') as referrer").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("COUNT(*) OVER() as total").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix)
+	q = applySessionFilters(q, query.Filter)
+	q = q.GroupExpr("COALESCE(NULLIF(s.referrer_host, ''), '(direct)')")
+	err := q.Clone().
+		Order("visitors DESC", "referrer ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get referrer domains with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+	} else if query.Offset > 0 {
+		total, err = r.groupedRowCount(ctx, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get referrer domains total: %w", err)
+		}
+	}
+	return stats, total, nil
+}
+
+func (r *Repository) GetChannelStatsWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]ChannelStats, int, int, error) {
+	var stats []ChannelStats
+	var total int
+	var totalVisitors int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		ColumnExpr("s.channel").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("COUNT(*) OVER() as total").
+		ColumnExpr("SUM(COUNT(DISTINCT s.client_id)) OVER() as total_visitors").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where("s.channel != ?", SessionChannelUnknown)
+	q = applySessionFilters(q, query.Filter)
+	q = q.Group("s.channel")
+	err := q.Clone().
+		Order("visitors DESC", "s.channel ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get channel stats with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+		totalVisitors = stats[0].TotalVisitors
+	} else if query.Offset > 0 {
+		total, totalVisitors, err = r.groupedRowAndVisitorTotals(ctx, q)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to get channel stats totals: %w", err)
+		}
+	}
+	return stats, total, totalVisitors, nil
+}
+
+// GetEntryPagesWithFilterPaged groups sessions by their landing page; a bounced session viewed only that page.
+func (r *Repository) GetEntryPagesWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]EntryPageStats, int, error) {
+	var stats []EntryPageStats
+	var total int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		ColumnExpr("s.enter_path AS path").
+		ColumnExpr("COUNT(*) as sessions").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("SUM(CASE WHEN s.page_view_count = 1 THEN 1 ELSE 0 END) as bounces").
+		ColumnExpr("COUNT(*) OVER() as total").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where("s.page_view_count > 0")
+	q = applySessionFilters(q, query.Filter)
+	q = q.Group("s.enter_path")
+	err := q.Clone().
+		Order("sessions DESC", "path ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get entry pages with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+	} else if query.Offset > 0 {
+		total, err = r.groupedRowCount(ctx, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get entry pages total: %w", err)
+		}
+	}
+	return stats, total, nil
+}
+
+// GetExitPagesWithFilterPaged groups sessions by their last page and pairs each with its page views from the
+// same sessions, so the exit rate is the share of views of that page that ended a visit.
+func (r *Repository) GetExitPagesWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]ExitPageStats, int, error) {
+	var stats []ExitPageStats
+	var total int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	views := r.db.NewSelect().
+		TableExpr("events e").
+		Join("INNER JOIN sessions s ON e.session_id = s.id").
+		ColumnExpr("e.path").
+		ColumnExpr("COUNT(*) AS views").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where("e.definition_id IS NULL")
+	views = applySessionFilters(views, query.Filter)
+	views = views.Group("e.path")
+
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		Join("LEFT JOIN (?) AS pv ON pv.path = s.exit_path", views).
+		ColumnExpr("s.exit_path AS path").
+		ColumnExpr("COUNT(*) as exits").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("COALESCE(MAX(pv.views), 0) as views").
+		ColumnExpr("COUNT(*) OVER() as total").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where("s.page_view_count > 0")
+	q = applySessionFilters(q, query.Filter)
+	q = q.Group("s.exit_path")
+	err := q.Clone().
+		Order("exits DESC", "path ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get exit pages with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+	} else if query.Offset > 0 {
+		total, err = r.groupedRowCount(ctx, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get exit pages total: %w", err)
+		}
+	}
+	return stats, total, nil
+}
+
+type UTMDimension string
+
+const (
+	UTMDimensionSource   UTMDimension = "source"
+	UTMDimensionMedium   UTMDimension = "medium"
+	UTMDimensionCampaign UTMDimension = "campaign"
+	UTMDimensionTerm     UTMDimension = "term"
+	UTMDimensionContent  UTMDimension = "content"
+)
+
+var utmDimensionColumns = map[UTMDimension]string{
+	UTMDimensionSource:   "s.utm_source",
+	UTMDimensionMedium:   "s.utm_medium",
+	UTMDimensionCampaign: "s.utm_campaign",
+	UTMDimensionTerm:     "s.utm_term",
+	UTMDimensionContent:  "s.utm_content",
+}
+
+// GetUTMStatsWithFilterPaged groups tagged sessions by one UTM parameter; untagged sessions are left out.
+func (r *Repository) GetUTMStatsWithFilterPaged(ctx context.Context, query AnalyticsQuery, dimension UTMDimension) ([]UTMStats, int, error) {
+	column, ok := utmDimensionColumns[dimension]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported utm dimension %q", dimension)
+	}
+	var stats []UTMStats
+	var total int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		ColumnExpr(column+" AS value").
+		ColumnExpr("COUNT(*) as sessions").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("SUM(CASE WHEN s.page_view_count = 1 THEN 1 ELSE 0 END) as bounces").
+		ColumnExpr("COUNT(*) OVER() as total").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where(column + " <> ''")
+	q = applySessionFilters(q, query.Filter)
+	q = q.Group(column)
+	err := q.Clone().
+		Order("visitors DESC", "value ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get utm %s stats with filter paged: %w", dimension, err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+	} else if query.Offset > 0 {
+		total, err = r.groupedRowCount(ctx, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get utm %s stats total: %w", dimension, err)
+		}
+	}
+	return stats, total, nil
+}
+
 func (r *Repository) GetDeviceStatsWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]DeviceStats, int, int, error) {
 	var stats []DeviceStats
 	var total int
@@ -188,6 +416,46 @@ func (r *Repository) GetOperatingSystemStatsWithFilterPaged(ctx context.Context,
 	return stats, total, totalVisitors, nil
 }
 
+func (r *Repository) GetScreenSizeStatsWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]ScreenSizeStats, int, int, error) {
+	var stats []ScreenSizeStats
+	var total int
+	var totalVisitors int
+	fromUnix := query.From.Unix()
+	toUnix := query.To.Unix()
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		Join("INNER JOIN clients c ON s.client_id = c.id").
+		ColumnExpr("c.screen_size").
+		ColumnExpr("COUNT(DISTINCT s.client_id) as visitors").
+		ColumnExpr("COUNT(*) OVER() as total").
+		ColumnExpr("SUM(COUNT(DISTINCT s.client_id)) OVER() as total_visitors").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", fromUnix).
+		Where("s.enter_time <= ?", toUnix).
+		Where("c.screen_size != ?", ClientScreenSizeUnknown)
+	q = applySessionFilters(q, query.Filter)
+	q = q.Group("c.screen_size")
+	err := q.Clone().
+		Order("visitors DESC", "c.screen_size ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get screen size stats with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+		totalVisitors = stats[0].TotalVisitors
+	} else if query.Offset > 0 {
+		total, totalVisitors, err = r.groupedRowAndVisitorTotals(ctx, q)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to get screen size stats totals: %w", err)
+		}
+	}
+	return stats, total, totalVisitors, nil
+}
+
 func (r *Repository) GetCountryStatsWithFilterPaged(ctx context.Context, query AnalyticsQuery) ([]CountryStats, int, int, error) {
 	var stats []CountryStats
 	var total int
@@ -227,18 +495,85 @@ func (r *Repository) GetCountryStatsWithFilterPaged(ctx context.Context, query A
 	return stats, total, totalVisitors, nil
 }
 
+// GetGoalStatsWithFilterPaged counts distinct converting visitors per site goal. Conversions are scoped to
+// sessions entered within the range so the rate stays comparable with the unique visitor count. Callers pass
+// the stored goal type values because goals are owned by their own package.
+func (r *Repository) GetGoalStatsWithFilterPaged(
+	ctx context.Context,
+	query AnalyticsQuery,
+	pageGoalType, eventGoalType int8,
+) ([]GoalStats, int, error) {
+	var stats []GoalStats
+	var total int
+	// Page and event goals are matched in separate branches so each join can use its own events index
+	// instead of scanning every in-range event against both conditions.
+	pageConversions := r.goalConversions(query).
+		Join("INNER JOIN goals g ON g.site_id = s.site_id AND g.type = ? AND g.value = e.path", pageGoalType).
+		Where("e.definition_id IS NULL")
+	eventConversions := r.goalConversions(query).
+		Join("INNER JOIN event_definitions ed ON ed.id = e.definition_id").
+		Join("INNER JOIN goals g ON g.site_id = ed.site_id AND g.type = ? AND g.value = ed.name", eventGoalType)
+
+	q := r.db.NewSelect().
+		TableExpr("goals g").
+		Join("LEFT JOIN (? UNION ALL ?) AS conv ON conv.goal_id = g.id", pageConversions, eventConversions).
+		ColumnExpr("g.id AS goal_id").
+		ColumnExpr("g.name").
+		ColumnExpr("g.type").
+		ColumnExpr("g.value").
+		ColumnExpr("g.created_at").
+		ColumnExpr("g.updated_at").
+		ColumnExpr("COUNT(DISTINCT conv.client_id) as conversions").
+		ColumnExpr("COUNT(*) OVER() as total").
+		Where("g.site_id = ?", query.SiteID).
+		Group("g.id", "g.name", "g.type", "g.value", "g.created_at", "g.updated_at")
+	err := q.Clone().
+		Order("conversions DESC", "g.name ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(ctx, &stats)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get goal stats with filter paged: %w", err)
+	}
+
+	if len(stats) > 0 {
+		total = stats[0].Total
+	} else if query.Offset > 0 {
+		total, err = r.groupedRowCount(ctx, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get goal stats total: %w", err)
+		}
+	}
+	return stats, total, nil
+}
+
+// goalConversions selects the visitors behind events of sessions entered within the query range.
+func (r *Repository) goalConversions(query AnalyticsQuery) *bun.SelectQuery {
+	q := r.db.NewSelect().
+		TableExpr("sessions s").
+		Join("INNER JOIN events e ON e.session_id = s.id").
+		ColumnExpr("g.id AS goal_id").
+		ColumnExpr("s.client_id").
+		Where("s.site_id = ?", query.SiteID).
+		Where("s.enter_time >= ?", query.From.Unix()).
+		Where("s.enter_time <= ?", query.To.Unix())
+	return applySessionFilters(q, query.Filter)
+}
+
 type TimeBucket string
 
 const (
-	TimeBucketDaily  TimeBucket = "daily"
-	TimeBucketHourly TimeBucket = "hourly"
+	TimeBucketDaily   TimeBucket = "daily"
+	TimeBucketHourly  TimeBucket = "hourly"
+	TimeBucketWeekly  TimeBucket = "weekly"
+	TimeBucketMonthly TimeBucket = "monthly"
 )
 
 func (r *Repository) GetTimeSeriesStatsWithFilter(ctx context.Context, query AnalyticsQuery) ([]DailyVisitorStats, error) {
 	fromUnix := query.From.Unix()
 	toUnix := query.To.Unix()
-	sessionBucketExpr := r.sessionTimeBucketExpression(query.Bucket)
-	eventBucketExpr := r.eventTimeBucketExpression(query.Bucket)
+	sessionBucketExpr := r.sessionTimeBucketExpression(query)
+	eventBucketExpr := r.eventTimeBucketExpression(query)
 
 	var sessionStats []struct {
 		DateBucket int64
@@ -325,18 +660,48 @@ func (r *Repository) GetTimeSeriesStatsWithFilter(ctx context.Context, query Ana
 	return stats, nil
 }
 
-func (r *Repository) sessionTimeBucketExpression(bucket TimeBucket) string {
-	if bucket == TimeBucketHourly {
-		return "s.enter_hour"
+func (r *Repository) sessionTimeBucketExpression(query AnalyticsQuery) string {
+	return r.timeBucketExpression(query, "s.enter_time", "s.enter_hour", "s.enter_day")
+}
+
+func (r *Repository) eventTimeBucketExpression(query AnalyticsQuery) string {
+	return r.timeBucketExpression(query, "e.time", "e.hour", "e.day")
+}
+
+// timeBucketExpression numbers hours, days, ISO weeks, or months since the epoch.
+// UTC sites use the precomputed day and hour columns; other sites number local wall-clock buckets.
+func (r *Repository) timeBucketExpression(query AnalyticsQuery, timeColumn, hourColumn, dayColumn string) string {
+	local := !isUTCLocation(query.Location)
+	if query.Bucket == TimeBucketHourly {
+		if local {
+			return localTimeBucketExpression(timeColumn, 3600, query.Location, query.From, query.To)
+		}
+		return hourColumn
 	}
 
-	return "s.enter_day"
+	dayExpr := dayColumn
+	if local {
+		dayExpr = localTimeBucketExpression(timeColumn, 86400, query.Location, query.From, query.To)
+	}
+	switch query.Bucket {
+	case TimeBucketWeekly:
+		// Day 0 was a Thursday, so shifting by three days makes weeks start on Monday.
+		return "(" + dayExpr + " + 3) / 7"
+	case TimeBucketMonthly:
+		return r.monthBucketExpression(dayExpr)
+	default:
+		return dayExpr
+	}
 }
 
-func (r *Repository) eventTimeBucketExpression(bucket TimeBucket) string {
-	if bucket == TimeBucketHourly {
-		return "e.hour"
+// monthBucketExpression numbers the calendar month of a day number as year * 12 + month - 1.
+func (r *Repository) monthBucketExpression(dayExpr string) string {
+	dialect := fmt.Sprint(r.db.Dialect().Name())
+	if dialect == "pg" || dialect == "postgres" || dialect == "postgresql" {
+		date := "(DATE '1970-01-01' + CAST(" + dayExpr + " AS INTEGER))"
+		return "CAST(EXTRACT(YEAR FROM " + date + ") * 12 + EXTRACT(MONTH FROM " + date + ") - 1 AS BIGINT)"
 	}
 
-	return "e.day"
+	seconds := "(" + dayExpr + ") * 86400"
+	return "CAST(strftime('%Y', " + seconds + ", 'unixepoch') AS INTEGER) * 12 + CAST(strftime('%m', " + seconds + ", 'unixepoch') AS INTEGER) - 1"
 }
@@ -12,6 +12,18 @@ import (
 )
 
 func TestPagedBreakdownsPostgres(t *testing.T) {
+	db := setupPostgresTestDB(t)
+	testPagedBreakdownsReturnExactWindowTotals(t, db)
+}
+
+func TestTimeSeriesLocalDaysPostgres(t *testing.T) {
+	db := setupPostgresTestDB(t)
+	testTimeSeriesLocalDaysAcrossDST(t, db)
+	testTimeSeriesWeeklyAndMonthlyBuckets(t, db)
+}
+
+func setupPostgresTestDB(t *testing.T) *bun.DB {
+	t.Helper()
 	dsn := os.Getenv("LOVELY_EYE_TEST_POSTGRES_DSN")
 	if dsn == "" {
 		t.Skip("LOVELY_EYE_TEST_POSTGRES_DSN is not set")
@@ -28,6 +40,5 @@ func TestPagedBreakdownsPostgres(t *testing.T) {
 	if err := database.Migrate(t.Context(), db); err != nil {
 		t.Fatalf("migrate PostgreSQL database: %v", err)
 	}
-
-	testPagedBreakdownsReturnExactWindowTotals(t, db)
+	return db
 }
@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+func TestReferrerDomainsAndChannelsGroupNormalizedSources(t *testing.T) {
+	db := setupTestDB(t)
+	repo := New(db)
+	ctx := context.Background()
+	site := createTestSite(t, db)
+	now := time.Now().UTC()
+
+	searcher := createTestClient(t, db, site.ID, "channel-search", "desktop", "chrome", "linux")
+	first := insertSessionWithPath(t, db, site.ID, searcher, "/", now.Add(-3*time.Hour), 0, 1)
+	setSessionReferrer(t, db, first, "https://www.google.com/", "google.com", SessionChannelSearch)
+	second := insertSessionWithPath(t, db, site.ID, searcher, "/docs", now.Add(-2*time.Hour), 0, 1)
+	setSessionReferrer(t, db, second, "https://google.com/search?q=docs", "google.com", SessionChannelSearch)
+
+	reader := createTestClient(t, db, site.ID, "channel-social", "mobile", "safari", "ios")
+	social := insertSessionWithPath(t, db, site.ID, reader, "/blog", now.Add(-time.Hour), 0, 1)
+	setSessionReferrer(t, db, social, "https://l.facebook.com/l.php", "l.facebook.com", SessionChannelSocial)
+
+	direct := createTestClient(t, db, site.ID, "channel-direct", "desktop", "firefox", "windows")
+	directVisit := insertSessionWithPath(t, db, site.ID, direct, "/", now.Add(-30*time.Minute), 0, 1)
+	setSessionReferrer(t, db, directVisit, "", "", SessionChannelDirect)
+
+	query := AnalyticsQuery{
+		SiteID: site.ID,
+		From:   now.Add(-24 * time.Hour),
+		To:     now,
+		Limit:  10,
+	}
+	domains, total, err := repo.GetReferrerDomainsWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []ReferrerStats{
+		{Referrer: "(direct)", Visitors: 1, Total: 3},
+		{Referrer: "google.com", Visitors: 1, Total: 3},
+		{Referrer: "l.facebook.com", Visitors: 1, Total: 3},
+	}, domains)
+
+	channels, total, totalVisitors, err := repo.GetChannelStatsWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, 3, totalVisitors)
+	require.Len(t, channels, 3)
+	require.Equal(t, SessionChannelDirect, channels[0].Channel)
+
+	query.Filter = AnalyticsFilter{Channel: []string{"search"}}
+	sessions, err := repo.GetSessionCountWithFilter(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions)
+
+	query.Filter = AnalyticsFilter{ReferrerDomain: []string{"", "l.facebook.com"}}
+	sessions, err = repo.GetSessionCountWithFilter(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions)
+
+	query.Filter = AnalyticsFilter{Channel: []string{"carrier-pigeon"}}
+	sessions, err = repo.GetSessionCountWithFilter(ctx, query)
+	require.NoError(t, err)
+	require.Zero(t, sessions)
+}
+
+func setSessionReferrer(t *testing.T, db *bun.DB, sessionID int64, referrer, host string, channel SessionChannel) {
+	t.Helper()
+
+	_, err := db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("referrer = ?", referrer).
+		Set("referrer_host = ?", host).
+		Set("channel = ?", channel).
+		Where("id = ?", sessionID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
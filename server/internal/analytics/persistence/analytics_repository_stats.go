@@ -218,12 +218,43 @@ func (r *Repository) GetTopPages(ctx context.Context, siteID int64, from, to tim
 	return stats, nil
 }
 
+type EntryPageStats struct {
+	Path     string
+	Sessions int
+	Visitors int
+	Bounces  int
+	Total    int
+}
+
+type ExitPageStats struct {
+	Path     string
+	Exits    int
+	Visitors int
+	Views    int
+	Total    int
+}
+
+type UTMStats struct {
+	Value    string
+	Sessions int
+	Visitors int
+	Bounces  int
+	Total    int
+}
+
 type ReferrerStats struct {
 	Referrer string
 	Visitors int
 	Total    int
 }
 
+type ChannelStats struct {
+	Channel       SessionChannel
+	Visitors      int
+	Total         int
+	TotalVisitors int
+}
+
 type BrowserStats struct {
 	Browser  ClientBrowser
 	Visitors int
@@ -243,6 +274,13 @@ type OperatingSystemStats struct {
 	TotalVisitors int
 }
 
+type ScreenSizeStats struct {
+	ScreenSize    ClientScreenSize
+	Visitors      int
+	Total         int
+	TotalVisitors int
+}
+
 type CountryStats struct {
 	CountryCode   string
 	Visitors      int
@@ -250,6 +288,17 @@ type CountryStats struct {
 	TotalVisitors int
 }
 
+type GoalStats struct {
+	GoalID      int64
+	Name        string
+	Type        int8
+	Value       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Conversions int
+	Total       int
+}
+
 type DailyVisitorStats struct {
 	DateBucket int64 // Unix timestamp bucket (day or hour) - integer for performance
 	Visitors   int
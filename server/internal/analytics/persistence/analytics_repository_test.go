@@ -193,21 +193,22 @@ func testPagedBreakdownsReturnExactWindowTotals(t *testing.T, db *bun.DB) {
 	now := time.Now().UTC()
 
 	clients := []struct {
-		hash     string
-		device   string
-		browser  string
-		os       string
-		country  string
-		path     string
-		referrer string
+		hash       string
+		device     string
+		browser    string
+		os         string
+		screenSize ClientScreenSize
+		country    string
+		path       string
+		referrer   string
 	}{
-		{hash: "window-1", device: "desktop", browser: "chrome", os: "linux", country: "US", path: "/one", referrer: "https://one.example"},
-		{hash: "window-2", device: "mobile", browser: "safari", os: "ios", country: "CA", path: "/two", referrer: "https://two.example"},
-		{hash: "window-3", device: "desktop", browser: "chrome", os: "windows", country: "US", path: "/one", referrer: "https://one.example"},
+		{hash: "window-1", device: "desktop", browser: "chrome", os: "linux", screenSize: ClientScreenSizeXL, country: "US", path: "/one", referrer: "https://one.example"},
+		{hash: "window-2", device: "mobile", browser: "safari", os: "ios", screenSize: ClientScreenSizeXS, country: "CA", path: "/two", referrer: "https://two.example"},
+		{hash: "window-3", device: "desktop", browser: "chrome", os: "windows", screenSize: ClientScreenSizeUnknown, country: "US", path: "/one", referrer: "https://one.example"},
 	}
 	for index, fixture := range clients {
 		clientID := createTestClient(t, db, site.ID, fixture.hash, fixture.device, fixture.browser, fixture.os)
-		_, err := db.ExecContext(ctx, "UPDATE clients SET country = ? WHERE id = ?", fixture.country, clientID)
+		_, err := db.ExecContext(ctx, "UPDATE clients SET country = ?, screen_size = ? WHERE id = ?", fixture.country, fixture.screenSize, clientID)
 		require.NoError(t, err)
 		timestamp := now.Add(-time.Duration(index+1) * time.Hour)
 		sessionID := insertSessionWithPath(t, db, site.ID, clientID, fixture.path, timestamp, 60, 1)
@@ -240,6 +241,11 @@ func testPagedBreakdownsReturnExactWindowTotals(t *testing.T, db *bun.DB) {
 	require.Len(t, operatingSystems, 1)
 	require.Equal(t, 3, osTotal)
 	require.Equal(t, 3, osVisitors)
+	screenSizes, screenSizeTotal, screenSizeVisitors, err := repository.GetScreenSizeStatsWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, screenSizes, 1)
+	require.Equal(t, 2, screenSizeTotal)
+	require.Equal(t, 2, screenSizeVisitors)
 	countries, countryTotal, countryVisitors, err := repository.GetCountryStatsWithFilterPaged(ctx, query)
 	require.NoError(t, err)
 	require.Len(t, countries, 1)
@@ -265,11 +271,25 @@ func testPagedBreakdownsReturnExactWindowTotals(t *testing.T, db *bun.DB) {
 	require.Empty(t, operatingSystems)
 	require.Equal(t, 3, osTotal)
 	require.Equal(t, 3, osVisitors)
+	screenSizes, screenSizeTotal, screenSizeVisitors, err = repository.GetScreenSizeStatsWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Empty(t, screenSizes)
+	require.Equal(t, 2, screenSizeTotal)
+	require.Equal(t, 2, screenSizeVisitors)
 	countries, countryTotal, countryVisitors, err = repository.GetCountryStatsWithFilterPaged(ctx, query)
 	require.NoError(t, err)
 	require.Empty(t, countries)
 	require.Equal(t, 2, countryTotal)
 	require.Equal(t, 3, countryVisitors)
+
+	query.Offset = 0
+	query.Filter.ScreenSize = []string{"xs"}
+	devices, deviceTotal, deviceVisitors, err = repository.GetDeviceStatsWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	require.Equal(t, ClientDeviceMobile, devices[0].Device)
+	require.Equal(t, 1, deviceTotal)
+	require.Equal(t, 1, deviceVisitors)
 }
 
 func TestEventCountsReturnTotalForOutOfRangePage(t *testing.T) {
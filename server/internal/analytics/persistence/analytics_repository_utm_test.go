@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+func TestUTMStatsGroupTaggedSessionsAndFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := New(db)
+	ctx := context.Background()
+	site := createTestSite(t, db)
+	now := time.Now().UTC()
+
+	newsletter := createTestClient(t, db, site.ID, "utm-newsletter", "desktop", "chrome", "linux")
+	first := insertSessionWithPath(t, db, site.ID, newsletter, "/", now.Add(-3*time.Hour), 0, 1)
+	setSessionUTM(t, db, first, "newsletter", "email", "spring", "", "header")
+	second := insertSessionWithPath(t, db, site.ID, newsletter, "/", now.Add(-2*time.Hour), 60, 3)
+	setSessionUTM(t, db, second, "newsletter", "email", "spring", "", "footer")
+
+	ads := createTestClient(t, db, site.ID, "utm-ads", "mobile", "safari", "ios")
+	adVisit := insertSessionWithPath(t, db, site.ID, ads, "/pricing", now.Add(-time.Hour), 30, 2)
+	setSessionUTM(t, db, adVisit, "google", "cpc", "spring", "web analytics", "banner")
+	insertPageViewEvent(t, db, adVisit, "/pricing", now.Add(-time.Hour))
+
+	untagged := createTestClient(t, db, site.ID, "utm-untagged", "desktop", "firefox", "windows")
+	insertSessionWithPath(t, db, site.ID, untagged, "/", now.Add(-30*time.Minute), 0, 1)
+
+	query := AnalyticsQuery{
+		SiteID: site.ID,
+		From:   now.Add(-24 * time.Hour),
+		To:     now,
+		Limit:  10,
+	}
+	sources, total, err := repo.GetUTMStatsWithFilterPaged(ctx, query, UTMDimensionSource)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, []UTMStats{
+		{Value: "google", Sessions: 1, Visitors: 1, Bounces: 0, Total: 2},
+		{Value: "newsletter", Sessions: 2, Visitors: 1, Bounces: 1, Total: 2},
+	}, sources)
+
+	campaigns, total, err := repo.GetUTMStatsWithFilterPaged(ctx, query, UTMDimensionCampaign)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []UTMStats{{Value: "spring", Sessions: 3, Visitors: 2, Bounces: 1, Total: 1}}, campaigns)
+
+	terms, total, err := repo.GetUTMStatsWithFilterPaged(ctx, query, UTMDimensionTerm)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []UTMStats{{Value: "web analytics", Sessions: 1, Visitors: 1, Bounces: 0, Total: 1}}, terms)
+
+	contents, total, err := repo.GetUTMStatsWithFilterPaged(ctx, query, UTMDimensionContent)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, "banner", contents[0].Value)
+
+	_, _, err = repo.GetUTMStatsWithFilterPaged(ctx, query, UTMDimension("term; DROP TABLE sessions"))
+	require.Error(t, err)
+
+	query.Filter = AnalyticsFilter{UTMMedium: []string{"cpc"}}
+	visitors, err := repo.GetVisitorCountWithFilter(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 1, visitors)
+	pages, _, err := repo.GetTopPagesWithFilterPaged(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	require.Equal(t, "/pricing", pages[0].Path)
+
+	query.Filter = AnalyticsFilter{UTMContent: []string{"header", "footer"}}
+	sessions, err := repo.GetSessionCountWithFilter(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions)
+}
+
+func setSessionUTM(t *testing.T, db *bun.DB, sessionID int64, source, medium, campaign, term, content string) {
+	t.Helper()
+
+	_, err := db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("utm_source = ?", source).
+		Set("utm_medium = ?", medium).
+		Set("utm_campaign = ?", campaign).
+		Set("utm_term = ?", term).
+		Set("utm_content = ?", content).
+		Where("id = ?", sessionID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
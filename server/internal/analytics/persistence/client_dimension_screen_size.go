@@ -72,27 +72,39 @@ func ClientScreenSizeFromWidth(width int) ClientScreenSize {
 	}
 }
 
-func ClientScreenSizeFromLegacyLabel(value string) ClientScreenSize {
+func ClientScreenSizeFromLabel(value string) (ClientScreenSize, bool) {
 	switch normalizeClientDimensionLabel(value) {
-	case "":
-		return ClientScreenSizeUnknown
 	case "watch":
-		return ClientScreenSizeWatch
+		return ClientScreenSizeWatch, true
 	case "xs":
-		return ClientScreenSizeXS
+		return ClientScreenSizeXS, true
 	case "sm":
-		return ClientScreenSizeSM
+		return ClientScreenSizeSM, true
 	case "md":
-		return ClientScreenSizeMD
+		return ClientScreenSizeMD, true
 	case "lg":
-		return ClientScreenSizeLG
+		return ClientScreenSizeLG, true
 	case "xl":
-		return ClientScreenSizeXL
+		return ClientScreenSizeXL, true
 	default:
-		width, ok := parseLegacyScreenWidth(value)
-		if !ok {
-			return ClientScreenSizeUnknown
-		}
-		return ClientScreenSizeFromWidth(width)
+		return ClientScreenSizeUnknown, false
+	}
+}
+
+func ClientScreenSizeFromLegacyLabel(value string) ClientScreenSize {
+	if normalizeClientDimensionLabel(value) == "" {
+		return ClientScreenSizeUnknown
+	}
+	if screenSize, ok := ClientScreenSizeFromLabel(value); ok {
+		return screenSize
 	}
+	width, ok := parseLegacyScreenWidth(value)
+	if !ok {
+		return ClientScreenSizeUnknown
+	}
+	return ClientScreenSizeFromWidth(width)
+}
+
+func ParseClientScreenSizeFilters(values []string) []ClientScreenSize {
+	return parseClientDimensionFilters(values, ClientScreenSizeFromLabel)
 }
@@ -121,3 +121,17 @@ func TestParseClientDeviceFilters(t *testing.T) {
 		}
 	}
 }
+
+func TestParseClientScreenSizeFilters(t *testing.T) {
+	got := ParseClientScreenSizeFilters([]string{"XL", "xs", "xl", "1920x1080", ""})
+	want := []ClientScreenSize{ClientScreenSizeXL, ClientScreenSizeXS}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseClientScreenSizeFilters() length = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ParseClientScreenSizeFilters()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
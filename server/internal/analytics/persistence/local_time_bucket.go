@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// utcOffsetSegment is a span of unix seconds that shares one UTC offset.
+// End is exclusive; the last segment of a range has End zero.
+type utcOffsetSegment struct {
+	End    int64
+	Offset int64
+}
+
+// utcOffsetSegments splits [from, to] at every UTC offset transition of loc.
+func utcOffsetSegments(loc *time.Location, from, to time.Time) []utcOffsetSegment {
+	segments := make([]utcOffsetSegment, 0, 1)
+	current := from.In(loc)
+	_, offset := current.Zone()
+	end := to.In(loc)
+
+	for current.Before(end) {
+		next := current.Add(24 * time.Hour)
+		if next.After(end) {
+			next = end
+		}
+		if _, nextOffset := next.Zone(); nextOffset != offset {
+			transition := findOffsetTransition(loc, current, next, offset)
+			segments = append(segments, utcOffsetSegment{End: transition.Unix(), Offset: int64(offset)})
+			_, offset = transition.Zone()
+			next = transition
+		}
+		current = next
+	}
+	return append(segments, utcOffsetSegment{Offset: int64(offset)})
+}
+
+// findOffsetTransition returns the first second in (before, after] whose offset differs from offset.
+func findOffsetTransition(loc *time.Location, before, after time.Time, offset int) time.Time {
+	low := before.Unix()
+	high := after.Unix()
+	for high-low > 1 {
+		middle := low + (high-low)/2
+		if _, middleOffset := time.Unix(middle, 0).In(loc).Zone(); middleOffset == offset {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return time.Unix(high, 0).In(loc)
+}
+
+// localTimeBucketExpression numbers the local wall-clock day or hour of a unix-seconds column.
+// Offsets are resolved in Go so the expression is plain integer arithmetic on SQLite and PostgreSQL.
+func localTimeBucketExpression(column string, bucketSeconds int, loc *time.Location, from, to time.Time) string {
+	segments := utcOffsetSegments(loc, from, to)
+	if len(segments) == 1 {
+		return localBucketTerm(column, segments[0].Offset, bucketSeconds)
+	}
+
+	var expression strings.Builder
+	expression.WriteString("CASE")
+	for _, segment := range segments[:len(segments)-1] {
+		fmt.Fprintf(&expression, " WHEN %s < %d THEN %s", column, segment.End, localBucketTerm(column, segment.Offset, bucketSeconds))
+	}
+	fmt.Fprintf(&expression, " ELSE %s END", localBucketTerm(column, segments[len(segments)-1].Offset, bucketSeconds))
+	return expression.String()
+}
+
+func localBucketTerm(column string, offset int64, bucketSeconds int) string {
+	if offset < 0 {
+		return fmt.Sprintf("(%s - %d) / %d", column, -offset, bucketSeconds)
+	}
+	return fmt.Sprintf("(%s + %d) / %d", column, offset, bucketSeconds)
+}
+
+func isUTCLocation(loc *time.Location) bool {
+	return loc == nil || loc == time.UTC || loc.String() == "UTC"
+}
@@ -0,0 +1,193 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+func TestUTCOffsetSegmentsSplitsAtDSTTransitions(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 11, 30, 0, 0, 0, 0, loc)
+	segments := utcOffsetSegments(loc, from, to)
+
+	want := []utcOffsetSegment{
+		{End: time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC).Unix(), Offset: -8 * 3600},
+		{End: time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC).Unix(), Offset: -7 * 3600},
+		{Offset: -8 * 3600},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("utcOffsetSegments() = %+v, want %+v", segments, want)
+	}
+	for index := range want {
+		if segments[index] != want[index] {
+			t.Fatalf("utcOffsetSegments()[%d] = %+v, want %+v", index, segments[index], want[index])
+		}
+	}
+}
+
+func TestLocalTimeBucketExpressionWithoutTransition(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+	got := localTimeBucketExpression("e.time", 3600, loc, from, from.AddDate(0, 1, 0))
+	if want := "(e.time + 19800) / 3600"; got != want {
+		t.Fatalf("localTimeBucketExpression() = %q, want %q", got, want)
+	}
+}
+
+func TestGetTimeSeriesStatsWithFilterBucketsByLocalDayAcrossDST(t *testing.T) {
+	db := setupTestDB(t)
+	testTimeSeriesLocalDaysAcrossDST(t, db)
+}
+
+func testTimeSeriesLocalDaysAcrossDST(t *testing.T, db *bun.DB) {
+	t.Helper()
+	repo := New(db)
+	ctx := context.Background()
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	site := createTestSite(t, db)
+	clientID := createTestClient(t, db, site.ID, "hash-dst", "desktop", "Chrome", "Windows")
+
+	// The first two sessions share a UTC day but not a local one; the last two share a local day
+	// that spans the spring-forward transition and two UTC days.
+	enterTimes := []time.Time{
+		time.Date(2026, 3, 7, 23, 30, 0, 0, loc),
+		time.Date(2026, 3, 8, 0, 30, 0, 0, loc),
+		time.Date(2026, 3, 8, 23, 30, 0, 0, loc),
+	}
+	for _, enterTime := range enterTimes {
+		sessionID := insertSessionWithPath(t, db, site.ID, clientID, "/", enterTime, 60, 1)
+		insertPageViewEvent(t, db, sessionID, "/", enterTime)
+	}
+
+	stats, err := repo.GetTimeSeriesStatsWithFilter(ctx, AnalyticsQuery{
+		SiteID:   site.ID,
+		From:     time.Date(2026, 3, 7, 0, 0, 0, 0, loc),
+		To:       time.Date(2026, 3, 9, 23, 59, 59, 0, loc),
+		Bucket:   TimeBucketDaily,
+		Location: loc,
+	})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesStatsWithFilter() error = %v", err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("GetTimeSeriesStatsWithFilter() = %+v, want 2 local days", stats)
+	}
+	wantBuckets := []int64{
+		time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC).Unix() / 86400,
+		time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC).Unix() / 86400,
+	}
+	wantSessions := []int{1, 2}
+	for index, stat := range stats {
+		if stat.DateBucket != wantBuckets[index] {
+			t.Fatalf("stats[%d].DateBucket = %d, want %d", index, stat.DateBucket, wantBuckets[index])
+		}
+		if stat.Sessions != wantSessions[index] || stat.PageViews != wantSessions[index] {
+			t.Fatalf("stats[%d] = %+v, want %d sessions and pageviews", index, stat, wantSessions[index])
+		}
+	}
+}
+
+func TestGetTimeSeriesStatsWithFilterBucketsByWeekAndMonth(t *testing.T) {
+	db := setupTestDB(t)
+	testTimeSeriesWeeklyAndMonthlyBuckets(t, db)
+}
+
+func testTimeSeriesWeeklyAndMonthlyBuckets(t *testing.T, db *bun.DB) {
+	t.Helper()
+	repo := New(db)
+	ctx := context.Background()
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	site := createTestSite(t, db)
+	clientID := createTestClient(t, db, site.ID, "hash-calendar", "desktop", "Chrome", "Windows")
+
+	// Sunday 2026-03-01 is the last day of an ISO week; 23:30 local is already March 2 in UTC.
+	enterTimes := []time.Time{
+		time.Date(2026, 2, 28, 12, 0, 0, 0, loc),
+		time.Date(2026, 3, 1, 23, 30, 0, 0, loc),
+		time.Date(2026, 3, 2, 9, 0, 0, 0, loc),
+	}
+	for _, enterTime := range enterTimes {
+		sessionID := insertSessionWithPath(t, db, site.ID, clientID, "/", enterTime, 60, 1)
+		insertPageViewEvent(t, db, sessionID, "/", enterTime)
+	}
+
+	tests := []struct {
+		name         string
+		location     *time.Location
+		bucket       TimeBucket
+		wantBuckets  []int64
+		wantSessions []int
+	}{
+		{
+			name:         "weekly local",
+			location:     loc,
+			bucket:       TimeBucketWeekly,
+			wantBuckets:  []int64{(dayNumber(2026, 2, 23) + 3) / 7, (dayNumber(2026, 3, 2) + 3) / 7},
+			wantSessions: []int{2, 1},
+		},
+		{
+			name:         "weekly UTC",
+			bucket:       TimeBucketWeekly,
+			wantBuckets:  []int64{(dayNumber(2026, 2, 23) + 3) / 7, (dayNumber(2026, 3, 2) + 3) / 7},
+			wantSessions: []int{1, 2},
+		},
+		{
+			name:         "monthly local",
+			location:     loc,
+			bucket:       TimeBucketMonthly,
+			wantBuckets:  []int64{2026*12 + 1, 2026*12 + 2},
+			wantSessions: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetTimeSeriesStatsWithFilter(ctx, AnalyticsQuery{
+				SiteID:   site.ID,
+				From:     time.Date(2026, 2, 1, 0, 0, 0, 0, loc),
+				To:       time.Date(2026, 3, 31, 0, 0, 0, 0, loc),
+				Bucket:   tt.bucket,
+				Location: tt.location,
+			})
+			if err != nil {
+				t.Fatalf("GetTimeSeriesStatsWithFilter() error = %v", err)
+			}
+			if len(stats) != len(tt.wantBuckets) {
+				t.Fatalf("GetTimeSeriesStatsWithFilter() = %+v, want %d buckets", stats, len(tt.wantBuckets))
+			}
+			for index, stat := range stats {
+				if stat.DateBucket != tt.wantBuckets[index] {
+					t.Fatalf("stats[%d].DateBucket = %d, want %d", index, stat.DateBucket, tt.wantBuckets[index])
+				}
+				if stat.Sessions != tt.wantSessions[index] || stat.PageViews != tt.wantSessions[index] {
+					t.Fatalf("stats[%d] = %+v, want %d sessions and pageviews", index, stat, tt.wantSessions[index])
+				}
+			}
+		})
+	}
+}
+
+func dayNumber(year int, month time.Month, day int) int64 {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
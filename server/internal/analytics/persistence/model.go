@@ -38,10 +38,14 @@ type Session struct {
 	ExitDay  int64  `bun:"exit_day,notnull"`
 	ExitPath string `bun:"exit_path,notnull,type:varchar(2048)"`
 
-	Referrer    string `bun:"referrer,type:varchar(2048)"`
-	UTMSource   string `bun:"utm_source,type:varchar(128)"`
-	UTMMedium   string `bun:"utm_medium,type:varchar(128)"`
-	UTMCampaign string `bun:"utm_campaign,type:varchar(256)"`
+	Referrer     string         `bun:"referrer,type:varchar(2048)"`
+	ReferrerHost string         `bun:"referrer_host,type:varchar(255)"`
+	Channel      SessionChannel `bun:"channel,notnull,default:0"`
+	UTMSource    string         `bun:"utm_source,type:varchar(128)"`
+	UTMMedium    string         `bun:"utm_medium,type:varchar(128)"`
+	UTMCampaign  string         `bun:"utm_campaign,type:varchar(256)"`
+	UTMTerm      string         `bun:"utm_term,type:varchar(256)"`
+	UTMContent   string         `bun:"utm_content,type:varchar(256)"`
 
 	Duration      int `bun:"duration,notnull,default:0"`
 	PageViewCount int `bun:"page_view_count,notnull,default:0"`
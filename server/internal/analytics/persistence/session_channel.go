@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// SessionChannel is the acquisition channel derived from a session's referrer and UTM tags.
+type SessionChannel uint8
+
+const (
+	// Persisted analytics enum codes are hard-coded on purpose.
+	// Do not reorder these values or switch to iota, because existing rows and migrations depend on them.
+	SessionChannelUnknown  SessionChannel = 0
+	SessionChannelDirect   SessionChannel = 1
+	SessionChannelEmail    SessionChannel = 2
+	SessionChannelPaid     SessionChannel = 3
+	SessionChannelReferral SessionChannel = 4
+	SessionChannelSearch   SessionChannel = 5
+	SessionChannelSocial   SessionChannel = 6
+)
+
+func (c SessionChannel) String() string {
+	switch c {
+	case SessionChannelDirect:
+		return "direct"
+	case SessionChannelEmail:
+		return "email"
+	case SessionChannelPaid:
+		return "paid"
+	case SessionChannelReferral:
+		return "referral"
+	case SessionChannelSearch:
+		return "search"
+	case SessionChannelSocial:
+		return "social"
+	default:
+		return ""
+	}
+}
+
+func (c SessionChannel) Value() (driver.Value, error) {
+	return int64(c), nil
+}
+
+func (c *SessionChannel) Scan(src any) error {
+	return scanClientEnumUint8((*uint8)(c), src)
+}
+
+func (c SessionChannel) MarshalJSON() ([]byte, error) {
+	bytes, err := json.Marshal(c.String())
+	if err != nil {
+		return nil, fmt.Errorf("marshal session channel: %w", err)
+	}
+	return bytes, nil
+}
+
+func SessionChannelFromLabel(value string) (SessionChannel, bool) {
+	switch normalizeClientDimensionLabel(value) {
+	case "direct":
+		return SessionChannelDirect, true
+	case "email":
+		return SessionChannelEmail, true
+	case "paid":
+		return SessionChannelPaid, true
+	case "referral":
+		return SessionChannelReferral, true
+	case "search":
+		return SessionChannelSearch, true
+	case "social":
+		return SessionChannelSocial, true
+	default:
+		return SessionChannelUnknown, false
+	}
+}
+
+func ParseSessionChannelFilters(values []string) []SessionChannel {
+	return parseClientDimensionFilters(values, SessionChannelFromLabel)
+}
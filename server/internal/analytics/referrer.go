@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+)
+
+// maxReferrerHostLength matches the sessions.referrer_host column.
+const maxReferrerHostLength = 255
+
+// The rules below are mirrored by the sessions channel backfill migration;
+// keep both in sync when adding sources.
+var (
+	paidMediums = map[string]struct{}{
+		"cpc": {}, "ppc": {}, "cpm": {}, "cpv": {}, "cpa": {},
+		"paid": {}, "paidsearch": {}, "paid_search": {}, "paid-search": {},
+		"paidsocial": {}, "paid_social": {}, "paid-social": {},
+		"display": {}, "banner": {}, "retargeting": {},
+	}
+	emailMediums = map[string]struct{}{
+		"email": {}, "e-mail": {}, "newsletter": {},
+	}
+	socialMediums = map[string]struct{}{
+		"social": {}, "social-media": {}, "social_media": {},
+		"social-network": {}, "social_network": {}, "sm": {},
+	}
+	emailHosts = map[string]struct{}{
+		"mail.google.com": {}, "mail.yahoo.com": {}, "mail.proton.me": {}, "mail.aol.com": {},
+		"outlook.live.com": {}, "outlook.office.com": {}, "outlook.office365.com": {}, "app.fastmail.com": {},
+	}
+	// searchEngines match on the first host label so every country domain is covered.
+	searchEngines = []string{
+		"google", "bing", "duckduckgo", "yahoo", "yandex", "baidu",
+		"ecosia", "qwant", "startpage", "naver", "seznam",
+	}
+	searchHosts = map[string]struct{}{
+		"search.brave.com": {}, "search.yahoo.com": {},
+	}
+	// socialDomains also match their subdomains, such as l.facebook.com.
+	socialDomains = []string{
+		"facebook.com", "instagram.com", "linkedin.com", "lnkd.in", "t.co", "twitter.com", "x.com",
+		"reddit.com", "news.ycombinator.com", "pinterest.com", "youtube.com", "tiktok.com",
+		"threads.net", "bsky.app", "mastodon.social", "quora.com",
+	}
+	socialSources = map[string]struct{}{
+		"facebook": {}, "instagram": {}, "linkedin": {}, "twitter": {}, "x": {}, "reddit": {},
+		"hackernews": {}, "pinterest": {}, "youtube": {}, "tiktok": {}, "threads": {}, "bluesky": {}, "mastodon": {},
+	}
+)
+
+// normalizeReferrerHost reduces a referrer URL to its lowercase host without
+// scheme, credentials, port, or a leading www./m. label.
+func normalizeReferrerHost(referrer string) string {
+	host := strings.ToLower(strings.TrimSpace(referrer))
+	if index := strings.Index(host, "://"); index >= 0 {
+		host = host[index+3:]
+	}
+	if index := strings.IndexAny(host, "/?#"); index >= 0 {
+		host = host[:index]
+	}
+	if index := strings.Index(host, "@"); index >= 0 {
+		host = host[index+1:]
+	}
+	if index := strings.Index(host, ":"); index >= 0 {
+		host = host[:index]
+	}
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	if utf8.RuneCountInString(host) > maxReferrerHostLength {
+		host = string([]rune(host)[:maxReferrerHostLength])
+	}
+	return host
+}
+
+// classifyChannel prefers an explicit utm_medium, then the referrer host, then
+// utm_source. Anything else that carries attribution counts as a referral.
+func classifyChannel(referrerHost, utmSource, utmMedium string) analyticspersistence.SessionChannel {
+	medium := strings.ToLower(strings.TrimSpace(utmMedium))
+	source := strings.ToLower(strings.TrimSpace(utmSource))
+
+	if _, ok := paidMediums[medium]; ok {
+		return analyticspersistence.SessionChannelPaid
+	}
+	if _, ok := emailMediums[medium]; ok {
+		return analyticspersistence.SessionChannelEmail
+	}
+	if _, ok := socialMediums[medium]; ok {
+		return analyticspersistence.SessionChannelSocial
+	}
+	if medium == "organic" {
+		return analyticspersistence.SessionChannelSearch
+	}
+
+	if _, ok := emailHosts[referrerHost]; ok {
+		return analyticspersistence.SessionChannelEmail
+	}
+	if isSearchHost(referrerHost) {
+		return analyticspersistence.SessionChannelSearch
+	}
+	if isSocialHost(referrerHost) {
+		return analyticspersistence.SessionChannelSocial
+	}
+
+	if _, ok := emailMediums[source]; ok {
+		return analyticspersistence.SessionChannelEmail
+	}
+	if isSearchHost(source) {
+		return analyticspersistence.SessionChannelSearch
+	}
+	if _, ok := socialSources[source]; ok {
+		return analyticspersistence.SessionChannelSocial
+	}
+
+	if referrerHost != "" || source != "" || medium != "" {
+		return analyticspersistence.SessionChannelReferral
+	}
+	return analyticspersistence.SessionChannelDirect
+}
+
+func isSearchHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if _, ok := searchHosts[host]; ok {
+		return true
+	}
+	for _, engine := range searchEngines {
+		if strings.HasPrefix(host+".", engine+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isSocialHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range socialDomains {
+		if strings.HasSuffix("."+host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
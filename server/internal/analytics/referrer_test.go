@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"testing"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+)
+
+func TestNormalizeReferrerHost(t *testing.T) {
+	tests := map[string]string{
+		"":                                       "",
+		"https://www.google.com/":                "google.com",
+		"https://google.de/search?q=lovely":      "google.de",
+		"HTTP://M.Facebook.com/story.php":        "facebook.com",
+		"https://user@blog.example.com:8443/a#b": "blog.example.com",
+		"news.ycombinator.com/item?id=1":         "news.ycombinator.com",
+		"android-app://com.google.android.gm/":   "com.google.android.gm",
+	}
+	for referrer, want := range tests {
+		if got := normalizeReferrerHost(referrer); got != want {
+			t.Errorf("normalizeReferrerHost(%q) = %q, want %q", referrer, got, want)
+		}
+	}
+}
+
+func TestClassifyChannel(t *testing.T) {
+	tests := []struct {
+		name   string
+		host   string
+		source string
+		medium string
+		want   analyticspersistence.SessionChannel
+	}{
+		{name: "no attribution", want: analyticspersistence.SessionChannelDirect},
+		{name: "search engine", host: "google.co.uk", want: analyticspersistence.SessionChannelSearch},
+		{name: "paid medium wins over search host", host: "google.com", source: "google", medium: "CPC", want: analyticspersistence.SessionChannelPaid},
+		{name: "social subdomain", host: "l.facebook.com", want: analyticspersistence.SessionChannelSocial},
+		{name: "webmail", host: "mail.google.com", want: analyticspersistence.SessionChannelEmail},
+		{name: "email medium", source: "weekly", medium: "email", want: analyticspersistence.SessionChannelEmail},
+		{name: "social source without referrer", source: "linkedin", want: analyticspersistence.SessionChannelSocial},
+		{name: "unknown site", host: "blog.example.com", want: analyticspersistence.SessionChannelReferral},
+		{name: "unknown source", source: "partner", want: analyticspersistence.SessionChannelReferral},
+		{name: "lookalike domain", host: "notfacebook.com", want: analyticspersistence.SessionChannelReferral},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyChannel(tt.host, tt.source, tt.medium); got != tt.want {
+				t.Fatalf("classifyChannel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
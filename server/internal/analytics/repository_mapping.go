@@ -1,8 +1,13 @@
 package analytics
 
 import (
+	"time"
+
 	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
 	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	"github.com/lovely-eye/server/internal/funnel"
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
+	"github.com/lovely-eye/server/internal/goal"
 )
 
 func repositoryAnalyticsQuery(query Query) analyticspersistence.AnalyticsQuery {
@@ -11,23 +16,32 @@ func repositoryAnalyticsQuery(query Query) analyticspersistence.AnalyticsQuery {
 		eventTypes = append(eventTypes, analyticspersistence.EventType(eventType))
 	}
 	return analyticspersistence.AnalyticsQuery{
-		SiteID: query.SiteID,
-		From:   query.From,
-		To:     query.To,
-		Limit:  query.Limit,
-		Offset: query.Offset,
-		Bucket: analyticspersistence.TimeBucket(query.Bucket),
+		SiteID:   query.SiteID,
+		From:     query.From,
+		To:       query.To,
+		Limit:    query.Limit,
+		Offset:   query.Offset,
+		Bucket:   analyticspersistence.TimeBucket(query.Bucket),
+		Location: query.Location,
 		Filter: analyticspersistence.AnalyticsFilter{
 			Referrer:           query.Filter.Referrer,
+			ReferrerDomain:     query.Filter.ReferrerDomain,
+			Channel:            query.Filter.Channel,
 			Browser:            query.Filter.Browser,
 			Device:             query.Filter.Device,
 			OS:                 query.Filter.OS,
+			ScreenSize:         query.Filter.ScreenSize,
 			Page:               query.Filter.Page,
 			Country:            query.Filter.Country,
 			EventTypes:         eventTypes,
 			EventName:          query.Filter.EventName,
 			EventPath:          query.Filter.EventPath,
 			EventDefinitionIDs: query.Filter.EventDefinitionIDs,
+			UTMSource:          query.Filter.UTMSource,
+			UTMMedium:          query.Filter.UTMMedium,
+			UTMCampaign:        query.Filter.UTMCampaign,
+			UTMTerm:            query.Filter.UTMTerm,
+			UTMContent:         query.Filter.UTMContent,
 		},
 	}
 }
@@ -42,6 +56,48 @@ func pageStats(values []analyticspersistence.PageStats) []PageStats {
 	return result
 }
 
+func entryPageStats(values []analyticspersistence.EntryPageStats) []EntryPageStats {
+	result := make([]EntryPageStats, 0, len(values))
+	for _, value := range values {
+		bounceRate := 0.0
+		if value.Sessions > 0 {
+			bounceRate = float64(value.Bounces) / float64(value.Sessions) * 100
+		}
+		result = append(result, EntryPageStats{
+			Path: value.Path, Sessions: value.Sessions, Visitors: value.Visitors, BounceRate: bounceRate,
+		})
+	}
+	return result
+}
+
+func exitPageStats(values []analyticspersistence.ExitPageStats) []ExitPageStats {
+	result := make([]ExitPageStats, 0, len(values))
+	for _, value := range values {
+		exitRate := 0.0
+		if value.Views > 0 {
+			exitRate = float64(value.Exits) / float64(value.Views) * 100
+		}
+		result = append(result, ExitPageStats{
+			Path: value.Path, Exits: value.Exits, Visitors: value.Visitors, ExitRate: exitRate,
+		})
+	}
+	return result
+}
+
+func utmStats(values []analyticspersistence.UTMStats) []UTMStats {
+	result := make([]UTMStats, 0, len(values))
+	for _, value := range values {
+		bounceRate := 0.0
+		if value.Sessions > 0 {
+			bounceRate = float64(value.Bounces) / float64(value.Sessions) * 100
+		}
+		result = append(result, UTMStats{
+			Value: value.Value, Sessions: value.Sessions, Visitors: value.Visitors, BounceRate: bounceRate,
+		})
+	}
+	return result
+}
+
 func referrerStats(values []analyticspersistence.ReferrerStats) []ReferrerStats {
 	result := make([]ReferrerStats, 0, len(values))
 	for _, value := range values {
@@ -52,6 +108,16 @@ func referrerStats(values []analyticspersistence.ReferrerStats) []ReferrerStats
 	return result
 }
 
+func channelStats(values []analyticspersistence.ChannelStats) []ChannelStats {
+	result := make([]ChannelStats, 0, len(values))
+	for _, value := range values {
+		result = append(result, ChannelStats{
+			Channel: value.Channel.String(), Visitors: value.Visitors,
+		})
+	}
+	return result
+}
+
 func browserStats(values []analyticspersistence.BrowserStats) []BrowserStats {
 	result := make([]BrowserStats, 0, len(values))
 	for _, value := range values {
@@ -82,6 +148,16 @@ func operatingSystemStats(values []analyticspersistence.OperatingSystemStats) []
 	return result
 }
 
+func screenSizeStats(values []analyticspersistence.ScreenSizeStats) []ScreenSizeStats {
+	result := make([]ScreenSizeStats, 0, len(values))
+	for _, value := range values {
+		result = append(result, ScreenSizeStats{
+			ScreenSize: value.ScreenSize.String(), Visitors: value.Visitors,
+		})
+	}
+	return result
+}
+
 func countryStats(values []analyticspersistence.CountryStats) []CountryStats {
 	result := make([]CountryStats, 0, len(values))
 	for _, value := range values {
@@ -92,11 +168,70 @@ func countryStats(values []analyticspersistence.CountryStats) []CountryStats {
 	return result
 }
 
-func timeSeriesStats(values []analyticspersistence.DailyVisitorStats) []TimeSeriesStats {
+func goalStats(siteID int64, values []analyticspersistence.GoalStats, visitors int) []GoalStats {
+	result := make([]GoalStats, 0, len(values))
+	for _, value := range values {
+		conversionRate := 0.0
+		if visitors > 0 {
+			conversionRate = float64(value.Conversions) / float64(visitors) * 100
+		}
+		result = append(result, GoalStats{
+			Goal: goal.Goal{
+				ID:        value.GoalID,
+				SiteID:    siteID,
+				Name:      value.Name,
+				Type:      goal.Type(value.Type),
+				Value:     value.Value,
+				CreatedAt: value.CreatedAt,
+				UpdatedAt: value.UpdatedAt,
+			},
+			Conversions:    value.Conversions,
+			ConversionRate: conversionRate,
+		})
+	}
+	return result
+}
+
+func funnelSteps(steps []funnel.Step) []analyticspersistence.FunnelStep {
+	result := make([]analyticspersistence.FunnelStep, 0, len(steps))
+	for _, step := range steps {
+		result = append(result, analyticspersistence.FunnelStep{
+			Type:  funnelpersistence.StepType(step.Type),
+			Value: step.Value,
+		})
+	}
+	return result
+}
+
+func funnelStepStats(steps []funnel.Step, visitors []int) []FunnelStepStats {
+	result := make([]FunnelStepStats, 0, len(steps))
+	for index, step := range steps {
+		stats := FunnelStepStats{Step: step, Visitors: visitors[index]}
+		if index == 0 {
+			if stats.Visitors > 0 {
+				stats.ConversionRate = 100
+			}
+			result = append(result, stats)
+			continue
+		}
+		if first := visitors[0]; first > 0 {
+			stats.ConversionRate = float64(stats.Visitors) / float64(first) * 100
+		}
+		if previous := visitors[index-1]; previous > 0 {
+			stats.DropOff = previous - stats.Visitors
+			stats.DropOffRate = float64(stats.DropOff) / float64(previous) * 100
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
+func timeSeriesStats(values []analyticspersistence.DailyVisitorStats, bucket TimeBucket, loc *time.Location) []TimeSeriesStats {
 	result := make([]TimeSeriesStats, 0, len(values))
 	for _, value := range values {
 		result = append(result, TimeSeriesStats{
 			DateBucket: value.DateBucket,
+			Date:       timeBucketStart(value.DateBucket, bucket, loc),
 			Visitors:   value.Visitors,
 			PageViews:  value.PageViews,
 			Sessions:   value.Sessions,
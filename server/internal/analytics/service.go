@@ -91,6 +91,11 @@ type CollectInput struct {
 	UTMSource   string
 	UTMMedium   string
 	UTMCampaign string
+	UTMTerm     string
+	UTMContent  string
+	// ViewportWidth is the visitor's viewport width in CSS pixels as reported by the tracker, or
+	// zero when unknown. It is an untrusted hint that only selects the screen size bucket.
+	ViewportWidth int
 }
 
 type EventInput struct {
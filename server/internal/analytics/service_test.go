@@ -2,6 +2,7 @@ package analytics
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"testing"
 
@@ -163,3 +164,96 @@ func TestCollectEventStoresNonStringProperties(t *testing.T) {
 		{Key: "retry", Value: "true"},
 	}, rows)
 }
+
+func TestCollectEventAcceptsBuiltinEventsWithoutDefinition(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	site := createAnalyticsIdentitySite(t, db)
+	eventDefinitionRepo := eventpersistence.New(db)
+	service := NewService(
+		analyticspersistence.New(db),
+		sitepersistence.New(db),
+		eventDefinitionRepo,
+		nil,
+		nil,
+		testAnalyticsIdentitySecret,
+	)
+
+	for _, url := range []string{"https://example.com/docs", "https://example.com/pricing"} {
+		err := service.CollectEvent(ctx, EventInput{
+			SiteKey:    site.PublicKey,
+			Name:       event.OutboundLinkEvent,
+			Path:       "/blog",
+			Properties: `{"url":"` + url + `"}`,
+			UserAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0",
+			IP:         "203.0.113.42",
+			Origin:     "https://identity.test",
+		})
+		require.NoError(t, err)
+	}
+	err := service.CollectEvent(ctx, EventInput{
+		SiteKey:   site.PublicKey,
+		Name:      "signup",
+		Path:      "/blog",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0",
+		IP:        "203.0.113.42",
+		Origin:    "https://identity.test",
+	})
+	require.NoError(t, err)
+
+	definition, err := eventDefinitionRepo.GetByName(ctx, site.ID, event.OutboundLinkEvent)
+	require.NoError(t, err)
+	require.Len(t, definition.Fields, 1)
+	require.Equal(t, "url", definition.Fields[0].Key)
+	_, err = eventDefinitionRepo.GetByName(ctx, site.ID, "signup")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	var values []string
+	err = db.NewSelect().
+		TableExpr("event_data evd").
+		ColumnExpr("evd.value").
+		Order("evd.value ASC").
+		Scan(ctx, &values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/docs", "https://example.com/pricing"}, values)
+}
+
+func TestCollectEventRecordsBuiltinEventsWithLegacyDefinition(t *testing.T) {
+	ctx := context.Background()
+	db := setupServiceTestDB(t)
+	site := createAnalyticsIdentitySite(t, db)
+	eventDefinitionRepo := eventpersistence.New(db)
+	// Sites seeded before the built-in events defined file_download with a required file field.
+	_, err := eventDefinitionRepo.Upsert(ctx, site.ID, event.FileDownloadEvent, []*event.Field{
+		{Key: "file", Type: event.FieldTypeString, Required: true, MaxLength: 500},
+		{Key: "url", Type: event.FieldTypeString, MaxLength: 16},
+	})
+	require.NoError(t, err)
+	service := NewService(
+		analyticspersistence.New(db),
+		sitepersistence.New(db),
+		eventDefinitionRepo,
+		nil,
+		nil,
+		testAnalyticsIdentitySecret,
+	)
+
+	err = service.CollectEvent(ctx, EventInput{
+		SiteKey:    site.PublicKey,
+		Name:       event.FileDownloadEvent,
+		Path:       "/docs",
+		Properties: `{"url":"https://example.com/files/report.pdf"}`,
+		UserAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0",
+		IP:         "203.0.113.42",
+		Origin:     "https://identity.test",
+	})
+	require.NoError(t, err)
+
+	var values []string
+	err = db.NewSelect().
+		TableExpr("event_data evd").
+		ColumnExpr("evd.value").
+		Scan(ctx, &values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/files/report.pdf"}, values)
+}
@@ -0,0 +1,45 @@
+package analytics
+
+import "time"
+
+// localTimeBucket numbers the wall-clock hour, day, ISO week, or month of t in loc,
+// matching the repository bucket keys.
+func localTimeBucket(t time.Time, bucket TimeBucket, loc *time.Location) int64 {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	_, offset := local.Zone()
+	seconds := t.Unix() + int64(offset)
+
+	switch bucket {
+	case TimeBucketHourly:
+		return seconds / 3600
+	case TimeBucketWeekly:
+		return (seconds/86400 + 3) / 7
+	case TimeBucketMonthly:
+		return int64(local.Year())*12 + int64(local.Month()) - 1
+	default:
+		return seconds / 86400
+	}
+}
+
+// timeBucketStart returns the wall-clock start of a bucket key in loc.
+func timeBucketStart(key int64, bucket TimeBucket, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var wall time.Time
+	switch bucket {
+	case TimeBucketHourly:
+		wall = time.Unix(key*3600, 0).UTC()
+	case TimeBucketWeekly:
+		wall = time.Unix((key*7-3)*86400, 0).UTC()
+	case TimeBucketMonthly:
+		return time.Date(int(key/12), time.Month(key%12+1), 1, 0, 0, 0, 0, loc)
+	default:
+		wall = time.Unix(key*86400, 0).UTC()
+	}
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), 0, 0, 0, loc)
+}
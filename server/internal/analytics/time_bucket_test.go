@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalTimeBucketRoundTripsWallClock(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	// 23:30 PDT is already the next UTC day.
+	evening := time.Date(2026, 3, 8, 23, 30, 0, 0, loc)
+	day := localTimeBucket(evening, TimeBucketDaily, loc)
+	require.Equal(t, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC).Unix()/86400, day)
+	require.Equal(t, time.Date(2026, 3, 8, 0, 0, 0, 0, loc), timeBucketStart(day, TimeBucketDaily, loc))
+
+	hour := localTimeBucket(evening, TimeBucketHourly, loc)
+	require.Equal(t, time.Date(2026, 3, 8, 23, 0, 0, 0, loc), timeBucketStart(hour, TimeBucketHourly, loc))
+
+	require.Equal(t, evening.Unix()/86400, localTimeBucket(evening, TimeBucketDaily, nil))
+}
+
+func TestCalendarTimeBucketsStartOnMondayAndFirstOfMonth(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	sunday := time.Date(2026, 3, 1, 23, 30, 0, 0, loc)
+	week := localTimeBucket(sunday, TimeBucketWeekly, loc)
+	require.Equal(t, time.Date(2026, 2, 23, 0, 0, 0, 0, loc), timeBucketStart(week, TimeBucketWeekly, loc))
+	require.Equal(t, week+1, localTimeBucket(sunday.Add(time.Hour), TimeBucketWeekly, loc))
+
+	month := localTimeBucket(sunday, TimeBucketMonthly, loc)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, loc), timeBucketStart(month, TimeBucketMonthly, loc))
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, loc), timeBucketStart(month-2, TimeBucketMonthly, loc))
+}
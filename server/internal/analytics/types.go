@@ -1,6 +1,11 @@
 package analytics
 
-import "time"
+import (
+	"time"
+
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/goal"
+)
 
 type EventType string
 
@@ -12,21 +17,31 @@ const (
 type TimeBucket string
 
 const (
-	TimeBucketDaily  TimeBucket = "daily"
-	TimeBucketHourly TimeBucket = "hourly"
+	TimeBucketDaily   TimeBucket = "daily"
+	TimeBucketHourly  TimeBucket = "hourly"
+	TimeBucketWeekly  TimeBucket = "weekly"
+	TimeBucketMonthly TimeBucket = "monthly"
 )
 
 type Filter struct {
 	Referrer           []string
+	ReferrerDomain     []string
+	Channel            []string
 	Browser            []string
 	Device             []string
 	OS                 []string
+	ScreenSize         []string
 	Page               []string
 	Country            []string
 	EventTypes         []EventType
 	EventName          []string
 	EventPath          []string
 	EventDefinitionIDs []int64
+	UTMSource          []string
+	UTMMedium          []string
+	UTMCampaign        []string
+	UTMTerm            []string
+	UTMContent         []string
 }
 
 type Query struct {
@@ -36,7 +51,9 @@ type Query struct {
 	Limit  int
 	Offset int
 	Bucket TimeBucket
-	Filter Filter
+	// Location selects the wall clock for time series buckets; nil means UTC.
+	Location *time.Location
+	Filter   Filter
 }
 
 type Overview struct {
@@ -53,11 +70,48 @@ type PageStats struct {
 	Visitors int
 }
 
+type EntryPageStats struct {
+	Path       string
+	Sessions   int
+	Visitors   int
+	BounceRate float64
+}
+
+type ExitPageStats struct {
+	Path     string
+	Exits    int
+	Visitors int
+	ExitRate float64
+}
+
+// UTMDimension selects which campaign parameter a UTM breakdown groups by.
+type UTMDimension string
+
+const (
+	UTMDimensionSource   UTMDimension = "source"
+	UTMDimensionMedium   UTMDimension = "medium"
+	UTMDimensionCampaign UTMDimension = "campaign"
+	UTMDimensionTerm     UTMDimension = "term"
+	UTMDimensionContent  UTMDimension = "content"
+)
+
+type UTMStats struct {
+	Value      string
+	Sessions   int
+	Visitors   int
+	BounceRate float64
+}
+
 type ReferrerStats struct {
 	Referrer string
 	Visitors int
 }
 
+type ChannelStats struct {
+	Channel  string
+	Visitors int
+}
+
 type BrowserStats struct {
 	Browser  string
 	Visitors int
@@ -73,13 +127,35 @@ type OperatingSystemStats struct {
 	Visitors int
 }
 
+type ScreenSizeStats struct {
+	ScreenSize string
+	Visitors   int
+}
+
 type CountryStats struct {
 	CountryCode string
 	Visitors    int
 }
 
+type GoalStats struct {
+	Goal           goal.Goal
+	Conversions    int
+	ConversionRate float64
+}
+
+// FunnelStepStats compares a step with the first step (conversion) and the previous step (drop-off).
+type FunnelStepStats struct {
+	Step           funnel.Step
+	Visitors       int
+	ConversionRate float64
+	DropOff        int
+	DropOffRate    float64
+}
+
 type TimeSeriesStats struct {
+	// DateBucket numbers local wall-clock days or hours since the epoch; Date is its start in the query location.
 	DateBucket int64
+	Date       time.Time
 	Visitors   int
 	PageViews  int
 	Sessions   int
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ apitoken.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func orderedSites(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("ats.site_id ASC")
+}
+
+func (r *Repository) ListByUser(ctx context.Context, userID int64) ([]*apitoken.Token, error) {
+	var rows []*APIToken
+	if err := r.db.NewSelect().
+		Model(&rows).
+		Where("at.user_id = ?", userID).
+		Relation("Sites", orderedSites).
+		Order("at.created_at DESC", "at.id DESC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get API tokens by user: %w", err)
+	}
+	result := make([]*apitoken.Token, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, tokenFromModel(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*APIToken)(nil)).
+		Where("user_id = ?", userID).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count API tokens: %w", err)
+	}
+	return count, nil
+}
+
+func (r *Repository) Create(ctx context.Context, value *apitoken.Token, secretHash string) error {
+	row := &APIToken{
+		UserID:     value.UserID,
+		Name:       value.Name,
+		Prefix:     value.Prefix,
+		TokenHash:  secretHash,
+		SiteScoped: value.SiteScoped,
+		CreatedAt:  time.Now(),
+	}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(row).Exec(ctx); err != nil {
+			return fmt.Errorf("insert API token: %w", err)
+		}
+		if len(value.SiteIDs) == 0 {
+			return nil
+		}
+		sites := make([]*APITokenSite, 0, len(value.SiteIDs))
+		for _, siteID := range value.SiteIDs {
+			sites = append(sites, &APITokenSite{APITokenID: row.ID, SiteID: siteID})
+		}
+		if _, err := tx.NewInsert().Model(&sites).Exec(ctx); err != nil {
+			return fmt.Errorf("insert API token sites: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API token transaction: %w", err)
+	}
+	value.ID = row.ID
+	value.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *Repository) GetByHash(ctx context.Context, secretHash string) (*apitoken.Credential, error) {
+	row := new(APIToken)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("at.token_hash = ?", secretHash).
+		Relation("Sites", orderedSites).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apitoken.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get API token by hash: %w", err)
+	}
+
+	var owner struct {
+		Username string
+		Role     string
+	}
+	err = r.db.NewSelect().
+		Table("users").
+		Column("username", "role").
+		Where("id = ?", row.UserID).
+		Scan(ctx, &owner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apitoken.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get API token owner: %w", err)
+	}
+
+	return &apitoken.Credential{
+		Token:    tokenFromModel(row),
+		Username: owner.Username,
+		Role:     owner.Role,
+	}, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, userID, id int64) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*APITokenSite)(nil)).
+			Where("api_token_id IN (SELECT id FROM api_tokens WHERE id = ? AND user_id = ?)", id, userID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("delete API token sites: %w", err)
+		}
+		result, err := tx.NewDelete().
+			Model((*APIToken)(nil)).
+			Where("id = ?", id).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("delete API token: %w", err)
+		}
+		return requireAffectedToken(result, "delete API token")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete API token transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	if _, err := r.db.NewUpdate().
+		Model((*APIToken)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update API token last use: %w", err)
+	}
+	return nil
+}
+
+func requireAffectedToken(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, apitoken.ErrTokenNotFound)
+	}
+	return nil
+}
+
+func tokenFromModel(row *APIToken) *apitoken.Token {
+	value := &apitoken.Token{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		SiteScoped: row.SiteScoped,
+		SiteIDs:    make([]int64, 0, len(row.Sites)),
+		CreatedAt:  row.CreatedAt,
+	}
+	if !row.LastUsedAt.IsZero() {
+		lastUsedAt := row.LastUsedAt
+		value.LastUsedAt = &lastUsedAt
+	}
+	for _, site := range row.Sites {
+		if site != nil {
+			value.SiteIDs = append(value.SiteIDs, site.SiteID)
+		}
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/lovely-eye/server/internal/site"
+	"github.com/stretchr/testify/require"
+)
+
+type allowAllSites struct{}
+
+func (allowAllSites) RequireRole(context.Context, int64, int64, site.Role) error { return nil }
+
+func TestRepository_CreateAuthenticateRevokeToken(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	site := createTestSite(t, db)
+	service := apitoken.NewService(New(db), allowAllSites{})
+	ctx := context.Background()
+
+	token, secret, err := service.Create(ctx, apitoken.CreateInput{UserID: site.UserID, Name: " Reporting ", SiteIDs: []int64{site.ID, site.ID}})
+	require.NoError(t, err)
+	require.NotZero(t, token.ID)
+	require.Equal(t, "Reporting", token.Name)
+	require.Equal(t, []int64{site.ID}, token.SiteIDs)
+	require.True(t, len(secret) > len(token.Prefix))
+	require.Equal(t, token.Prefix, secret[:len(token.Prefix)])
+
+	claims, err := service.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	require.Equal(t, site.UserID, claims.UserID)
+	require.Equal(t, "token-test", claims.Username)
+	require.Equal(t, token.ID, claims.APITokenID)
+	require.True(t, claims.IsAPIToken())
+	require.True(t, claims.CanAccessSite(site.ID))
+	require.False(t, claims.CanAccessSite(site.ID+1))
+
+	tokens, err := service.List(ctx, site.UserID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, []int64{site.ID}, tokens[0].SiteIDs)
+	require.NotNil(t, tokens[0].LastUsedAt)
+
+	_, err = service.Authenticate(ctx, secret+"x")
+	require.True(t, errors.Is(err, apitoken.ErrInvalidToken))
+
+	err = service.Revoke(ctx, site.UserID+1, token.ID)
+	require.True(t, errors.Is(err, apitoken.ErrTokenNotFound))
+	require.NoError(t, service.Revoke(ctx, site.UserID, token.ID))
+
+	_, err = service.Authenticate(ctx, secret)
+	require.True(t, errors.Is(err, apitoken.ErrInvalidToken))
+}
+
+func TestRepository_UnscopedTokenReadsAllSites(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	site := createTestSite(t, db)
+	service := apitoken.NewService(New(db), allowAllSites{})
+	ctx := context.Background()
+
+	_, secret, err := service.Create(ctx, apitoken.CreateInput{UserID: site.UserID, Name: "All sites"})
+	require.NoError(t, err)
+
+	claims, err := service.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	require.Nil(t, claims.SiteIDs)
+	require.True(t, claims.CanAccessSite(site.ID))
+	require.True(t, claims.CanAccessSite(site.ID+1))
+}
@@ -0,0 +1,30 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type APIToken struct {
+	bun.BaseModel `bun:"table:api_tokens,alias:at"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	UserID     int64     `bun:"user_id,notnull"`
+	Name       string    `bun:"name,notnull,type:varchar(100)"`
+	Prefix     string    `bun:"prefix,notnull,type:varchar(16)"`
+	TokenHash  string    `bun:"token_hash,unique,notnull,type:varchar(64)"`
+	SiteScoped bool      `bun:"site_scoped,notnull,default:false"`
+	LastUsedAt time.Time `bun:"last_used_at,nullzero"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+
+	Sites []*APITokenSite `bun:"rel:has-many,join:id=api_token_id"`
+}
+
+type APITokenSite struct {
+	bun.BaseModel `bun:"table:api_token_sites,alias:ats"`
+
+	ID         int64 `bun:"id,pk,autoincrement"`
+	APITokenID int64 `bun:"api_token_id,notnull,unique:api_token_sites_api_token_id_site_id"`
+	SiteID     int64 `bun:"site_id,notnull,unique:api_token_sites_api_token_id_site_id"`
+}
@@ -0,0 +1,183 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	platformsecret "github.com/lovely-eye/server/internal/platform/secret"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+const (
+	maxTokenNameLength = 100
+	maxTokenSites      = 100
+	maxTokensPerUser   = 50
+
+	// secretPrefix makes leaked tokens easy to recognise in logs and secret scanners.
+	secretPrefix      = "le_"
+	displayPrefixSize = len(secretPrefix) + 8
+
+	// lastUsedResolution bounds how often an active token rewrites its last-used time.
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrTokenNotFound     = errors.New("API token not found")
+	ErrInvalidToken      = errors.New("invalid API token")
+	ErrInvalidTokenName  = errors.New("invalid API token name")
+	ErrInvalidTokenSites = errors.New("API token site list exceeds 100 entries")
+	ErrTooManyTokens     = errors.New("API token limit of 50 per user reached")
+)
+
+// Token is a read-only credential for programmatic stats access. Only a hash of the secret is stored.
+type Token struct {
+	ID     int64
+	UserID int64
+	Name   string
+	Prefix string
+	// SiteScoped limits the token to SiteIDs; unscoped tokens read every site the owner can access.
+	SiteScoped bool
+	SiteIDs    []int64
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// Credential is a stored token together with the account it authenticates as.
+type Credential struct {
+	Token    *Token
+	Username string
+	Role     string
+}
+
+type Store interface {
+	ListByUser(ctx context.Context, userID int64) ([]*Token, error)
+	CountByUser(ctx context.Context, userID int64) (int, error)
+	Create(ctx context.Context, token *Token, secretHash string) error
+	GetByHash(ctx context.Context, secretHash string) (*Credential, error)
+	Delete(ctx context.Context, userID, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// SiteAuthorizer confirms that a user may read a site before it is added to a token scope.
+type SiteAuthorizer interface {
+	RequireRole(ctx context.Context, id, userID int64, role site.Role) error
+}
+
+type Service struct {
+	store Store
+	sites SiteAuthorizer
+}
+
+func NewService(store Store, sites SiteAuthorizer) *Service {
+	return &Service{store: store, sites: sites}
+}
+
+type CreateInput struct {
+	UserID int64
+	Name   string
+	// SiteIDs limits the token to these sites; empty means all owned sites.
+	SiteIDs []int64
+}
+
+func (s *Service) List(ctx context.Context, userID int64) ([]*Token, error) {
+	tokens, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Create stores a new token and returns it with its secret, which cannot be recovered later.
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Token, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxTokenNameLength {
+		return nil, "", ErrInvalidTokenName
+	}
+
+	siteIDs := slices.Clone(input.SiteIDs)
+	slices.Sort(siteIDs)
+	siteIDs = slices.Compact(siteIDs)
+	if len(siteIDs) > maxTokenSites {
+		return nil, "", ErrInvalidTokenSites
+	}
+	for _, siteID := range siteIDs {
+		if err := s.sites.RequireRole(ctx, siteID, input.UserID, site.RoleViewer); err != nil {
+			return nil, "", fmt.Errorf("failed to authorize API token site: %w", err)
+		}
+	}
+
+	count, err := s.store.CountByUser(ctx, input.UserID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to count API tokens: %w", err)
+	}
+	if count >= maxTokensPerUser {
+		return nil, "", ErrTooManyTokens
+	}
+
+	secret, err := platformsecret.Generate(secretPrefix)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API token secret: %w", err)
+	}
+	token := &Token{
+		UserID:     input.UserID,
+		Name:       name,
+		Prefix:     secret[:displayPrefixSize],
+		SiteScoped: len(siteIDs) > 0,
+		SiteIDs:    siteIDs,
+	}
+	if err := s.store.Create(ctx, token, platformsecret.Hash(secret)); err != nil {
+		return nil, "", fmt.Errorf("failed to create API token: %w", err)
+	}
+	return token, secret, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, userID, id int64) error {
+	if err := s.store.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return ErrTokenNotFound
+		}
+		return fmt.Errorf("failed to revoke API token: %w", err)
+	}
+	return nil
+}
+
+// Authenticate resolves a presented secret into read-only claims for its owner.
+func (s *Service) Authenticate(ctx context.Context, secret string) (*auth.Claims, error) {
+	if !strings.HasPrefix(secret, secretPrefix) || len(secret) <= displayPrefixSize {
+		return nil, ErrInvalidToken
+	}
+
+	credential, err := s.store.GetByHash(ctx, platformsecret.Hash(secret))
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get API token: %w", err)
+	}
+
+	token := credential.Token
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.store.TouchLastUsed(ctx, token.ID, now); err != nil {
+			// Usage tracking is informational and must not reject a valid credential.
+			slog.WarnContext(ctx, "failed to record API token use", "token_id", token.ID, "error", err)
+		}
+	}
+
+	claims := &auth.Claims{
+		UserID:     token.UserID,
+		Username:   credential.Username,
+		Role:       credential.Role,
+		APITokenID: token.ID,
+	}
+	if token.SiteScoped {
+		claims.SiteIDs = append([]int64{}, token.SiteIDs...)
+	}
+	return claims, nil
+}
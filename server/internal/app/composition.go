@@ -9,19 +9,32 @@ import (
 
 	"github.com/lovely-eye/server/internal/analytics"
 	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	"github.com/lovely-eye/server/internal/apitoken"
+	apitokenpersistence "github.com/lovely-eye/server/internal/apitoken/persistence"
+	"github.com/lovely-eye/server/internal/audit"
+	auditpersistence "github.com/lovely-eye/server/internal/audit/persistence"
 	"github.com/lovely-eye/server/internal/auth"
 	authpersistence "github.com/lovely-eye/server/internal/auth/persistence"
 	"github.com/lovely-eye/server/internal/country"
 	countrypersistence "github.com/lovely-eye/server/internal/country/persistence"
 	"github.com/lovely-eye/server/internal/event"
 	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	"github.com/lovely-eye/server/internal/funnel"
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
 	geoipcore "github.com/lovely-eye/server/internal/geoip"
 	geoipservice "github.com/lovely-eye/server/internal/geoip/service"
+	"github.com/lovely-eye/server/internal/goal"
+	goalpersistence "github.com/lovely-eye/server/internal/goal/persistence"
+	"github.com/lovely-eye/server/internal/ingestkey"
+	ingestkeypersistence "github.com/lovely-eye/server/internal/ingestkey/persistence"
 	"github.com/lovely-eye/server/internal/platform/config"
 	"github.com/lovely-eye/server/internal/platform/database"
+	"github.com/lovely-eye/server/internal/share"
+	sharepersistence "github.com/lovely-eye/server/internal/share/persistence"
 	"github.com/lovely-eye/server/internal/site"
 	sitepersistence "github.com/lovely-eye/server/internal/site/persistence"
 	transporthttp "github.com/lovely-eye/server/internal/transport/http"
+	"github.com/lovely-eye/server/internal/transport/http/oidc"
 	"github.com/uptrace/bun"
 )
 
@@ -57,13 +70,23 @@ func buildServices(ctx context.Context, cfg config.Config, db *bun.DB) (transpor
 	analyticsRepo := analyticspersistence.New(db)
 	countryRepo := countrypersistence.New(db)
 	eventDefinitionRepo := eventpersistence.New(db)
-	authService := auth.NewService(userRepo, authConfig(cfg))
+	goalRepo := goalpersistence.New(db)
+	funnelRepo := funnelpersistence.New(db)
+	apiTokenRepo := apitokenpersistence.New(db)
+	shareLinkRepo := sharepersistence.New(db)
+	ingestKeyRepo := ingestkeypersistence.New(db)
+	auditService := audit.NewService(auditpersistence.New(db))
+	authService := auth.NewService(userRepo, userRepo, authConfig(cfg))
+	authService.SetAuditRecorder(auditService)
 	geoIPService := geoipservice.NewService(geoipcore.Config{
 		DBPath:            cfg.GeoIP.DBPath,
 		DownloadURL:       cfg.GeoIP.DownloadURL,
 		MaxMindLicenseKey: cfg.GeoIP.MaxMindLicenseKey,
 	})
 	siteService := site.NewService(siteRepo)
+	siteService.SetAuditRecorder(auditService)
+	eventService := event.NewService(eventDefinitionRepo)
+	eventService.SetAuditRecorder(auditService)
 	countryService := country.NewService(countryRepo, geoIPService)
 	analyticsService := analytics.NewService(
 		analyticsRepo,
@@ -81,7 +104,22 @@ func buildServices(ctx context.Context, cfg config.Config, db *bun.DB) (transpor
 		Site:            siteService,
 		Analytics:       analyticsService,
 		Country:         countryService,
-		EventDefinition: event.NewService(eventDefinitionRepo),
+		EventDefinition: eventService,
+		Goal:            goal.NewService(goalRepo),
+		Funnel:          funnel.NewService(funnelRepo),
+		APIToken:        apitoken.NewService(apiTokenRepo, siteService),
+		Share:           share.NewService(shareLinkRepo, siteService, cfg.Auth.JWTSecret),
+		IngestKey:       ingestkey.NewService(ingestKeyRepo, siteService),
+		Audit:           auditService,
+	}
+	if cfg.Auth.OIDC.Enabled() {
+		result.OIDC = oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.Auth.OIDC.IssuerURL,
+			ClientID:     cfg.Auth.OIDC.ClientID,
+			ClientSecret: cfg.Auth.OIDC.ClientSecret,
+			RedirectURL:  cfg.Auth.OIDC.RedirectURL,
+			Scopes:       cfg.Auth.OIDC.Scopes,
+		}, nil)
 	}
 	if err := analyticsService.SyncGeoIPRequirement(ctx); err != nil {
 		// Country analytics is optional at startup; the retained status keeps the failure actionable in admin UI.
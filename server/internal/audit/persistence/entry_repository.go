@@ -0,0 +1,138 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/audit"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ audit.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create stores an entry with its changes. A missing ActorName is filled in from the users table
+// so that the entry still names the actor after the account is deleted.
+func (r *Repository) Create(ctx context.Context, value *audit.Entry) error {
+	if value.ActorName == "" && value.ActorID != 0 {
+		err := r.db.NewSelect().
+			Table("users").
+			Column("username").
+			Where("id = ?", value.ActorID).
+			Scan(ctx, &value.ActorName)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get audit actor: %w", err)
+		}
+	}
+
+	row := &Entry{
+		ActorID:      value.ActorID,
+		ActorName:    value.ActorName,
+		Action:       string(value.Action),
+		SiteID:       value.SiteID,
+		TargetUserID: value.TargetUserID,
+		CreatedAt:    time.Now(),
+	}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(row).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to insert audit entry: %w", err)
+		}
+		if len(value.Changes) == 0 {
+			return nil
+		}
+		changes := make([]*Change, 0, len(value.Changes))
+		for _, change := range value.Changes {
+			changes = append(changes, &Change{
+				EntryID:     row.ID,
+				Field:       change.Field,
+				BeforeValue: encodedValue(change.Before),
+				AfterValue:  encodedValue(change.After),
+			})
+		}
+		if _, err := tx.NewInsert().Model(&changes).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to insert audit changes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	value.ID = row.ID
+	value.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *Repository) List(ctx context.Context, filter audit.ListFilter) ([]*audit.Entry, int, error) {
+	var rows []*Entry
+	query := r.db.NewSelect().
+		Model(&rows).
+		Relation("Changes", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("ac.id ASC")
+		})
+	if filter.SiteID != 0 {
+		query = query.Where("ae.site_id = ?", filter.SiteID)
+	}
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+	if err := query.
+		Order("ae.created_at DESC", "ae.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(ctx); err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	result := make([]*audit.Entry, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, entryFromModel(row))
+	}
+	return result, total, nil
+}
+
+func entryFromModel(row *Entry) *audit.Entry {
+	value := &audit.Entry{
+		ID:           row.ID,
+		ActorID:      row.ActorID,
+		ActorName:    row.ActorName,
+		Action:       audit.Action(row.Action),
+		SiteID:       row.SiteID,
+		TargetUserID: row.TargetUserID,
+		CreatedAt:    row.CreatedAt,
+	}
+	for _, change := range row.Changes {
+		value.Changes = append(value.Changes, audit.Change{
+			Field:  change.Field,
+			Before: decodedValue(change.BeforeValue),
+			After:  decodedValue(change.AfterValue),
+		})
+	}
+	return value
+}
+
+func encodedValue(value json.RawMessage) *string {
+	if value == nil {
+		return nil
+	}
+	encoded := string(value)
+	return &encoded
+}
+
+func decodedValue(value *string) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	return json.RawMessage(*value)
+}
@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/audit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_RecordAndListEntries(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	user := createTestUser(t, db)
+	service := audit.NewService(New(db))
+	ctx := context.Background()
+
+	update := audit.Entry{ActorID: user.ID, Action: audit.ActionSiteUpdate, SiteID: 7}
+	update.Diff("name", "Old", "New")
+	update.Diff("trackCountry", true, true)
+	service.Record(ctx, update)
+
+	deletion := audit.Entry{Action: audit.ActionEventDefinitionDelete, SiteID: 7}
+	deletion.Diff("name", "signup", nil)
+	service.Record(ctx, deletion)
+
+	service.Record(ctx, audit.Entry{ActorID: user.ID, Action: audit.ActionUserLogin, TargetUserID: user.ID})
+
+	entries, total, err := service.List(ctx, audit.ListFilter{SiteID: 7, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, audit.ActionEventDefinitionDelete, entries[0].Action)
+	require.Zero(t, entries[0].ActorID)
+	require.Empty(t, entries[0].ActorName)
+	require.Equal(t, []audit.Change{{Field: "name", Before: []byte(`"signup"`)}}, entries[0].Changes)
+
+	require.Equal(t, audit.ActionSiteUpdate, entries[1].Action)
+	require.Equal(t, "audit-test", entries[1].ActorName, "the actor name is captured when recording")
+	require.Equal(t, []audit.Change{{Field: "name", Before: []byte(`"Old"`), After: []byte(`"New"`)}}, entries[1].Changes)
+
+	entries, total, err = service.List(ctx, audit.ListFilter{Limit: 1, Offset: 0})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, entries, 1)
+	require.Equal(t, audit.ActionUserLogin, entries[0].Action)
+	require.Equal(t, user.ID, entries[0].TargetUserID)
+	require.Empty(t, entries[0].Changes)
+}
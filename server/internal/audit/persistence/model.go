@@ -0,0 +1,34 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Entry keeps site and user IDs without foreign keys so that entries survive the deletion of
+// the sites and accounts they describe.
+type Entry struct {
+	bun.BaseModel `bun:"table:audit_entries,alias:ae"`
+
+	ID           int64     `bun:"id,pk,autoincrement"`
+	ActorID      int64     `bun:"actor_id,nullzero"`
+	ActorName    string    `bun:"actor_name,notnull,type:varchar(128),default:''"`
+	Action       string    `bun:"action,notnull,type:varchar(64)"`
+	SiteID       int64     `bun:"site_id,nullzero"`
+	TargetUserID int64     `bun:"target_user_id,nullzero"`
+	CreatedAt    time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+
+	Changes []*Change `bun:"rel:has-many,join:id=entry_id"`
+}
+
+// Change holds JSON-encoded values; a NULL value means the field did not exist on that side.
+type Change struct {
+	bun.BaseModel `bun:"table:audit_changes,alias:ac"`
+
+	ID          int64   `bun:"id,pk,autoincrement"`
+	EntryID     int64   `bun:"entry_id,notnull"`
+	Field       string  `bun:"field,notnull,type:varchar(64)"`
+	BeforeValue *string `bun:"before_value,type:text"`
+	AfterValue  *string `bun:"after_value,type:text"`
+}
@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionSiteUpdate            Action = "site.update"
+	ActionSiteDelete            Action = "site.delete"
+	ActionSiteKeyRegenerate     Action = "site.regenerate_key"
+	ActionEventDefinitionUpsert Action = "event_definition.upsert"
+	ActionEventDefinitionDelete Action = "event_definition.delete"
+	ActionUserRoleChange        Action = "user.role_change"
+	ActionUserLogin             Action = "user.login"
+)
+
+// Entry records one administrative change. Site and user IDs are kept without foreign keys so
+// that the trail outlives deleted sites and accounts.
+type Entry struct {
+	ID int64
+	// ActorID is zero for changes made by the server itself, such as seeding.
+	ActorID int64
+	// ActorName is the actor's username when the entry was recorded.
+	ActorName    string
+	Action       Action
+	SiteID       int64
+	TargetUserID int64
+	Changes      []Change
+	CreatedAt    time.Time
+}
+
+// Change is the JSON-encoded value of one field before and after an action. A nil value means
+// that the field did not exist on that side.
+type Change struct {
+	Field  string
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// Diff appends a change for field unless before and after encode to the same JSON. Pass nil
+// for a side on which the field does not exist.
+func (e *Entry) Diff(field string, before, after any) {
+	beforeJSON, beforeErr := encodeValue(before)
+	afterJSON, afterErr := encodeValue(after)
+	if beforeErr != nil || afterErr != nil {
+		slog.Warn("failed to encode audit change", "field", field, "error", errors.Join(beforeErr, afterErr))
+		return
+	}
+	if string(beforeJSON) == string(afterJSON) {
+		return
+	}
+	e.Changes = append(e.Changes, Change{Field: field, Before: beforeJSON, After: afterJSON})
+}
+
+func encodeValue(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+type ListFilter struct {
+	// SiteID limits the log to one site; zero lists every entry and needs an admin.
+	SiteID int64
+	Limit  int
+	Offset int
+}
+
+type Store interface {
+	Create(ctx context.Context, entry *Entry) error
+	List(ctx context.Context, filter ListFilter) ([]*Entry, int, error)
+}
+
+// Recorder is implemented by Service and consumed by the features whose changes are audited.
+type Recorder interface {
+	Record(ctx context.Context, entry Entry)
+}
+
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// Record stores an entry. The change it describes has already happened, so a failure is
+// logged rather than returned.
+func (s *Service) Record(ctx context.Context, entry Entry) {
+	if err := s.store.Create(ctx, &entry); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit entry",
+			"action", entry.Action, "actor_id", entry.ActorID, "error", err)
+	}
+}
+
+// List returns the newest entries first. Callers check that the reader is an admin, or a site
+// owner when the filter names a site.
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]*Entry, int, error) {
+	entries, total, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, total, nil
+}
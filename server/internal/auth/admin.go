@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lovely-eye/server/internal/audit"
+)
+
+var (
+	ErrAdminRequired       = errors.New("admin role required")
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrInvalidRole         = errors.New("invalid user role")
+	ErrLastAdmin           = errors.New("at least one admin must remain")
+	ErrCannotDeleteSelf    = errors.New("admins cannot delete their own account")
+	ErrInvalidSiteTransfer = errors.New("sites must be transferred to another existing user")
+)
+
+type CreateUserInput struct {
+	Username string
+	Password string
+	// Role defaults to RoleUser when empty.
+	Role string
+}
+
+// ListUsers returns every account for an admin.
+func (s *Service) ListUsers(ctx context.Context, actorID int64, limit, offset int) ([]*User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	storedUsers, err := s.userStore.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]*User, 0, len(storedUsers))
+	for _, storedUser := range storedUsers {
+		users = append(users, publicUser(storedUser))
+	}
+	return users, nil
+}
+
+// CreateUser adds an account regardless of the self-registration policy.
+func (s *Service) CreateUser(ctx context.Context, actorID int64, input CreateUserInput) (*User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	username, err := normalizeUsername(input.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePassword(input.Password); err != nil {
+		return nil, err
+	}
+	role := RoleUser
+	if input.Role != "" {
+		if role, err = validateRole(input.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	hashedPassword, err := hashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+	storedUser := &StoredUser{
+		Username:     username,
+		PasswordHash: hashedPassword,
+		Role:         role,
+	}
+	if err := s.userStore.CreateUser(ctx, storedUser); err != nil {
+		if errors.Is(err, ErrUserExists) {
+			return nil, ErrUserExists
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return publicUser(storedUser), nil
+}
+
+// ResetPassword replaces another user's password without knowing the current one.
+func (s *Service) ResetPassword(ctx context.Context, actorID, id int64, password string) error {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return err
+	}
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.userStore.UpdatePasswordHash(ctx, id, hashedPassword); err != nil {
+		return classifyManagedUserError("reset user password", err)
+	}
+	return s.RevokeAllSessions(ctx, id)
+}
+
+// ResetTwoFactor turns off a user's second factor, for example after a lost device.
+func (s *Service) ResetTwoFactor(ctx context.Context, actorID, id int64) error {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return err
+	}
+	if err := s.userStore.DisableTOTP(ctx, id); err != nil {
+		return classifyManagedUserError("reset user two-factor authentication", err)
+	}
+	return nil
+}
+
+// SetRole promotes or demotes a user. The last admin cannot be demoted.
+func (s *Service) SetRole(ctx context.Context, actorID, id int64, role string) (*User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	validatedRole, err := validateRole(role)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, classifyManagedUserError("get user by id", err)
+	}
+	if err := s.userStore.UpdateRole(ctx, id, validatedRole); err != nil {
+		return nil, classifyManagedUserError("update user role", err)
+	}
+	storedUser, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, classifyManagedUserError("get user by id", err)
+	}
+	s.recordRoleChange(ctx, actorID, storedUser, previous.Role)
+	return publicUser(storedUser), nil
+}
+
+// DeleteUser removes another account. Sites it created move to transferSitesTo, or to the
+// acting admin when zero, so analytics are never deleted as a side effect.
+func (s *Service) DeleteUser(ctx context.Context, actorID, id, transferSitesTo int64) error {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return err
+	}
+	if id == actorID {
+		return ErrCannotDeleteSelf
+	}
+	if transferSitesTo == 0 {
+		transferSitesTo = actorID
+	}
+	if transferSitesTo == id {
+		return ErrInvalidSiteTransfer
+	}
+	if _, err := s.userStore.GetByID(ctx, transferSitesTo); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrInvalidSiteTransfer
+		}
+		return fmt.Errorf("failed to get site transfer user: %w", err)
+	}
+	if err := s.userStore.Delete(ctx, id, transferSitesTo); err != nil {
+		return classifyManagedUserError("delete user", err)
+	}
+	return nil
+}
+
+// IsAdmin reads the stored role so a demotion applies before the access token expires.
+func (s *Service) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	actor, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get acting user: %w", err)
+	}
+	return actor.Role == RoleAdmin, nil
+}
+
+func (s *Service) requireAdmin(ctx context.Context, actorID int64) error {
+	admin, err := s.IsAdmin(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return ErrAdminRequired
+	}
+	return nil
+}
+
+// recordRoleChange audits a role change of user made by actorID, which is zero when an
+// identity provider synced the role.
+func (s *Service) recordRoleChange(ctx context.Context, actorID int64, user *StoredUser, previousRole string) {
+	entry := audit.Entry{
+		ActorID:      actorID,
+		Action:       audit.ActionUserRoleChange,
+		TargetUserID: user.ID,
+	}
+	entry.Diff("role", previousRole, user.Role)
+	if len(entry.Changes) > 0 {
+		s.recordAudit(ctx, entry)
+	}
+}
+
+func validateRole(role string) (string, error) {
+	switch role {
+	case RoleAdmin, RoleUser:
+		return role, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// classifyManagedUserError keeps a missing target account distinct from a missing session user.
+func classifyManagedUserError(operation string, err error) error {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		return ErrAccountNotFound
+	case errors.Is(err, ErrLastAdmin):
+		return ErrLastAdmin
+	default:
+		return fmt.Errorf("failed to %s: %w", operation, err)
+	}
+}
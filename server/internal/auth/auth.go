@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -13,16 +14,57 @@ type UserStore interface {
 	GetByID(ctx context.Context, id int64) (*StoredUser, error)
 	GetByUsername(ctx context.Context, username string) (*StoredUser, error)
 	HasUsers(ctx context.Context) (bool, error)
+	List(ctx context.Context, limit, offset int) ([]*StoredUser, error)
+	CreateUser(ctx context.Context, user *StoredUser) error
+	UpdatePasswordHash(ctx context.Context, id int64, passwordHash string) error
+	// UpdateRole changes a role and returns ErrLastAdmin instead of demoting the only admin.
+	UpdateRole(ctx context.Context, id int64, role string) error
+	// Delete removes a user, its API tokens, and its site memberships, and moves the sites it
+	// created to transferSitesTo.
+	Delete(ctx context.Context, id, transferSitesTo int64) error
+	// SetTOTPSecret stores an unconfirmed TOTP secret, replacing any earlier unconfirmed one.
+	SetTOTPSecret(ctx context.Context, id int64, secret string) error
+	// EnableTOTP confirms the stored secret, records step as used, and replaces the recovery codes.
+	EnableTOTP(ctx context.Context, id, step int64, recoveryCodeHashes []string) error
+	// DisableTOTP removes the TOTP secret and recovery codes.
+	DisableTOTP(ctx context.Context, id int64) error
+	// UseTOTPStep records step as used and returns ErrInvalidTwoFactorCode unless it is newer
+	// than the last used step, so each code works once.
+	UseTOTPStep(ctx context.Context, id, step int64) error
+	// UseRecoveryCode consumes an unused recovery code and returns ErrInvalidTwoFactorCode otherwise.
+	UseRecoveryCode(ctx context.Context, id int64, codeHash string) error
+	// ProvisionExternalUser loads the user linked to provider and subject into user. Without a
+	// link it links the account named user.Username when linkExisting is set, returns
+	// ErrIdentityConflict when that account exists otherwise, or creates the account with
+	// user.Role (RoleUser when empty, RoleAdmin for the first account) and no usable password.
+	ProvisionExternalUser(ctx context.Context, user *StoredUser, provider, subject string, linkExisting bool) error
 }
 
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
 type RegisterInput struct {
 	Username string
 	Password string
+	// UserAgent labels the new session in the session list.
+	UserAgent string
 }
 
 type LoginInput struct {
 	Username string
 	Password string
+	// UserAgent labels the new session in the session list.
+	UserAgent string
+}
+
+// LoginResult carries the tokens of a completed login. When the user has enabled two-factor
+// authentication, Tokens is nil and TwoFactorToken must be passed to VerifyTwoFactorLogin.
+type LoginResult struct {
+	User           *User
+	Tokens         *Tokens
+	TwoFactorToken string
 }
 
 type Tokens struct {
@@ -30,10 +72,11 @@ type Tokens struct {
 	RefreshToken string
 }
 type User struct {
-	ID        int64
-	Username  string
-	Role      string
-	CreatedAt time.Time
+	ID               int64
+	Username         string
+	Role             string
+	TwoFactorEnabled bool
+	CreatedAt        time.Time
 }
 
 // StoredUser contains the authentication data that persistence must retain.
@@ -43,7 +86,10 @@ type StoredUser struct {
 	Username     string
 	PasswordHash string
 	Role         string
-	CreatedAt    time.Time
+	// TOTPSecret is the base32 secret; it is only checked once TOTPEnabled is set.
+	TOTPSecret  string
+	TOTPEnabled bool
+	CreatedAt   time.Time
 }
 
 type RegistrationStatus struct {
@@ -55,4 +101,38 @@ type Claims struct {
 	UserID   int64
 	Username string
 	Role     string
+	// SessionID is the refresh session behind a browser access token.
+	SessionID int64
+	// APITokenID is set when the request authenticated with a read-only API token.
+	APITokenID int64
+	// SiteIDs limits an API token to these sites; nil allows every site the user can access.
+	SiteIDs []int64
+	// ShareLinkID is set when an anonymous visitor opened a shared dashboard. UserID is zero
+	// and SiteIDs holds the shared site.
+	ShareLinkID int64
+	// Breakdowns limits a shared dashboard to these breakdowns; nil exposes every breakdown.
+	Breakdowns []string
+}
+
+// IsAPIToken reports whether the claims came from an API token rather than a browser session.
+func (c *Claims) IsAPIToken() bool {
+	return c.APITokenID != 0
+}
+
+// IsShareLink reports whether the claims came from a shared dashboard link.
+func (c *Claims) IsShareLink() bool {
+	return c.ShareLinkID != 0
+}
+
+// CanViewBreakdown applies the breakdown restriction of a shared dashboard.
+func (c *Claims) CanViewBreakdown(breakdown string) bool {
+	return !c.IsShareLink() || c.Breakdowns == nil || slices.Contains(c.Breakdowns, breakdown)
+}
+
+// CanAccessSite applies the API token site scope; ownership is still checked separately.
+func (c *Claims) CanAccessSite(siteID int64) bool {
+	if c.SiteIDs == nil {
+		return true
+	}
+	return slices.Contains(c.SiteIDs, siteID)
 }
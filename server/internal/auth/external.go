@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var (
+	ErrInvalidExternalIdentity = errors.New("external identity is missing a subject or username")
+	// ErrIdentityConflict means a local account already uses the username and linking is off.
+	ErrIdentityConflict = errors.New("username belongs to an account that is not linked to this identity")
+)
+
+// maxExternalIdentityBytes matches the identity provider and subject columns.
+const maxExternalIdentityBytes = 255
+
+// ExternalIdentity is a user authenticated by a trusted party such as an OIDC provider.
+type ExternalIdentity struct {
+	// Provider names the authenticating party, for example an OIDC issuer URL.
+	Provider string
+	// Subject is the provider's stable user identifier.
+	Subject  string
+	Username string
+	// Role is applied on every login when set. When empty, new users get RoleUser unless they
+	// are the first account, and existing users keep their stored role.
+	Role string
+	// LinkExistingUser links the identity to a local account with the same username instead of
+	// rejecting it with ErrIdentityConflict.
+	LinkExistingUser bool
+}
+
+// LoginExternal signs in an identity that was verified outside Lovely Eye, creating the
+// account on first use. Registration policy and two-factor authentication do not apply.
+func (s *Service) LoginExternal(ctx context.Context, identity ExternalIdentity, userAgent string) (*User, *Tokens, error) {
+	if identity.Provider == "" || len(identity.Provider) > maxExternalIdentityBytes ||
+		identity.Subject == "" || len(identity.Subject) > maxExternalIdentityBytes {
+		return nil, nil, ErrInvalidExternalIdentity
+	}
+	username, err := normalizeUsername(identity.Username)
+	if err != nil {
+		return nil, nil, ErrInvalidExternalIdentity
+	}
+	if identity.Role != "" {
+		if _, err := validateRole(identity.Role); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	storedUser := &StoredUser{Username: username, Role: identity.Role}
+	if err := s.userStore.ProvisionExternalUser(ctx, storedUser, identity.Provider, identity.Subject, identity.LinkExistingUser); err != nil {
+		if errors.Is(err, ErrIdentityConflict) {
+			return nil, nil, ErrIdentityConflict
+		}
+		return nil, nil, fmt.Errorf("failed to provision external user: %w", err)
+	}
+
+	if identity.Role != "" && storedUser.Role != identity.Role {
+		if err := s.userStore.UpdateRole(ctx, storedUser.ID, identity.Role); err != nil {
+			if !errors.Is(err, ErrLastAdmin) {
+				return nil, nil, fmt.Errorf("failed to sync external user role: %w", err)
+			}
+			slog.WarnContext(ctx, "kept admin role of the last admin despite the identity provider role",
+				"user_id", storedUser.ID, "provider", identity.Provider)
+		} else {
+			previousRole := storedUser.Role
+			storedUser.Role = identity.Role
+			s.recordRoleChange(ctx, 0, storedUser, previousRole)
+		}
+	}
+
+	user := publicUser(storedUser)
+	tokens, err := s.startSession(ctx, user, userAgent, identity.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
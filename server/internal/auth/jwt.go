@@ -17,15 +17,23 @@ var (
 type tokenType string
 
 const (
-	accessTokenType  tokenType = "access"
-	refreshTokenType tokenType = "refresh"
+	accessTokenType    tokenType = "access"
+	refreshTokenType   tokenType = "refresh"
+	twoFactorTokenType tokenType = "two_factor"
 )
 
+// twoFactorTokenExpiry bounds how long a password-verified login waits for its second factor.
+const twoFactorTokenExpiry = 5 * time.Minute
+
 type jwtClaims struct {
 	UserID    int64     `json:"uid"`
 	Username  string    `json:"usr"`
 	Role      string    `json:"rol"`
 	TokenType tokenType `json:"typ"`
+	// SessionID names the persisted refresh session that issued the token.
+	SessionID int64 `json:"sid,omitempty"`
+	// Generation counts refresh rotations; only the session's current generation may refresh.
+	Generation int64 `json:"gen,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -45,13 +53,14 @@ func newJWTProvider(secret string, accessExpiry, refreshExpiry time.Duration) *j
 	}
 }
 
-func (p *jwtProvider) generateAccessToken(user *User) (string, error) {
+func (p *jwtProvider) generateAccessToken(user *User, sessionID int64) (string, error) {
 	now := time.Now()
 	claims := &jwtClaims{
 		UserID:    user.ID,
 		Username:  user.Username,
 		Role:      user.Role,
 		TokenType: accessTokenType,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(p.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -69,15 +78,43 @@ func (p *jwtProvider) generateAccessToken(user *User) (string, error) {
 	return signedToken, nil
 }
 
-func (p *jwtProvider) generateRefreshToken(user *User) (string, error) {
+func (p *jwtProvider) generateRefreshToken(user *User, sessionID, generation int64) (string, error) {
+	now := time.Now()
+	claims := &jwtClaims{
+		UserID:     user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		TokenType:  refreshTokenType,
+		SessionID:  sessionID,
+		Generation: generation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(p.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    p.issuer,
+			Subject:   user.Username,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString(p.secret)
+	if err != nil {
+		return "", fmt.Errorf("sign refresh token: %w", err)
+	}
+	return signedToken, nil
+}
+
+// generateTwoFactorToken proves that the password step of a login succeeded. It authenticates
+// nothing by itself and is only accepted by the second login step.
+func (p *jwtProvider) generateTwoFactorToken(user *User) (string, error) {
 	now := time.Now()
 	claims := &jwtClaims{
 		UserID:    user.ID,
 		Username:  user.Username,
 		Role:      user.Role,
-		TokenType: refreshTokenType,
+		TokenType: twoFactorTokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(p.refreshExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(twoFactorTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    p.issuer,
@@ -88,7 +125,7 @@ func (p *jwtProvider) generateRefreshToken(user *User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString(p.secret)
 	if err != nil {
-		return "", fmt.Errorf("sign refresh token: %w", err)
+		return "", fmt.Errorf("sign two-factor token: %w", err)
 	}
 	return signedToken, nil
 }
@@ -129,14 +166,20 @@ func (p *jwtProvider) validateAccessToken(tokenString string) (*Claims, error) {
 	if jwtClaims.TokenType != accessTokenType {
 		return nil, ErrWrongTokenType
 	}
+	if jwtClaims.SessionID == 0 {
+		// Without a session the token could not be signed out, so it is not honored.
+		return nil, ErrInvalidToken
+	}
 	return &Claims{
-		UserID:   jwtClaims.UserID,
-		Username: jwtClaims.Username,
-		Role:     jwtClaims.Role,
+		UserID:    jwtClaims.UserID,
+		Username:  jwtClaims.Username,
+		Role:      jwtClaims.Role,
+		SessionID: jwtClaims.SessionID,
 	}, nil
 }
 
-func (p *jwtProvider) validateRefreshToken(tokenString string) (*Claims, error) {
+// validateRefreshToken checks the signature and type; the caller checks the session and generation.
+func (p *jwtProvider) validateRefreshToken(tokenString string) (*jwtClaims, error) {
 	jwtClaims, err := p.validateToken(tokenString)
 	if err != nil {
 		return nil, err
@@ -144,9 +187,20 @@ func (p *jwtProvider) validateRefreshToken(tokenString string) (*Claims, error)
 	if jwtClaims.TokenType != refreshTokenType {
 		return nil, ErrWrongTokenType
 	}
-	return &Claims{
-		UserID:   jwtClaims.UserID,
-		Username: jwtClaims.Username,
-		Role:     jwtClaims.Role,
-	}, nil
+	if jwtClaims.SessionID == 0 {
+		// Refresh tokens issued before sessions were persisted cannot be revoked, so they are not honored.
+		return nil, ErrInvalidToken
+	}
+	return jwtClaims, nil
+}
+
+func (p *jwtProvider) validateTwoFactorToken(tokenString string) (*jwtClaims, error) {
+	jwtClaims, err := p.validateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if jwtClaims.TokenType != twoFactorTokenType {
+		return nil, ErrWrongTokenType
+	}
+	return jwtClaims, nil
 }
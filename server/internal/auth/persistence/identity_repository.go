@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/uptrace/bun"
+)
+
+func (r *Repository) ProvisionExternalUser(
+	ctx context.Context,
+	user *auth.StoredUser,
+	provider, subject string,
+	linkExisting bool,
+) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockUsersForBootstrap(ctx, tx); err != nil {
+			return err
+		}
+
+		linked := new(User)
+		err := tx.NewSelect().
+			Model(linked).
+			Where("id = (SELECT user_id FROM user_identities WHERE provider = ? AND subject = ?)", provider, subject).
+			Scan(ctx)
+		if err == nil {
+			copyStoredUser(user, linked)
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("scan linked user: %w", err)
+		}
+
+		existing := new(User)
+		err = tx.NewSelect().Model(existing).Where("username = ?", user.Username).Scan(ctx)
+		switch {
+		case err == nil && !linkExisting:
+			return auth.ErrIdentityConflict
+		case err == nil:
+			if err := insertIdentity(ctx, tx, existing.ID, provider, subject); err != nil {
+				return err
+			}
+			copyStoredUser(user, existing)
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("scan user by username: %w", err)
+		}
+
+		hasUsers, err := anyUsers(ctx, tx)
+		if err != nil {
+			return err
+		}
+		stored := &User{Username: user.Username, Role: user.Role}
+		if stored.Role == "" {
+			stored.Role = auth.RoleUser
+		}
+		if !hasUsers {
+			stored.Role = auth.RoleAdmin
+		}
+		if _, err := tx.NewInsert().Model(stored).Exec(ctx); err != nil {
+			return fmt.Errorf("insert external user: %w", err)
+		}
+		if err := insertIdentity(ctx, tx, stored.ID, provider, subject); err != nil {
+			return err
+		}
+		copyStoredUser(user, stored)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("provision external user: %w", err)
+	}
+	return nil
+}
+
+func insertIdentity(ctx context.Context, tx bun.Tx, userID int64, provider, subject string) error {
+	identity := &Identity{UserID: userID, Provider: provider, Subject: subject}
+	if _, err := tx.NewInsert().Model(identity).Exec(ctx); err != nil {
+		return fmt.Errorf("insert user identity: %w", err)
+	}
+	return nil
+}
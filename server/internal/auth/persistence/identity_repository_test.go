@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryProvisionsExternalUsersByIdentity(t *testing.T) {
+	t.Parallel()
+
+	repo := New(setupTestDB(t))
+	ctx := context.Background()
+	const issuer = "https://id.example.com"
+
+	first := &auth.StoredUser{Username: "alice"}
+	require.NoError(t, repo.ProvisionExternalUser(ctx, first, issuer, "sub-1", false))
+	require.Equal(t, auth.RoleAdmin, first.Role, "the first account is the admin")
+	require.Empty(t, first.PasswordHash)
+
+	again := &auth.StoredUser{Username: "renamed"}
+	require.NoError(t, repo.ProvisionExternalUser(ctx, again, issuer, "sub-1", false))
+	require.Equal(t, first.ID, again.ID)
+	require.Equal(t, "alice", again.Username)
+
+	local := &auth.StoredUser{Username: "bob", PasswordHash: "hash", Role: auth.RoleUser}
+	require.NoError(t, repo.CreateUser(ctx, local))
+	require.ErrorIs(t, repo.ProvisionExternalUser(ctx, &auth.StoredUser{Username: "bob"}, issuer, "sub-2", false), auth.ErrIdentityConflict)
+
+	linked := &auth.StoredUser{Username: "bob"}
+	require.NoError(t, repo.ProvisionExternalUser(ctx, linked, issuer, "sub-2", true))
+	require.Equal(t, local.ID, linked.ID)
+	require.Equal(t, "hash", linked.PasswordHash)
+
+	other := &auth.StoredUser{Username: "carol"}
+	require.NoError(t, repo.ProvisionExternalUser(ctx, other, "https://other.example.com", "sub-1", false))
+	require.NotEqual(t, first.ID, other.ID, "subjects are scoped to their provider")
+	require.Equal(t, auth.RoleUser, other.Role)
+
+	require.NoError(t, repo.Delete(ctx, local.ID, first.ID))
+	relinked := &auth.StoredUser{Username: "bob"}
+	require.NoError(t, repo.ProvisionExternalUser(ctx, relinked, issuer, "sub-2", false))
+	require.NotEqual(t, local.ID, relinked.ID, "deleting a user removes its identities")
+}
@@ -10,11 +10,52 @@ import (
 type User struct {
 	bun.BaseModel `bun:"table:users,alias:u"`
 
-	ID           int64     `bun:"id,pk,autoincrement"`
-	Username     string    `bun:"username,unique,notnull"`
-	PasswordHash string    `bun:"password_hash,notnull"`
-	Role         string    `bun:"role,notnull,default:'user'"`
-	Email        string    `bun:"email"`
-	CreatedAt    time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	UpdatedAt    time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	ID           int64  `bun:"id,pk,autoincrement"`
+	Username     string `bun:"username,unique,notnull"`
+	PasswordHash string `bun:"password_hash,notnull"`
+	Role         string `bun:"role,notnull,default:'user'"`
+	Email        string `bun:"email"`
+	// TOTPSecret is set from enrollment start; the factor applies once TOTPEnabledAt is set.
+	TOTPSecret    string    `bun:"totp_secret,notnull,type:varchar(64),default:''"`
+	TOTPEnabledAt time.Time `bun:"totp_enabled_at,nullzero"`
+	TOTPLastStep  int64     `bun:"totp_last_step,notnull,default:0"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// Identity links a user to an account at an external identity provider.
+type Identity struct {
+	bun.BaseModel `bun:"table:user_identities,alias:ui"`
+
+	ID        int64     `bun:"id,pk,autoincrement"`
+	UserID    int64     `bun:"user_id,notnull"`
+	Provider  string    `bun:"provider,notnull,type:varchar(255),unique:user_identities_provider_subject"`
+	Subject   string    `bun:"subject,notnull,type:varchar(255),unique:user_identities_provider_subject"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// RecoveryCode is a hashed single-use two-factor recovery code.
+type RecoveryCode struct {
+	bun.BaseModel `bun:"table:user_recovery_codes,alias:urc"`
+
+	ID        int64     `bun:"id,pk,autoincrement"`
+	UserID    int64     `bun:"user_id,notnull"`
+	CodeHash  string    `bun:"code_hash,notnull,type:varchar(64)"`
+	UsedAt    time.Time `bun:"used_at,nullzero"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// Session is a refresh session row. Generation advances on every refresh token rotation.
+type Session struct {
+	bun.BaseModel `bun:"table:auth_sessions,alias:aus"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	UserID     int64     `bun:"user_id,notnull"`
+	Generation int64     `bun:"generation,notnull,default:1"`
+	UserAgent  string    `bun:"user_agent,notnull,type:varchar(256),default:''"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	LastUsedAt time.Time `bun:"last_used_at,nullzero,notnull,default:current_timestamp"`
+	RotatedAt  time.Time `bun:"rotated_at,nullzero,notnull,default:current_timestamp"`
+	ExpiresAt  time.Time `bun:"expires_at,notnull"`
+	RevokedAt  time.Time `bun:"revoked_at,nullzero"`
 }
@@ -0,0 +1,21 @@
+package persistence
+
+import "github.com/uptrace/bun"
+
+// These table-only rows let user deletion release site ownership, memberships, and
+// API tokens without importing sibling feature persistence packages.
+type ownedSite struct {
+	bun.BaseModel `bun:"table:sites,alias:s"`
+}
+
+type ownedSiteMember struct {
+	bun.BaseModel `bun:"table:site_members,alias:sm"`
+}
+
+type ownedAPIToken struct {
+	bun.BaseModel `bun:"table:api_tokens,alias:at"`
+}
+
+type ownedAPITokenSite struct {
+	bun.BaseModel `bun:"table:api_token_sites,alias:ats"`
+}
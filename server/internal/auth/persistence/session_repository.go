@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/uptrace/bun"
+)
+
+var _ auth.SessionStore = (*Repository)(nil)
+
+func (r *Repository) CreateSession(ctx context.Context, session *auth.Session) error {
+	if _, err := r.db.NewDelete().
+		Model((*Session)(nil)).
+		Where("user_id = ?", session.UserID).
+		WhereGroup(" AND ", func(q *bun.DeleteQuery) *bun.DeleteQuery {
+			return q.Where("expires_at <= ?", session.CreatedAt).WhereOr("revoked_at IS NOT NULL")
+		}).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("prune user sessions: %w", err)
+	}
+
+	row := &Session{
+		UserID:     session.UserID,
+		Generation: session.Generation,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		RotatedAt:  session.RotatedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+	if _, err := r.db.NewInsert().Model(row).Exec(ctx); err != nil {
+		return fmt.Errorf("insert session: %w", err)
+	}
+	session.ID = row.ID
+	return nil
+}
+
+func (r *Repository) GetSession(ctx context.Context, id int64) (*auth.Session, error) {
+	row := new(Session)
+	if err := r.db.NewSelect().Model(row).Where("id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, auth.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return domainSession(row), nil
+}
+
+func (r *Repository) RotateSession(ctx context.Context, id, generation int64, usedAt, expiresAt time.Time) error {
+	result, err := r.db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("generation = generation + 1").
+		Set("last_used_at = ?", usedAt).
+		Set("rotated_at = ?", usedAt).
+		Set("expires_at = ?", expiresAt).
+		Where("id = ?", id).
+		Where("generation = ?", generation).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("rotate session: %w", err)
+	}
+	return requireAffectedSession(result, "rotate session")
+}
+
+func (r *Repository) ListActiveSessions(ctx context.Context, userID int64, now time.Time) ([]*auth.Session, error) {
+	var rows []*Session
+	if err := r.db.NewSelect().
+		Model(&rows).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", now).
+		Order("last_used_at DESC", "id DESC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	sessions := make([]*auth.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, domainSession(row))
+	}
+	return sessions, nil
+}
+
+func (r *Repository) RevokeSession(ctx context.Context, userID, id int64, revokedAt time.Time) error {
+	result, err := r.db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("revoked_at = ?", revokedAt).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	return requireAffectedSession(result, "revoke session")
+}
+
+func (r *Repository) RevokeUserSessions(ctx context.Context, userID, exceptID int64, revokedAt time.Time) error {
+	if _, err := r.db.NewUpdate().
+		Model((*Session)(nil)).
+		Set("revoked_at = ?", revokedAt).
+		Where("user_id = ?", userID).
+		Where("id <> ?", exceptID).
+		Where("revoked_at IS NULL").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("revoke user sessions: %w", err)
+	}
+	return nil
+}
+
+func requireAffectedSession(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, auth.ErrSessionNotFound)
+	}
+	return nil
+}
+
+func domainSession(row *Session) *auth.Session {
+	session := &auth.Session{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Generation: row.Generation,
+		UserAgent:  row.UserAgent,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		RotatedAt:  row.RotatedAt,
+		ExpiresAt:  row.ExpiresAt,
+	}
+	if !row.RevokedAt.IsZero() {
+		revokedAt := row.RevokedAt
+		session.RevokedAt = &revokedAt
+	}
+	return session
+}
@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepositoryRotatesOnlyCurrentGeneration(t *testing.T) {
+	t.Parallel()
+
+	repo := New(setupTestDB(t))
+	ctx := context.Background()
+	user := &auth.StoredUser{Username: "admin", PasswordHash: "hash", Role: auth.RoleAdmin}
+	require.NoError(t, repo.CreateUser(ctx, user))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	session := &auth.Session{
+		UserID:     user.ID,
+		Generation: 1,
+		UserAgent:  "Firefox",
+		CreatedAt:  now,
+		LastUsedAt: now,
+		RotatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateSession(ctx, session))
+	require.NotZero(t, session.ID)
+
+	require.NoError(t, repo.RotateSession(ctx, session.ID, 1, now, now.Add(2*time.Hour)))
+	require.ErrorIs(t, repo.RotateSession(ctx, session.ID, 1, now, now.Add(2*time.Hour)), auth.ErrSessionNotFound)
+
+	stored, err := repo.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stored.Generation)
+	require.Nil(t, stored.RevokedAt)
+
+	require.ErrorIs(t, repo.RevokeSession(ctx, user.ID+1, session.ID, now), auth.ErrSessionNotFound)
+	require.NoError(t, repo.RevokeSession(ctx, user.ID, session.ID, now))
+	require.ErrorIs(t, repo.RotateSession(ctx, session.ID, 2, now, now.Add(time.Hour)), auth.ErrSessionNotFound)
+
+	active, err := repo.ListActiveSessions(ctx, user.ID, now)
+	require.NoError(t, err)
+	require.Empty(t, active)
+
+	next := &auth.Session{UserID: user.ID, Generation: 1, CreatedAt: now, LastUsedAt: now, RotatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.CreateSession(ctx, next))
+	_, err = repo.GetSession(ctx, session.ID)
+	require.ErrorIs(t, err, auth.ErrSessionNotFound, "revoked sessions are pruned when a new one starts")
+
+	require.NoError(t, repo.RevokeUserSessions(ctx, user.ID, 0, now))
+	active, err = repo.ListActiveSessions(ctx, user.ID, now)
+	require.NoError(t, err)
+	require.Empty(t, active)
+}
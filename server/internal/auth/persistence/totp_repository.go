@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/uptrace/bun"
+)
+
+func (r *Repository) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	result, err := r.db.NewUpdate().
+		Model((*User)(nil)).
+		Set("totp_secret = ?", secret).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("totp_enabled_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("set TOTP secret: %w", err)
+	}
+	return requireAffectedUser(result, "set TOTP secret")
+}
+
+func (r *Repository) EnableTOTP(ctx context.Context, id, step int64, recoveryCodeHashes []string) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		result, err := tx.NewUpdate().
+			Model((*User)(nil)).
+			Set("totp_enabled_at = ?", now).
+			Set("totp_last_step = ?", step).
+			Set("updated_at = ?", now).
+			Where("id = ?", id).
+			Where("totp_secret <> ''").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("enable TOTP: %w", err)
+		}
+		if err := requireAffectedUser(result, "enable TOTP"); err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(ctx, tx, id, recoveryCodeHashes)
+	})
+	if err != nil {
+		return fmt.Errorf("enable TOTP transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DisableTOTP(ctx context.Context, id int64) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model((*User)(nil)).
+			Set("totp_secret = ''").
+			Set("totp_enabled_at = NULL").
+			Set("totp_last_step = 0").
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("disable TOTP: %w", err)
+		}
+		if err := requireAffectedUser(result, "disable TOTP"); err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(ctx, tx, id, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("disable TOTP transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UseTOTPStep(ctx context.Context, id, step int64) error {
+	result, err := r.db.NewUpdate().
+		Model((*User)(nil)).
+		Set("totp_last_step = ?", step).
+		Where("id = ?", id).
+		Where("totp_enabled_at IS NOT NULL").
+		Where("totp_last_step < ?", step).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("use TOTP step: %w", err)
+	}
+	return requireAffectedCode(result, "use TOTP step")
+}
+
+func (r *Repository) UseRecoveryCode(ctx context.Context, id int64, codeHash string) error {
+	result, err := r.db.NewUpdate().
+		Model((*RecoveryCode)(nil)).
+		Set("used_at = ?", time.Now()).
+		Where("user_id = ?", id).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+	return requireAffectedCode(result, "use recovery code")
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx bun.Tx, id int64, hashes []string) error {
+	if _, err := tx.NewDelete().
+		Model((*RecoveryCode)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	codes := make([]*RecoveryCode, 0, len(hashes))
+	for _, hash := range hashes {
+		codes = append(codes, &RecoveryCode{UserID: id, CodeHash: hash})
+	}
+	if _, err := tx.NewInsert().Model(&codes).Exec(ctx); err != nil {
+		return fmt.Errorf("insert recovery codes: %w", err)
+	}
+	return nil
+}
+
+func requireAffectedCode(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, auth.ErrInvalidTwoFactorCode)
+	}
+	return nil
+}
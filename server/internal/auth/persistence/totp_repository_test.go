@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryConsumesTwoFactorCodesOnce(t *testing.T) {
+	t.Parallel()
+
+	repo := New(setupTestDB(t))
+	ctx := context.Background()
+	user := &auth.StoredUser{Username: "admin", PasswordHash: "hash", Role: auth.RoleAdmin}
+	require.NoError(t, repo.CreateUser(ctx, user))
+
+	require.ErrorIs(t, repo.EnableTOTP(ctx, user.ID, 10, nil), auth.ErrUserNotFound, "enabling requires an enrolled secret")
+	require.NoError(t, repo.SetTOTPSecret(ctx, user.ID, "SECRET"))
+	require.NoError(t, repo.EnableTOTP(ctx, user.ID, 10, []string{"first", "second"}))
+	require.ErrorIs(t, repo.SetTOTPSecret(ctx, user.ID, "OTHER"), auth.ErrUserNotFound, "an enabled secret is never replaced")
+
+	stored, err := repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.True(t, stored.TOTPEnabled)
+	require.Equal(t, "SECRET", stored.TOTPSecret)
+
+	require.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 10), auth.ErrInvalidTwoFactorCode)
+	require.NoError(t, repo.UseTOTPStep(ctx, user.ID, 11))
+	require.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 11), auth.ErrInvalidTwoFactorCode)
+
+	require.NoError(t, repo.UseRecoveryCode(ctx, user.ID, "first"))
+	require.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "first"), auth.ErrInvalidTwoFactorCode)
+
+	require.NoError(t, repo.DisableTOTP(ctx, user.ID))
+	require.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "second"), auth.ErrInvalidTwoFactorCode)
+	stored, err = repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.False(t, stored.TOTPEnabled)
+	require.Empty(t, stored.TOTPSecret)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/uptrace/bun"
@@ -57,10 +58,10 @@ func (r *Repository) CreateForRegistration(
 		stored := &User{
 			Username:     user.Username,
 			PasswordHash: user.PasswordHash,
-			Role:         "user",
+			Role:         auth.RoleUser,
 		}
 		if !hasUsers {
-			stored.Role = "admin"
+			stored.Role = auth.RoleAdmin
 		}
 
 		if _, err := tx.NewInsert().Model(stored).Exec(ctx); err != nil {
@@ -96,7 +97,7 @@ func (r *Repository) CreateInitialAdminIfNoUsers(
 		stored := &User{
 			Username:     user.Username,
 			PasswordHash: user.PasswordHash,
-			Role:         "admin",
+			Role:         auth.RoleAdmin,
 		}
 		if _, err := tx.NewInsert().Model(stored).Exec(ctx); err != nil {
 			return fmt.Errorf("insert initial admin user: %w", err)
@@ -143,12 +144,217 @@ func (r *Repository) HasUsers(ctx context.Context) (bool, error) {
 	return exists, nil
 }
 
+func (r *Repository) List(ctx context.Context, limit, offset int) ([]*auth.StoredUser, error) {
+	var users []*User
+	q := r.db.NewSelect().
+		Model(&users).
+		Order("u.username ASC", "u.id ASC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("scan users: %w", err)
+	}
+	result := make([]*auth.StoredUser, 0, len(users))
+	for _, user := range users {
+		result = append(result, storedUser(user))
+	}
+	return result, nil
+}
+
+func (r *Repository) CreateUser(ctx context.Context, user *auth.StoredUser) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := usernameExists(ctx, tx, user.Username)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return auth.ErrUserExists
+		}
+
+		stored := &User{
+			Username:     user.Username,
+			PasswordHash: user.PasswordHash,
+			Role:         user.Role,
+		}
+		if _, err := tx.NewInsert().Model(stored).Exec(ctx); err != nil {
+			return fmt.Errorf("insert user: %w", err)
+		}
+		copyStoredUser(user, stored)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdatePasswordHash(ctx context.Context, id int64, passwordHash string) error {
+	result, err := r.db.NewUpdate().
+		Model((*User)(nil)).
+		Set("password_hash = ?", passwordHash).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update user password: %w", err)
+	}
+	return requireAffectedUser(result, "update user password")
+}
+
+func (r *Repository) UpdateRole(ctx context.Context, id int64, role string) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockUsersForBootstrap(ctx, tx); err != nil {
+			return err
+		}
+		if role != auth.RoleAdmin {
+			if err := requireAnotherAdmin(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.NewUpdate().
+			Model((*User)(nil)).
+			Set("role = ?", role).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update user role: %w", err)
+		}
+		return requireAffectedUser(result, "update user role")
+	})
+	if err != nil {
+		return fmt.Errorf("update user role transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id, transferSitesTo int64) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := r.lockUsersForBootstrap(ctx, tx); err != nil {
+			return err
+		}
+		if err := requireAnotherAdmin(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := releaseUserSites(ctx, tx, id, transferSitesTo); err != nil {
+			return err
+		}
+		if err := deleteUserCredentials(ctx, tx, id); err != nil {
+			return err
+		}
+
+		result, err := tx.NewDelete().Model((*User)(nil)).Where("id = ?", id).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+		return requireAffectedUser(result, "delete user")
+	})
+	if err != nil {
+		return fmt.Errorf("delete user transaction: %w", err)
+	}
+	return nil
+}
+
+// releaseUserSites moves created sites to the new owner, whose now-redundant memberships on
+// those sites are dropped, and removes the deleted user's own memberships.
+func releaseUserSites(ctx context.Context, tx bun.Tx, id, transferSitesTo int64) error {
+	if _, err := tx.NewDelete().
+		Model((*ownedSiteMember)(nil)).
+		Where("user_id = ?", transferSitesTo).
+		Where("site_id IN (SELECT id FROM sites WHERE user_id = ?)", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete transferred site memberships: %w", err)
+	}
+	if _, err := tx.NewUpdate().
+		Model((*ownedSite)(nil)).
+		Set("user_id = ?", transferSitesTo).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("transfer user sites: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedSiteMember)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user site memberships: %w", err)
+	}
+	return nil
+}
+
+func deleteUserCredentials(ctx context.Context, tx bun.Tx, id int64) error {
+	if _, err := tx.NewDelete().
+		Model((*ownedAPITokenSite)(nil)).
+		Where("api_token_id IN (SELECT id FROM api_tokens WHERE user_id = ?)", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user API token sites: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedAPIToken)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user API tokens: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*Session)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user sessions: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*RecoveryCode)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user recovery codes: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*Identity)(nil)).
+		Where("user_id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete user identities: %w", err)
+	}
+	return nil
+}
+
+// requireAnotherAdmin rejects changes that would leave the instance without an admin.
+func requireAnotherAdmin(ctx context.Context, tx bun.Tx, id int64) error {
+	exists, err := tx.NewSelect().
+		Model((*User)(nil)).
+		Where("role = ?", auth.RoleAdmin).
+		Where("id <> ?", id).
+		Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("check remaining admins: %w", err)
+	}
+	if !exists {
+		return auth.ErrLastAdmin
+	}
+	return nil
+}
+
+func requireAffectedUser(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, auth.ErrUserNotFound)
+	}
+	return nil
+}
+
 func storedUser(user *User) *auth.StoredUser {
 	return &auth.StoredUser{
 		ID:           user.ID,
 		Username:     user.Username,
 		PasswordHash: user.PasswordHash,
 		Role:         user.Role,
+		TOTPSecret:   user.TOTPSecret,
+		TOTPEnabled:  !user.TOTPEnabledAt.IsZero(),
 		CreatedAt:    user.CreatedAt,
 	}
 }
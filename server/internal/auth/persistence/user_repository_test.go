@@ -5,7 +5,9 @@ import (
 	"errors"
 	"testing"
 
+	apitokenpersistence "github.com/lovely-eye/server/internal/apitoken/persistence"
 	"github.com/lovely-eye/server/internal/auth"
+	sitepersistence "github.com/lovely-eye/server/internal/site/persistence"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,3 +48,57 @@ func TestRepositoryTranslatesMissingUsersToAuthError(t *testing.T) {
 	_, err = repo.GetByUsername(ctx, "missing")
 	require.True(t, errors.Is(err, auth.ErrUserNotFound))
 }
+
+func TestRepositoryDeleteTransfersSitesAndRemovesCredentials(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := New(db)
+	ctx := context.Background()
+
+	admin := &auth.StoredUser{Username: "admin", PasswordHash: "hash", Role: auth.RoleAdmin}
+	require.NoError(t, repo.CreateUser(ctx, admin))
+	departing := &auth.StoredUser{Username: "departing", PasswordHash: "hash", Role: auth.RoleUser}
+	require.NoError(t, repo.CreateUser(ctx, departing))
+	require.ErrorIs(t, repo.CreateUser(ctx, &auth.StoredUser{Username: "admin", PasswordHash: "hash", Role: auth.RoleUser}), auth.ErrUserExists)
+
+	owned := &sitepersistence.Site{UserID: departing.ID, Name: "Owned", PublicKey: "owned"}
+	_, err := db.NewInsert().Model(owned).Exec(ctx)
+	require.NoError(t, err)
+	shared := &sitepersistence.Site{UserID: admin.ID, Name: "Shared", PublicKey: "shared"}
+	_, err = db.NewInsert().Model(shared).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&[]*sitepersistence.Member{
+		{SiteID: owned.ID, UserID: admin.ID, Role: "viewer"},
+		{SiteID: shared.ID, UserID: departing.ID, Role: "editor"},
+	}).Exec(ctx)
+	require.NoError(t, err)
+	token := &apitokenpersistence.APIToken{UserID: departing.ID, Name: "CI", Prefix: "le_test", TokenHash: "hash", SiteScoped: true}
+	_, err = db.NewInsert().Model(token).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&apitokenpersistence.APITokenSite{APITokenID: token.ID, SiteID: owned.ID}).Exec(ctx)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, repo.UpdateRole(ctx, admin.ID, auth.RoleUser), auth.ErrLastAdmin)
+	require.NoError(t, repo.Delete(ctx, departing.ID, admin.ID))
+
+	_, err = repo.GetByID(ctx, departing.ID)
+	require.ErrorIs(t, err, auth.ErrUserNotFound)
+	var ownerID int64
+	require.NoError(t, db.NewSelect().Model((*sitepersistence.Site)(nil)).Column("user_id").Where("id = ?", owned.ID).Scan(ctx, &ownerID))
+	require.Equal(t, admin.ID, ownerID)
+	for _, model := range []any{
+		(*sitepersistence.Member)(nil),
+		(*apitokenpersistence.APIToken)(nil),
+		(*apitokenpersistence.APITokenSite)(nil),
+	} {
+		count, err := db.NewSelect().Model(model).Count(ctx)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	}
+
+	users, err := repo.List(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.ErrorIs(t, repo.Delete(ctx, departing.ID, admin.ID), auth.ErrUserNotFound)
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 	"unicode"
+
+	"github.com/lovely-eye/server/internal/audit"
 )
 
 var (
@@ -34,19 +36,34 @@ type Config struct {
 // Service implements authentication and token lifecycle policy.
 type Service struct {
 	userStore         UserStore
+	sessionStore      SessionStore
 	jwt               *jwtProvider
 	allowRegistration bool
+	// auditLog is nil unless SetAuditRecorder was called.
+	auditLog audit.Recorder
 }
 
 // NewService creates a new authentication service.
-func NewService(userStore UserStore, cfg Config) *Service {
+func NewService(userStore UserStore, sessionStore SessionStore, cfg Config) *Service {
 	return &Service{
 		userStore:         userStore,
+		sessionStore:      sessionStore,
 		jwt:               newJWTProvider(cfg.JWTSecret, cfg.AccessTokenExpiry, cfg.RefreshExpiry),
 		allowRegistration: cfg.AllowRegistration,
 	}
 }
 
+// SetAuditRecorder records logins and role changes in the audit log.
+func (s *Service) SetAuditRecorder(recorder audit.Recorder) {
+	s.auditLog = recorder
+}
+
+func (s *Service) recordAudit(ctx context.Context, entry audit.Entry) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, entry)
+	}
+}
+
 func (s *Service) Register(ctx context.Context, input RegisterInput) (*User, *Tokens, error) {
 	username, err := normalizeUsername(input.Username)
 	if err != nil {
@@ -78,7 +95,7 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*User, *To
 
 	user := publicUser(storedUser)
 
-	tokens, err := s.generateTokens(user)
+	tokens, err := s.startSession(ctx, user, input.UserAgent, loginMethodRegister)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -86,43 +103,89 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*User, *To
 	return user, tokens, nil
 }
 
-func (s *Service) Login(ctx context.Context, input LoginInput) (*User, *Tokens, error) {
+// Login verifies the password. Users with two-factor authentication get a TwoFactorToken
+// instead of tokens and finish with VerifyTwoFactorLogin.
+func (s *Service) Login(ctx context.Context, input LoginInput) (*LoginResult, error) {
 	username, err := normalizeUsername(input.Username)
 	if err != nil {
-		return nil, nil, ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 	if err := validatePassword(input.Password); err != nil {
-		return nil, nil, ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	storedUser, err := s.userStore.GetByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
-			return nil, nil, ErrInvalidCredentials
+			return nil, ErrInvalidCredentials
 		}
-		return nil, nil, fmt.Errorf("failed to get user by username: %w", err)
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
 	}
 
 	if !checkPassword(input.Password, storedUser.PasswordHash) {
-		return nil, nil, ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	user := publicUser(storedUser)
 
-	tokens, err := s.generateTokens(user)
+	if storedUser.TOTPEnabled {
+		twoFactorToken, err := s.jwt.generateTwoFactorToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, TwoFactorToken: twoFactorToken}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, input.UserAgent, loginMethodPassword)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return user, tokens, nil
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// ChangePassword replaces the user's password after verifying the current one and signs out
+// every session except sessionID, the one making the change.
+func (s *Service) ChangePassword(ctx context.Context, userID, sessionID int64, currentPassword, newPassword string) error {
+	storedUser, err := s.getStoredUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !checkPassword(currentPassword, storedUser.PasswordHash) {
+		return ErrInvalidCredentials
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.userStore.UpdatePasswordHash(ctx, userID, hashedPassword); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := s.sessionStore.RevokeUserSessions(ctx, userID, sessionID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+	return nil
 }
 
+// RefreshTokens rotates the refresh session and rejects reused or revoked refresh tokens.
 func (s *Service) RefreshTokens(ctx context.Context, refreshToken string) (*Tokens, error) {
 	claims, err := s.jwt.validateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
+	generation, err := s.rotateSession(ctx, claims.SessionID, claims.UserID, claims.Generation)
+	if err != nil {
+		return nil, err
+	}
+
 	storedUser, err := s.userStore.GetByID(ctx, claims.UserID)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
@@ -131,11 +194,20 @@ func (s *Service) RefreshTokens(ctx context.Context, refreshToken string) (*Toke
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
 
-	return s.generateTokens(publicUser(storedUser))
+	return s.generateTokens(publicUser(storedUser), claims.SessionID, generation)
 }
 
-func (s *Service) ValidateAccessToken(token string) (*Claims, error) {
-	return s.jwt.validateAccessToken(token)
+// ValidateAccessToken checks the token and that its session is still active, so revoking a
+// session, for example through ChangePassword, signs it out without waiting for the token to expire.
+func (s *Service) ValidateAccessToken(ctx context.Context, token string) (*Claims, error) {
+	claims, err := s.jwt.validateAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.activeSession(ctx, claims.SessionID, claims.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
 func (s *Service) GetUserByID(ctx context.Context, id int64) (*User, error) {
@@ -198,13 +270,13 @@ func (s *Service) isFirstUser(ctx context.Context) (bool, error) {
 	return !hasUsers, nil
 }
 
-func (s *Service) generateTokens(user *User) (*Tokens, error) {
-	accessToken, err := s.jwt.generateAccessToken(user)
+func (s *Service) generateTokens(user *User, sessionID, generation int64) (*Tokens, error) {
+	accessToken, err := s.jwt.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.jwt.generateRefreshToken(user)
+	refreshToken, err := s.jwt.generateRefreshToken(user, sessionID, generation)
 	if err != nil {
 		return nil, err
 	}
@@ -217,10 +289,11 @@ func (s *Service) generateTokens(user *User) (*Tokens, error) {
 
 func publicUser(user *StoredUser) *User {
 	return &User{
-		ID:        user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
+		ID:               user.ID,
+		Username:         user.Username,
+		Role:             user.Role,
+		TwoFactorEnabled: user.TOTPEnabled,
+		CreatedAt:        user.CreatedAt,
 	}
 }
 
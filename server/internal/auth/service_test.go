@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lovely-eye/server/internal/audit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -107,9 +108,9 @@ func TestCreateInitialAdminValidatesCredentials(t *testing.T) {
 
 func TestAuthServiceDoesNotClassifyStorageFailureAsUserError(t *testing.T) {
 	storageErr := errors.New("storage unavailable")
-	service := NewService(&fakeUserStore{err: storageErr}, testAuthConfig(true))
+	service := NewService(&fakeUserStore{err: storageErr}, newFakeSessionStore(), testAuthConfig(true))
 
-	_, _, loginErr := service.Login(context.Background(), LoginInput{
+	_, loginErr := service.Login(context.Background(), LoginInput{
 		Username: "missing",
 		Password: "password123",
 	})
@@ -121,9 +122,257 @@ func TestAuthServiceDoesNotClassifyStorageFailureAsUserError(t *testing.T) {
 	require.False(t, errors.Is(userErr, ErrUserNotFound))
 }
 
+func TestAdminUserManagementRequiresStoredAdminRole(t *testing.T) {
+	service := newTestAuthService(t, true)
+	ctx := context.Background()
+
+	admin, _, err := service.Register(ctx, RegisterInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	member, _, err := service.Register(ctx, RegisterInput{Username: "member", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = service.ListUsers(ctx, member.ID, 10, 0)
+	require.ErrorIs(t, err, ErrAdminRequired)
+	_, err = service.CreateUser(ctx, member.ID, CreateUserInput{Username: "other", Password: "password123"})
+	require.ErrorIs(t, err, ErrAdminRequired)
+
+	created, err := service.CreateUser(ctx, admin.ID, CreateUserInput{Username: " analyst ", Password: "password123"})
+	require.NoError(t, err)
+	require.Equal(t, "analyst", created.Username)
+	require.Equal(t, RoleUser, created.Role)
+	_, err = service.CreateUser(ctx, admin.ID, CreateUserInput{Username: "bad", Password: "password123", Role: "root"})
+	require.ErrorIs(t, err, ErrInvalidRole)
+
+	users, err := service.ListUsers(ctx, admin.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+
+	require.NoError(t, service.ResetPassword(ctx, admin.ID, created.ID, "new-password"))
+	_, err = service.Login(ctx, LoginInput{Username: "analyst", Password: "new-password"})
+	require.NoError(t, err)
+	require.ErrorIs(t, service.ResetPassword(ctx, admin.ID, 999, "new-password"), ErrAccountNotFound)
+
+	_, err = service.SetRole(ctx, admin.ID, admin.ID, RoleUser)
+	require.ErrorIs(t, err, ErrLastAdmin)
+	promoted, err := service.SetRole(ctx, admin.ID, member.ID, RoleAdmin)
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, promoted.Role)
+	_, err = service.SetRole(ctx, member.ID, admin.ID, RoleUser)
+	require.NoError(t, err)
+	_, err = service.ListUsers(ctx, admin.ID, 10, 0)
+	require.ErrorIs(t, err, ErrAdminRequired, "demotion must apply without waiting for token expiry")
+
+	require.ErrorIs(t, service.DeleteUser(ctx, member.ID, member.ID, 0), ErrCannotDeleteSelf)
+	require.ErrorIs(t, service.DeleteUser(ctx, member.ID, created.ID, created.ID), ErrInvalidSiteTransfer)
+	require.ErrorIs(t, service.DeleteUser(ctx, member.ID, created.ID, 999), ErrInvalidSiteTransfer)
+	require.NoError(t, service.DeleteUser(ctx, member.ID, created.ID, 0))
+	_, err = service.GetUserByID(ctx, created.ID)
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+type recordingAuditLog struct {
+	entries []audit.Entry
+}
+
+func (r *recordingAuditLog) Record(_ context.Context, entry audit.Entry) {
+	r.entries = append(r.entries, entry)
+}
+
+func TestAuthServiceAuditsLoginsAndRoleChanges(t *testing.T) {
+	service := newTestAuthService(t, true)
+	auditLog := &recordingAuditLog{}
+	service.SetAuditRecorder(auditLog)
+	ctx := context.Background()
+
+	admin, _, err := service.Register(ctx, RegisterInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	member, _, err := service.Register(ctx, RegisterInput{Username: "member", Password: "password123"})
+	require.NoError(t, err)
+	_, err = service.Login(ctx, LoginInput{Username: "member", Password: "wrong-password"})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = service.Login(ctx, LoginInput{Username: "member", Password: "password123"})
+	require.NoError(t, err)
+	_, err = service.SetRole(ctx, admin.ID, member.ID, RoleAdmin)
+	require.NoError(t, err)
+	_, err = service.SetRole(ctx, admin.ID, member.ID, RoleAdmin)
+	require.NoError(t, err)
+
+	require.Len(t, auditLog.entries, 4, "failed logins and unchanged roles are not audited")
+	login := auditLog.entries[2]
+	require.Equal(t, audit.ActionUserLogin, login.Action)
+	require.Equal(t, member.ID, login.ActorID)
+	require.Equal(t, "member", login.ActorName)
+	require.Equal(t, []audit.Change{{Field: "method", After: []byte(`"password"`)}}, login.Changes)
+
+	roleChange := auditLog.entries[3]
+	require.Equal(t, audit.ActionUserRoleChange, roleChange.Action)
+	require.Equal(t, admin.ID, roleChange.ActorID)
+	require.Equal(t, member.ID, roleChange.TargetUserID)
+	require.Equal(t, []audit.Change{{Field: "role", Before: []byte(`"user"`), After: []byte(`"admin"`)}}, roleChange.Changes)
+}
+
+func TestRefreshTokensRotateSessionAndDetectReuse(t *testing.T) {
+	sessions := newFakeSessionStore()
+	service := NewService(newFakeUserStore(), sessions, testAuthConfig(true))
+	ctx := context.Background()
+
+	user, first, err := service.Register(ctx, RegisterInput{Username: "admin", Password: "password123", UserAgent: "Firefox"})
+	require.NoError(t, err)
+	listed, err := service.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, "Firefox", listed[0].UserAgent)
+
+	second, err := service.RefreshTokens(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	_, err = service.RefreshTokens(ctx, first.RefreshToken)
+	require.NoError(t, err, "a racing refresh within the grace period must not revoke the session")
+
+	sessions.sessions[listed[0].ID].RotatedAt = time.Now().Add(-time.Minute)
+	_, err = service.RefreshTokens(ctx, first.RefreshToken)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	_, err = service.RefreshTokens(ctx, second.RefreshToken)
+	require.ErrorIs(t, err, ErrInvalidToken, "reuse must revoke the whole session")
+
+	other, err := service.Login(ctx, LoginInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeAllSessions(ctx, user.ID))
+	_, err = service.RefreshTokens(ctx, other.Tokens.RefreshToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	listed, err = service.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+}
+
+func TestChangePasswordRequiresCurrentPasswordAndKeepsOnlyCurrentSession(t *testing.T) {
+	service := newTestAuthService(t, true)
+	ctx := context.Background()
+
+	user, current, err := service.Register(ctx, RegisterInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, LoginInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	claims, err := service.ValidateAccessToken(ctx, current.AccessToken)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, service.ChangePassword(ctx, user.ID, claims.SessionID, "wrong-password", "new-password"), ErrInvalidCredentials)
+	require.ErrorIs(t, service.ChangePassword(ctx, user.ID, claims.SessionID, "password123", "short"), ErrInvalidPassword)
+	require.NoError(t, service.ChangePassword(ctx, user.ID, claims.SessionID, "password123", "new-password"))
+
+	_, err = service.Login(ctx, LoginInput{Username: "admin", Password: "password123"})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = service.RefreshTokens(ctx, other.Tokens.RefreshToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = service.RefreshTokens(ctx, current.RefreshToken)
+	require.NoError(t, err)
+}
+
+func TestTwoFactorLoginRequiresSingleUseCode(t *testing.T) {
+	service := newTestAuthService(t, true)
+	ctx := context.Background()
+
+	admin, _, err := service.Register(ctx, RegisterInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	member, _, err := service.Register(ctx, RegisterInput{Username: "member", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = service.EnableTwoFactor(ctx, member.ID, "000000")
+	require.ErrorIs(t, err, ErrTwoFactorNotEnrolling)
+	enrollment, err := service.BeginTwoFactorEnrollment(ctx, member.ID)
+	require.NoError(t, err)
+	require.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Lovely%20Eye:member?")
+	require.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	key, err := totpEncoding.DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+	step := time.Now().Unix() / int64(totpPeriod/time.Second)
+	_, err = service.EnableTwoFactor(ctx, member.ID, "not-a-code")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	recoveryCodes, err := service.EnableTwoFactor(ctx, member.ID, totpCode(key, step))
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, recoveryCodeCount)
+	_, err = service.BeginTwoFactorEnrollment(ctx, member.ID)
+	require.ErrorIs(t, err, ErrTwoFactorEnabled)
+
+	login := func() string {
+		t.Helper()
+		result, err := service.Login(ctx, LoginInput{Username: "member", Password: "password123"})
+		require.NoError(t, err)
+		require.Nil(t, result.Tokens, "tokens must wait for the second factor")
+		require.True(t, result.User.TwoFactorEnabled)
+		return result.TwoFactorToken
+	}
+
+	challenge := login()
+	_, _, err = service.VerifyTwoFactorLogin(ctx, TwoFactorLoginInput{TwoFactorToken: challenge, Code: totpCode(key, step)})
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode, "the enrollment code must not be replayed")
+	_, tokens, err := service.VerifyTwoFactorLogin(ctx, TwoFactorLoginInput{TwoFactorToken: challenge, Code: totpCode(key, step+1)})
+	require.NoError(t, err)
+	_, _, err = service.VerifyTwoFactorLogin(ctx, TwoFactorLoginInput{TwoFactorToken: tokens.AccessToken, Code: totpCode(key, step+1)})
+	require.Error(t, err, "only two-factor tokens start the second step")
+
+	challenge = login()
+	recoveryCode := strings.ToUpper(recoveryCodes[0])
+	_, _, err = service.VerifyTwoFactorLogin(ctx, TwoFactorLoginInput{TwoFactorToken: challenge, Code: recoveryCode})
+	require.NoError(t, err)
+	_, _, err = service.VerifyTwoFactorLogin(ctx, TwoFactorLoginInput{TwoFactorToken: challenge, Code: recoveryCode})
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode, "recovery codes are single-use")
+
+	require.ErrorIs(t, service.ResetTwoFactor(ctx, member.ID, member.ID), ErrAdminRequired)
+	require.NoError(t, service.ResetTwoFactor(ctx, admin.ID, member.ID))
+	result, err := service.Login(ctx, LoginInput{Username: "member", Password: "password123"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Tokens)
+}
+
+func TestTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	// RFC 6238 appendix B SHA-1 vectors, truncated to six digits.
+	require.Equal(t, "287082", totpCode(key, 59/30))
+	require.Equal(t, "081804", totpCode(key, 1111111109/30))
+	require.Equal(t, "005924", totpCode(key, 1234567890/30))
+}
+
+func TestLoginExternalProvisionsOnceAndSyncsRole(t *testing.T) {
+	service := newTestAuthService(t, false)
+	ctx := context.Background()
+	identity := ExternalIdentity{Provider: "https://id.example.com", Subject: "abc", Username: " alice "}
+
+	first, tokens, err := service.LoginExternal(ctx, identity, "agent")
+	require.NoError(t, err)
+	require.NotNil(t, tokens)
+	require.Equal(t, "alice", first.Username)
+	require.Equal(t, RoleAdmin, first.Role)
+
+	identity.Username = "renamed"
+	again, _, err := service.LoginExternal(ctx, identity, "agent")
+	require.NoError(t, err)
+	require.Equal(t, first.ID, again.ID)
+
+	bob := ExternalIdentity{Provider: identity.Provider, Subject: "def", Username: "bob", Role: RoleAdmin}
+	bobUser, _, err := service.LoginExternal(ctx, bob, "agent")
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, bobUser.Role)
+	bob.Role = RoleUser
+	bobUser, _, err = service.LoginExternal(ctx, bob, "agent")
+	require.NoError(t, err)
+	require.Equal(t, RoleUser, bobUser.Role)
+
+	_, _, err = service.LoginExternal(ctx, ExternalIdentity{Provider: identity.Provider, Subject: "ghi", Username: "bob"}, "agent")
+	require.ErrorIs(t, err, ErrIdentityConflict)
+	linked, _, err := service.LoginExternal(ctx, ExternalIdentity{
+		Provider: identity.Provider, Subject: "ghi", Username: "bob", LinkExistingUser: true,
+	}, "agent")
+	require.NoError(t, err)
+	require.Equal(t, bobUser.ID, linked.ID)
+
+	_, _, err = service.LoginExternal(ctx, ExternalIdentity{Provider: identity.Provider, Username: "carol"}, "agent")
+	require.ErrorIs(t, err, ErrInvalidExternalIdentity)
+}
+
 func newTestAuthService(t *testing.T, allowRegistration bool) *Service {
 	t.Helper()
-	return NewService(newFakeUserStore(), testAuthConfig(allowRegistration))
+	return NewService(newFakeUserStore(), newFakeSessionStore(), testAuthConfig(allowRegistration))
 }
 
 func testAuthConfig(allowRegistration bool) Config {
@@ -136,13 +385,22 @@ func testAuthConfig(allowRegistration bool) Config {
 }
 
 type fakeUserStore struct {
-	users  map[int64]*StoredUser
-	nextID int64
-	err    error
+	users         map[int64]*StoredUser
+	totpSteps     map[int64]int64
+	recoveryCodes map[int64]map[string]bool
+	identities    map[string]int64
+	nextID        int64
+	err           error
 }
 
 func newFakeUserStore() *fakeUserStore {
-	return &fakeUserStore{users: make(map[int64]*StoredUser), nextID: 1}
+	return &fakeUserStore{
+		users:         make(map[int64]*StoredUser),
+		totpSteps:     make(map[int64]int64),
+		recoveryCodes: make(map[int64]map[string]bool),
+		identities:    make(map[string]int64),
+		nextID:        1,
+	}
 }
 
 func (s *fakeUserStore) CreateForRegistration(
@@ -220,7 +478,230 @@ func (s *fakeUserStore) HasUsers(context.Context) (bool, error) {
 	return len(s.users) != 0, nil
 }
 
+func (s *fakeUserStore) List(_ context.Context, _, _ int) ([]*StoredUser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	users := make([]*StoredUser, 0, len(s.users))
+	for id := int64(1); id < s.nextID; id++ {
+		if user, ok := s.users[id]; ok {
+			users = append(users, cloneStoredUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (s *fakeUserStore) CreateUser(_ context.Context, user *StoredUser) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, existing := range s.users {
+		if existing.Username == user.Username {
+			return ErrUserExists
+		}
+	}
+	user.ID = s.nextID
+	user.CreatedAt = time.Now()
+	s.nextID++
+	s.users[user.ID] = cloneStoredUser(user)
+	return nil
+}
+
+func (s *fakeUserStore) UpdatePasswordHash(_ context.Context, id int64, passwordHash string) error {
+	user, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	user.PasswordHash = passwordHash
+	return nil
+}
+
+func (s *fakeUserStore) UpdateRole(_ context.Context, id int64, role string) error {
+	user, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if role != RoleAdmin && !s.hasAdminOtherThan(id) {
+		return ErrLastAdmin
+	}
+	user.Role = role
+	return nil
+}
+
+func (s *fakeUserStore) Delete(_ context.Context, id, _ int64) error {
+	if _, ok := s.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	if !s.hasAdminOtherThan(id) {
+		return ErrLastAdmin
+	}
+	delete(s.users, id)
+	return nil
+}
+
+func (s *fakeUserStore) hasAdminOtherThan(id int64) bool {
+	for _, user := range s.users {
+		if user.ID != id && user.Role == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
 func cloneStoredUser(user *StoredUser) *StoredUser {
 	clone := *user
 	return &clone
 }
+
+type fakeSessionStore struct {
+	sessions map[int64]*Session
+	nextID   int64
+}
+
+func newFakeSessionStore() *fakeSessionStore {
+	return &fakeSessionStore{sessions: make(map[int64]*Session), nextID: 1}
+}
+
+func (s *fakeSessionStore) CreateSession(_ context.Context, session *Session) error {
+	session.ID = s.nextID
+	s.nextID++
+	clone := *session
+	s.sessions[session.ID] = &clone
+	return nil
+}
+
+func (s *fakeSessionStore) GetSession(_ context.Context, id int64) (*Session, error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	clone := *session
+	return &clone, nil
+}
+
+func (s *fakeSessionStore) RotateSession(_ context.Context, id, generation int64, usedAt, expiresAt time.Time) error {
+	session, ok := s.sessions[id]
+	if !ok || session.Generation != generation || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	session.Generation++
+	session.LastUsedAt = usedAt
+	session.RotatedAt = usedAt
+	session.ExpiresAt = expiresAt
+	return nil
+}
+
+func (s *fakeSessionStore) ListActiveSessions(_ context.Context, userID int64, now time.Time) ([]*Session, error) {
+	var sessions []*Session
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.active(now) {
+			clone := *session
+			sessions = append(sessions, &clone)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *fakeSessionStore) RevokeSession(_ context.Context, userID, id int64, revokedAt time.Time) error {
+	session, ok := s.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	session.RevokedAt = &revokedAt
+	return nil
+}
+
+func (s *fakeSessionStore) RevokeUserSessions(_ context.Context, userID, exceptID int64, revokedAt time.Time) error {
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.ID != exceptID && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (s *fakeUserStore) SetTOTPSecret(_ context.Context, id int64, secret string) error {
+	user, ok := s.users[id]
+	if !ok || user.TOTPEnabled {
+		return ErrUserNotFound
+	}
+	user.TOTPSecret = secret
+	return nil
+}
+
+func (s *fakeUserStore) EnableTOTP(_ context.Context, id, step int64, recoveryCodeHashes []string) error {
+	user, ok := s.users[id]
+	if !ok || user.TOTPSecret == "" {
+		return ErrUserNotFound
+	}
+	user.TOTPEnabled = true
+	s.totpSteps[id] = step
+	s.recoveryCodes[id] = make(map[string]bool, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		s.recoveryCodes[id][hash] = true
+	}
+	return nil
+}
+
+func (s *fakeUserStore) DisableTOTP(_ context.Context, id int64) error {
+	user, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	user.TOTPSecret = ""
+	user.TOTPEnabled = false
+	delete(s.totpSteps, id)
+	delete(s.recoveryCodes, id)
+	return nil
+}
+
+func (s *fakeUserStore) UseTOTPStep(_ context.Context, id, step int64) error {
+	if step <= s.totpSteps[id] {
+		return ErrInvalidTwoFactorCode
+	}
+	s.totpSteps[id] = step
+	return nil
+}
+
+func (s *fakeUserStore) UseRecoveryCode(_ context.Context, id int64, codeHash string) error {
+	if !s.recoveryCodes[id][codeHash] {
+		return ErrInvalidTwoFactorCode
+	}
+	delete(s.recoveryCodes[id], codeHash)
+	return nil
+}
+
+func (s *fakeUserStore) ProvisionExternalUser(
+	_ context.Context,
+	user *StoredUser,
+	provider, subject string,
+	linkExisting bool,
+) error {
+	key := provider + "\x00" + subject
+	if id, ok := s.identities[key]; ok {
+		*user = *cloneStoredUser(s.users[id])
+		return nil
+	}
+	for _, existing := range s.users {
+		if existing.Username == user.Username {
+			if !linkExisting {
+				return ErrIdentityConflict
+			}
+			s.identities[key] = existing.ID
+			*user = *cloneStoredUser(existing)
+			return nil
+		}
+	}
+	user.ID = s.nextID
+	if user.Role == "" {
+		user.Role = RoleUser
+	}
+	if len(s.users) == 0 {
+		user.Role = RoleAdmin
+	}
+	user.CreatedAt = time.Now()
+	s.nextID++
+	s.users[user.ID] = cloneStoredUser(user)
+	s.identities[key] = user.ID
+	return nil
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lovely-eye/server/internal/audit"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+const (
+	maxSessionUserAgentLength = 256
+
+	// refreshGracePeriod lets parallel requests that raced on one expired access token
+	// share the first rotation instead of tripping reuse detection.
+	refreshGracePeriod = 30 * time.Second
+)
+
+// SessionStore persists refresh sessions so that they can be rotated and revoked.
+type SessionStore interface {
+	// CreateSession stores a session and prunes the user's expired or revoked sessions.
+	CreateSession(ctx context.Context, session *Session) error
+	GetSession(ctx context.Context, id int64) (*Session, error)
+	// RotateSession advances the generation only if it still equals generation and returns
+	// ErrSessionNotFound when another refresh won the race.
+	RotateSession(ctx context.Context, id, generation int64, usedAt, expiresAt time.Time) error
+	ListActiveSessions(ctx context.Context, userID int64, now time.Time) ([]*Session, error)
+	RevokeSession(ctx context.Context, userID, id int64, revokedAt time.Time) error
+	// RevokeUserSessions revokes every session of the user except exceptID, which may be zero.
+	RevokeUserSessions(ctx context.Context, userID, exceptID int64, revokedAt time.Time) error
+}
+
+// Session is a persisted refresh token family. Each refresh rotates Generation, and any older
+// refresh token presented later revokes the whole session.
+type Session struct {
+	ID         int64
+	UserID     int64
+	Generation int64
+	UserAgent  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RotatedAt  time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+func (s *Session) active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ListSessions returns the user's unexpired, unrevoked sessions.
+func (s *Service) ListSessions(ctx context.Context, userID int64) ([]*Session, error) {
+	sessions, err := s.sessionStore.ListActiveSessions(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's sessions out.
+func (s *Service) RevokeSession(ctx context.Context, userID, id int64) error {
+	if err := s.sessionStore.RevokeSession(ctx, userID, id, time.Now()); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllSessions signs the user out everywhere.
+func (s *Service) RevokeAllSessions(ctx context.Context, userID int64) error {
+	if err := s.sessionStore.RevokeUserSessions(ctx, userID, 0, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// Login methods recorded in the audit log. External logins record their provider instead.
+const (
+	loginMethodRegister  = "register"
+	loginMethodPassword  = "password"
+	loginMethodTwoFactor = "two_factor"
+)
+
+// startSession signs a user in and records the login with method in the audit log.
+func (s *Service) startSession(ctx context.Context, user *User, userAgent, method string) (*Tokens, error) {
+	now := time.Now()
+	session := &Session{
+		UserID:     user.ID,
+		Generation: 1,
+		UserAgent:  truncateUserAgent(userAgent),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		RotatedAt:  now,
+		ExpiresAt:  now.Add(s.jwt.refreshExpiry),
+	}
+	if err := s.sessionStore.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	entry := audit.Entry{
+		ActorID:      user.ID,
+		ActorName:    user.Username,
+		Action:       audit.ActionUserLogin,
+		TargetUserID: user.ID,
+	}
+	entry.Diff("method", nil, method)
+	s.recordAudit(ctx, entry)
+	return s.generateTokens(user, session.ID, session.Generation)
+}
+
+// rotateSession exchanges a refresh token of generation for tokens of the next generation.
+func (s *Service) rotateSession(ctx context.Context, sessionID, userID, generation int64) (int64, error) {
+	now := time.Now()
+	session, err := s.activeSession(ctx, sessionID, userID, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if generation == session.Generation {
+		err := s.sessionStore.RotateSession(ctx, session.ID, generation, now, now.Add(s.jwt.refreshExpiry))
+		if err == nil {
+			return generation + 1, nil
+		}
+		if !errors.Is(err, ErrSessionNotFound) {
+			return 0, fmt.Errorf("failed to rotate session: %w", err)
+		}
+		// A parallel refresh rotated first; re-read so the grace period below applies.
+		if session, err = s.activeSession(ctx, sessionID, userID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	if generation == session.Generation-1 && now.Sub(session.RotatedAt) <= refreshGracePeriod {
+		return session.Generation, nil
+	}
+
+	if err := s.sessionStore.RevokeSession(ctx, userID, session.ID, now); err != nil {
+		slog.WarnContext(ctx, "failed to revoke session after refresh token reuse", "session_id", session.ID, "error", err)
+	}
+	return 0, ErrRefreshTokenReused
+}
+
+func (s *Service) activeSession(ctx context.Context, sessionID, userID int64, now time.Time) (*Session, error) {
+	session, err := s.sessionStore.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != userID || !session.active(now) {
+		return nil, ErrInvalidToken
+	}
+	return session, nil
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxSessionUserAgentLength {
+		return userAgent
+	}
+	// Drop a trailing rune that the byte cut may have split.
+	return strings.ToValidUTF8(userAgent[:maxSessionUserAgentLength], "")
+}
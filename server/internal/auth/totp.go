@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // RFC 6238 authenticator apps default to HMAC-SHA1
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTwoFactorCode  = errors.New("invalid two-factor code")
+	ErrTwoFactorEnabled      = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled   = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolling = errors.New("two-factor enrollment has not been started")
+)
+
+const (
+	totpIssuer      = "Lovely Eye"
+	totpSecretBytes = 20
+	totpDigits      = 6
+	totpModulus     = 1_000_000
+	totpPeriod      = 30 * time.Second
+	// totpSkewSteps accepts codes from neighbouring periods to tolerate clock drift.
+	totpSkewSteps = 1
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorEnrollment is shown once so the user can add the secret to an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string
+	// ProvisioningURI is an otpauth:// URI for rendering as a QR code.
+	ProvisioningURI string
+}
+
+type TwoFactorLoginInput struct {
+	TwoFactorToken string
+	// Code is a current TOTP code or an unused recovery code.
+	Code string
+	// UserAgent labels the new session in the session list.
+	UserAgent string
+}
+
+// BeginTwoFactorEnrollment creates a new unconfirmed TOTP secret. It takes effect after
+// EnableTwoFactor verifies a code generated from it.
+func (s *Service) BeginTwoFactorEnrollment(ctx context.Context, userID int64) (*TwoFactorEnrollment, error) {
+	storedUser, err := s.getStoredUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if storedUser.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secretBytes := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("generate TOTP secret: %w", err)
+	}
+	secret := totpEncoding.EncodeToString(secretBytes)
+	if err := s.userStore.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(storedUser.Username, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms enrollment with a code from the authenticator app and returns
+// single-use recovery codes, which are only stored hashed.
+func (s *Service) EnableTwoFactor(ctx context.Context, userID int64, code string) ([]string, error) {
+	storedUser, err := s.getStoredUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if storedUser.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if storedUser.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolling
+	}
+
+	step, ok := matchTOTP(storedUser.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userStore.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor turns the second factor off after re-checking the password.
+func (s *Service) DisableTwoFactor(ctx context.Context, userID int64, password string) error {
+	storedUser, err := s.getStoredUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !checkPassword(password, storedUser.PasswordHash) {
+		return ErrInvalidCredentials
+	}
+	if !storedUser.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := s.userStore.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// VerifyTwoFactorLogin completes a login that Login answered with a TwoFactorToken.
+func (s *Service) VerifyTwoFactorLogin(ctx context.Context, input TwoFactorLoginInput) (*User, *Tokens, error) {
+	claims, err := s.jwt.validateTwoFactorToken(input.TwoFactorToken)
+	if err != nil {
+		return nil, nil, ErrInvalidTwoFactorCode
+	}
+	storedUser, err := s.getStoredUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, nil, ErrInvalidTwoFactorCode
+		}
+		return nil, nil, err
+	}
+	if !storedUser.TOTPEnabled {
+		// The factor was reset after the password step; start over with a fresh login.
+		return nil, nil, ErrInvalidTwoFactorCode
+	}
+
+	if err := s.consumeTwoFactorCode(ctx, storedUser, input.Code); err != nil {
+		return nil, nil, err
+	}
+
+	user := publicUser(storedUser)
+	tokens, err := s.startSession(ctx, user, input.UserAgent, loginMethodTwoFactor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+func (s *Service) consumeTwoFactorCode(ctx context.Context, storedUser *StoredUser, code string) error {
+	if step, ok := matchTOTP(storedUser.TOTPSecret, code, time.Now()); ok {
+		if err := s.userStore.UseTOTPStep(ctx, storedUser.ID, step); err != nil {
+			if errors.Is(err, ErrInvalidTwoFactorCode) {
+				return ErrInvalidTwoFactorCode
+			}
+			return fmt.Errorf("failed to record TOTP code: %w", err)
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return ErrInvalidTwoFactorCode
+	}
+	if err := s.userStore.UseRecoveryCode(ctx, storedUser.ID, hashRecoveryCode(normalized)); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return ErrInvalidTwoFactorCode
+		}
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) getStoredUser(ctx context.Context, id int64) (*StoredUser, error) {
+	storedUser, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+	return storedUser, nil
+}
+
+func totpProvisioningURI(username, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+	label := url.PathEscape(totpIssuer + ":" + username)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// matchTOTP returns the time step that code was generated for, allowing for clock skew.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := now.Unix() / int64(totpPeriod/time.Second)
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode implements the RFC 4226 dynamic truncation for one RFC 6238 time step.
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step)) //nolint:gosec // time steps are positive
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulus)
+}
+
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, 0, recoveryCodeCount)
+	hashes = make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		encoded := strings.ToLower(totpEncoding.EncodeToString(raw))
+		codes = append(codes, encoded[:8]+"-"+encoded[8:16])
+		hashes = append(hashes, hashRecoveryCode(encoded[:16]))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with any case and with or without the separator.
+func normalizeRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(normalized) != 16 {
+		return ""
+	}
+	return normalized
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,32 @@
+package event
+
+// Names of the events that tracker.js sends for its automatic outbound link and file download
+// tracking. Every site accepts them without configuration.
+const (
+	OutboundLinkEvent = "outbound_link"
+	FileDownloadEvent = "file_download"
+)
+
+const maxBuiltinURLLength = 2048
+
+// BuiltinFields returns the fields of a built-in event, or false when name is not built in.
+// Each call returns new fields, so callers may store them.
+func BuiltinFields(name string) ([]*Field, bool) {
+	switch name {
+	case OutboundLinkEvent, FileDownloadEvent:
+		return []*Field{{
+			Key:       "url",
+			Type:      FieldTypeString,
+			Required:  true,
+			MaxLength: maxBuiltinURLLength,
+		}}, true
+	default:
+		return nil, false
+	}
+}
+
+// IsBuiltin reports whether name is reserved for a built-in event.
+func IsBuiltin(name string) bool {
+	_, ok := BuiltinFields(name)
+	return ok
+}
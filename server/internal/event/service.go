@@ -2,10 +2,13 @@ package event
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/lovely-eye/server/internal/audit"
 )
 
 const (
@@ -16,6 +19,7 @@ const (
 
 var (
 	ErrInvalidEventName  = errors.New("invalid event name")
+	ErrReservedEventName = errors.New("event name is reserved for built-in tracking")
 	ErrInvalidFieldKey   = errors.New("invalid field key")
 	ErrInvalidFieldType  = errors.New("invalid field type")
 	ErrInvalidFieldLimit = errors.New("invalid field max length")
@@ -30,6 +34,20 @@ const (
 	FieldTypeBool   FieldType = 3
 )
 
+// name matches the type names that DefinitionInput accepts.
+func (t FieldType) name() string {
+	switch t {
+	case FieldTypeInt:
+		return "int"
+	case FieldTypeFloat:
+		return "float"
+	case FieldTypeBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
 type Definition struct {
 	ID        int64
 	SiteID    int64
@@ -59,12 +77,25 @@ type Store interface {
 
 type Service struct {
 	store Store
+	// auditLog is nil unless SetAuditRecorder was called.
+	auditLog audit.Recorder
 }
 
 func NewService(store Store) *Service {
 	return &Service{store: store}
 }
 
+// SetAuditRecorder records definition changes in the audit log.
+func (s *Service) SetAuditRecorder(recorder audit.Recorder) {
+	s.auditLog = recorder
+}
+
+func (s *Service) recordAudit(ctx context.Context, entry audit.Entry) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, entry)
+	}
+}
+
 type FieldInput struct {
 	Key       string
 	Type      string
@@ -85,11 +116,15 @@ func (s *Service) List(ctx context.Context, siteID int64, limit, offset int) ([]
 	return defs, nil
 }
 
-func (s *Service) Upsert(ctx context.Context, siteID int64, input DefinitionInput) (*Definition, error) {
+// Upsert creates or replaces a definition; userID is the actor recorded in the audit log.
+func (s *Service) Upsert(ctx context.Context, siteID, userID int64, input DefinitionInput) (*Definition, error) {
 	name := strings.TrimSpace(input.Name)
 	if name == "" || len(name) > maxEventNameLength {
 		return nil, ErrInvalidEventName
 	}
+	if IsBuiltin(name) {
+		return nil, ErrReservedEventName
+	}
 
 	fields := make([]*Field, 0, len(input.Fields))
 	seen := make(map[string]struct{}, len(input.Fields))
@@ -136,20 +171,71 @@ func (s *Service) Upsert(ctx context.Context, siteID int64, input DefinitionInpu
 		})
 	}
 
+	previous, err := s.store.GetByName(ctx, siteID, name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get event definition: %w", err)
+	}
 	def, err := s.store.Upsert(ctx, siteID, name, fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert event definition: %w", err)
 	}
+
+	entry := audit.Entry{ActorID: userID, Action: audit.ActionEventDefinitionUpsert, SiteID: siteID}
+	entry.Diff("name", definitionName(previous), def.Name)
+	entry.Diff("fields", auditFields(previous), auditFields(def))
+	s.recordAudit(ctx, entry)
 	return def, nil
 }
 
-func (s *Service) Delete(ctx context.Context, siteID int64, name string) error {
+// Delete removes a definition; userID is the actor recorded in the audit log.
+func (s *Service) Delete(ctx context.Context, siteID, userID int64, name string) error {
 	trimmed := strings.TrimSpace(name)
 	if trimmed == "" {
 		return ErrInvalidEventName
 	}
+	previous, err := s.store.GetByName(ctx, siteID, trimmed)
+	if err != nil {
+		return fmt.Errorf("failed to delete event definition: %w", err)
+	}
 	if err := s.store.DeleteByName(ctx, siteID, trimmed); err != nil {
 		return fmt.Errorf("failed to delete event definition: %w", err)
 	}
+
+	entry := audit.Entry{ActorID: userID, Action: audit.ActionEventDefinitionDelete, SiteID: siteID}
+	entry.Diff("name", trimmed, nil)
+	entry.Diff("fields", auditFields(previous), nil)
+	s.recordAudit(ctx, entry)
 	return nil
 }
+
+// auditField is the audited form of a field, without database IDs and timestamps.
+type auditField struct {
+	Key       string `json:"key"`
+	Type      string `json:"type"`
+	Required  bool   `json:"required"`
+	MaxLength int    `json:"maxLength"`
+}
+
+func definitionName(def *Definition) any {
+	if def == nil {
+		return nil
+	}
+	return def.Name
+}
+
+// auditFields returns nil for a missing definition so that creation has no before value.
+func auditFields(def *Definition) any {
+	if def == nil {
+		return nil
+	}
+	fields := make([]auditField, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		fields = append(fields, auditField{
+			Key:       field.Key,
+			Type:      field.Type.name(),
+			Required:  field.Required,
+			MaxLength: field.MaxLength,
+		})
+	}
+	return fields
+}
@@ -0,0 +1,208 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ funnel.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func orderedSteps(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("fs.position ASC")
+}
+
+func (r *Repository) GetBySite(ctx context.Context, siteID int64, limit, offset int) ([]*funnel.Funnel, error) {
+	var rows []*Funnel
+	q := r.db.NewSelect().
+		Model(&rows).
+		Where("f.site_id = ?", siteID).
+		Relation("Steps", orderedSteps).
+		Order("f.name ASC", "f.id ASC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get funnels by site: %w", err)
+	}
+	result := make([]*funnel.Funnel, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, funnelFromModel(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, siteID, id int64) (*funnel.Funnel, error) {
+	row := new(Funnel)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("f.id = ?", id).
+		Where("f.site_id = ?", siteID).
+		Relation("Steps", orderedSteps).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get funnel by id: %w", funnel.ErrFunnelNotFound)
+		}
+		return nil, fmt.Errorf("failed to get funnel by id: %w", err)
+	}
+	return funnelFromModel(row), nil
+}
+
+func (r *Repository) NameExists(ctx context.Context, siteID int64, name string, excludedID int64) (bool, error) {
+	q := r.db.NewSelect().
+		Model((*Funnel)(nil)).
+		Where("site_id = ?", siteID).
+		Where("name = ?", name)
+	if excludedID > 0 {
+		q = q.Where("id != ?", excludedID)
+	}
+	exists, err := q.Exists(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check funnel name: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *Repository) Create(ctx context.Context, value *funnel.Funnel) error {
+	now := time.Now()
+	row := &Funnel{
+		SiteID:    value.SiteID,
+		Name:      value.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(row).Exec(ctx); err != nil {
+			return fmt.Errorf("insert funnel: %w", err)
+		}
+		return insertSteps(ctx, tx, row.ID, value.Steps)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create funnel transaction: %w", err)
+	}
+	value.ID = row.ID
+	value.CreatedAt = row.CreatedAt
+	value.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, value *funnel.Funnel) error {
+	row := &Funnel{
+		ID:        value.ID,
+		SiteID:    value.SiteID,
+		Name:      value.Name,
+		UpdatedAt: time.Now(),
+	}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model(row).
+			Column("name", "updated_at").
+			Where("id = ?", row.ID).
+			Where("site_id = ?", row.SiteID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("update funnel: %w", err)
+		}
+		if err := requireAffectedFunnel(result, "update funnel"); err != nil {
+			return err
+		}
+		if _, err := tx.NewDelete().
+			Model((*Step)(nil)).
+			Where("funnel_id = ?", row.ID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("delete funnel steps: %w", err)
+		}
+		return insertSteps(ctx, tx, row.ID, value.Steps)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update funnel transaction: %w", err)
+	}
+	value.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, siteID, id int64) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*Step)(nil)).
+			Where("funnel_id IN (SELECT id FROM funnels WHERE id = ? AND site_id = ?)", id, siteID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("delete funnel steps: %w", err)
+		}
+		result, err := tx.NewDelete().
+			Model((*Funnel)(nil)).
+			Where("id = ?", id).
+			Where("site_id = ?", siteID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("delete funnel: %w", err)
+		}
+		return requireAffectedFunnel(result, "delete funnel")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete funnel transaction: %w", err)
+	}
+	return nil
+}
+
+func insertSteps(ctx context.Context, tx bun.Tx, funnelID int64, steps []funnel.Step) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	rows := make([]*Step, 0, len(steps))
+	for position, step := range steps {
+		rows = append(rows, &Step{
+			FunnelID: funnelID,
+			Position: position,
+			Type:     StepType(step.Type),
+			Value:    step.Value,
+		})
+	}
+	if _, err := tx.NewInsert().Model(&rows).Exec(ctx); err != nil {
+		return fmt.Errorf("insert funnel steps: %w", err)
+	}
+	return nil
+}
+
+func requireAffectedFunnel(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, funnel.ErrFunnelNotFound)
+	}
+	return nil
+}
+
+func funnelFromModel(row *Funnel) *funnel.Funnel {
+	value := &funnel.Funnel{
+		ID:        row.ID,
+		SiteID:    row.SiteID,
+		Name:      row.Name,
+		Steps:     make([]funnel.Step, 0, len(row.Steps)),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	for _, step := range row.Steps {
+		value.Steps = append(value.Steps, funnel.Step{Type: funnel.StepType(step.Type), Value: step.Value})
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateUpdateDeleteFunnel(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := New(db)
+	site := createTestSite(t, db)
+	ctx := context.Background()
+
+	created := &funnel.Funnel{
+		SiteID: site.ID,
+		Name:   "Signup",
+		Steps: []funnel.Step{
+			{Type: funnel.StepTypePage, Value: "/pricing"},
+			{Type: funnel.StepTypePage, Value: "/signup"},
+			{Type: funnel.StepTypeEvent, Value: "signup_completed"},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, created))
+	require.NotZero(t, created.ID)
+
+	loaded, err := repo.GetByID(ctx, site.ID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created.Steps, loaded.Steps)
+
+	exists, err := repo.NameExists(ctx, site.ID, "Signup", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	loaded.Name = "Checkout"
+	loaded.Steps = []funnel.Step{
+		{Type: funnel.StepTypePage, Value: "/cart"},
+		{Type: funnel.StepTypePage, Value: "/checkout"},
+	}
+	require.NoError(t, repo.Update(ctx, loaded))
+
+	funnels, err := repo.GetBySite(ctx, site.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, funnels, 1)
+	require.Equal(t, "Checkout", funnels[0].Name)
+	require.Equal(t, loaded.Steps, funnels[0].Steps)
+
+	require.NoError(t, repo.Delete(ctx, site.ID, created.ID))
+	_, err = repo.GetByID(ctx, site.ID, created.ID)
+	require.True(t, errors.Is(err, funnel.ErrFunnelNotFound))
+	count, err := db.NewSelect().Model((*Step)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestRepository_FunnelMutationsAreScopedToSite(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := New(db)
+	site := createTestSite(t, db)
+	ctx := context.Background()
+
+	steps := []funnel.Step{
+		{Type: funnel.StepTypePage, Value: "/pricing"},
+		{Type: funnel.StepTypePage, Value: "/signup"},
+	}
+	created := &funnel.Funnel{SiteID: site.ID, Name: "Signup", Steps: steps}
+	require.NoError(t, repo.Create(ctx, created))
+
+	otherSiteID := site.ID + 1
+	_, err := repo.GetByID(ctx, otherSiteID, created.ID)
+	require.True(t, errors.Is(err, funnel.ErrFunnelNotFound))
+
+	err = repo.Update(ctx, &funnel.Funnel{ID: created.ID, SiteID: otherSiteID, Name: "Moved", Steps: steps})
+	require.True(t, errors.Is(err, funnel.ErrFunnelNotFound))
+
+	err = repo.Delete(ctx, otherSiteID, created.ID)
+	require.True(t, errors.Is(err, funnel.ErrFunnelNotFound))
+
+	loaded, err := repo.GetByID(ctx, site.ID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, steps, loaded.Steps)
+}
@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type StepType int8
+
+const (
+	StepTypePage StepType = iota
+	StepTypeEvent
+)
+
+type Funnel struct {
+	bun.BaseModel `bun:"table:funnels,alias:f"`
+
+	ID        int64     `bun:"id,pk,autoincrement"`
+	SiteID    int64     `bun:"site_id,notnull,unique:funnels_site_id_name"`
+	Name      string    `bun:"name,notnull,type:varchar(100),unique:funnels_site_id_name"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	Steps []*Step `bun:"rel:has-many,join:id=funnel_id"`
+}
+
+type Step struct {
+	bun.BaseModel `bun:"table:funnel_steps,alias:fs"`
+
+	ID       int64    `bun:"id,pk,autoincrement"`
+	FunnelID int64    `bun:"funnel_id,notnull,unique:funnel_steps_funnel_id_position"`
+	Position int      `bun:"position,notnull,unique:funnel_steps_funnel_id_position"`
+	Type     StepType `bun:"type,notnull"`
+	Value    string   `bun:"value,notnull,type:varchar(2048)"`
+}
@@ -0,0 +1,189 @@
+package funnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	maxFunnelNameLength = 100
+	minFunnelSteps      = 2
+	maxFunnelSteps      = 8
+	maxStepPathLength   = 2048
+	maxStepEventLength  = 100
+)
+
+var (
+	ErrFunnelNotFound     = errors.New("funnel not found")
+	ErrFunnelExists       = errors.New("funnel with this name already exists")
+	ErrInvalidFunnelName  = errors.New("invalid funnel name")
+	ErrInvalidFunnelSteps = errors.New("funnel must have between 2 and 8 steps")
+	ErrInvalidStepType    = errors.New("invalid funnel step type")
+	ErrInvalidStepValue   = errors.New("invalid funnel step value")
+	ErrRepeatedStep       = errors.New("consecutive funnel steps must differ")
+)
+
+// StepType selects what a funnel step matches: a page view path or a predefined event name.
+type StepType int8
+
+const (
+	StepTypePage  StepType = 0
+	StepTypeEvent StepType = 1
+)
+
+type Funnel struct {
+	ID        int64
+	SiteID    int64
+	Name      string
+	Steps     []Step
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Step struct {
+	Type  StepType
+	Value string
+}
+
+type Store interface {
+	GetBySite(ctx context.Context, siteID int64, limit, offset int) ([]*Funnel, error)
+	GetByID(ctx context.Context, siteID, id int64) (*Funnel, error)
+	NameExists(ctx context.Context, siteID int64, name string, excludedID int64) (bool, error)
+	Create(ctx context.Context, funnel *Funnel) error
+	Update(ctx context.Context, funnel *Funnel) error
+	Delete(ctx context.Context, siteID, id int64) error
+}
+
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+type Input struct {
+	Name  string
+	Steps []Step
+}
+
+func (s *Service) List(ctx context.Context, siteID int64, limit, offset int) ([]*Funnel, error) {
+	funnels, err := s.store.GetBySite(ctx, siteID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list funnels: %w", err)
+	}
+	return funnels, nil
+}
+
+func (s *Service) Get(ctx context.Context, siteID, id int64) (*Funnel, error) {
+	funnel, err := s.store.GetByID(ctx, siteID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funnel: %w", err)
+	}
+	return funnel, nil
+}
+
+func (s *Service) Create(ctx context.Context, siteID int64, input Input) (*Funnel, error) {
+	normalized, err := normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireUniqueName(ctx, siteID, normalized.Name, 0); err != nil {
+		return nil, err
+	}
+
+	funnel := &Funnel{
+		SiteID: siteID,
+		Name:   normalized.Name,
+		Steps:  normalized.Steps,
+	}
+	if err := s.store.Create(ctx, funnel); err != nil {
+		return nil, fmt.Errorf("failed to create funnel: %w", err)
+	}
+	return funnel, nil
+}
+
+func (s *Service) Update(ctx context.Context, siteID, id int64, input Input) (*Funnel, error) {
+	normalized, err := normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	funnel, err := s.store.GetByID(ctx, siteID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funnel: %w", err)
+	}
+	if err := s.requireUniqueName(ctx, siteID, normalized.Name, id); err != nil {
+		return nil, err
+	}
+
+	funnel.Name = normalized.Name
+	funnel.Steps = normalized.Steps
+	if err := s.store.Update(ctx, funnel); err != nil {
+		return nil, fmt.Errorf("failed to update funnel: %w", err)
+	}
+	return funnel, nil
+}
+
+func (s *Service) Delete(ctx context.Context, siteID, id int64) error {
+	if err := s.store.Delete(ctx, siteID, id); err != nil {
+		return fmt.Errorf("failed to delete funnel: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) requireUniqueName(ctx context.Context, siteID int64, name string, excludedID int64) error {
+	exists, err := s.store.NameExists(ctx, siteID, name, excludedID)
+	if err != nil {
+		return fmt.Errorf("failed to check funnel name availability: %w", err)
+	}
+	if exists {
+		return ErrFunnelExists
+	}
+	return nil
+}
+
+func normalizeInput(input Input) (Input, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxFunnelNameLength {
+		return Input{}, ErrInvalidFunnelName
+	}
+	if len(input.Steps) < minFunnelSteps || len(input.Steps) > maxFunnelSteps {
+		return Input{}, ErrInvalidFunnelSteps
+	}
+
+	steps := make([]Step, 0, len(input.Steps))
+	for _, step := range input.Steps {
+		normalized, err := normalizeStep(step)
+		if err != nil {
+			return Input{}, err
+		}
+		// A repeated step would match the same event twice within one second.
+		if len(steps) > 0 && steps[len(steps)-1] == normalized {
+			return Input{}, ErrRepeatedStep
+		}
+		steps = append(steps, normalized)
+	}
+
+	return Input{Name: name, Steps: steps}, nil
+}
+
+func normalizeStep(step Step) (Step, error) {
+	value := strings.TrimSpace(step.Value)
+	switch step.Type {
+	case StepTypePage:
+		if !strings.HasPrefix(value, "/") || len(value) > maxStepPathLength {
+			return Step{}, ErrInvalidStepValue
+		}
+	case StepTypeEvent:
+		if value == "" || len(value) > maxStepEventLength {
+			return Step{}, ErrInvalidStepValue
+		}
+	default:
+		return Step{}, ErrInvalidStepType
+	}
+	return Step{Type: step.Type, Value: value}, nil
+}
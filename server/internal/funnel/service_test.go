@@ -0,0 +1,74 @@
+package funnel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeInput(t *testing.T) {
+	pricing := Step{Type: StepTypePage, Value: "/pricing"}
+	signup := Step{Type: StepTypeEvent, Value: "signup_completed"}
+
+	tests := []struct {
+		name      string
+		input     Input
+		want      Input
+		wantError error
+	}{
+		{
+			name:  "trims name and values",
+			input: Input{Name: " Signup ", Steps: []Step{{Type: StepTypePage, Value: " /pricing "}, signup}},
+			want:  Input{Name: "Signup", Steps: []Step{pricing, signup}},
+		},
+		{
+			name:      "empty name",
+			input:     Input{Name: " ", Steps: []Step{pricing, signup}},
+			wantError: ErrInvalidFunnelName,
+		},
+		{
+			name:      "single step",
+			input:     Input{Name: "Signup", Steps: []Step{pricing}},
+			wantError: ErrInvalidFunnelSteps,
+		},
+		{
+			name:      "too many steps",
+			input:     Input{Name: "Signup", Steps: []Step{pricing, signup, pricing, signup, pricing, signup, pricing, signup, pricing}},
+			wantError: ErrInvalidFunnelSteps,
+		},
+		{
+			name:      "relative page path",
+			input:     Input{Name: "Signup", Steps: []Step{{Type: StepTypePage, Value: "pricing"}, signup}},
+			wantError: ErrInvalidStepValue,
+		},
+		{
+			name:      "unknown step type",
+			input:     Input{Name: "Signup", Steps: []Step{{Type: StepType(9), Value: "/pricing"}, signup}},
+			wantError: ErrInvalidStepType,
+		},
+		{
+			name:      "consecutive repeated step",
+			input:     Input{Name: "Signup", Steps: []Step{pricing, {Type: StepTypePage, Value: "/pricing "}}},
+			wantError: ErrRepeatedStep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeInput(tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("normalizeInput() error = %v, want %v", err, tt.wantError)
+			}
+			if tt.wantError != nil {
+				return
+			}
+			if got.Name != tt.want.Name || len(got.Steps) != len(tt.want.Steps) {
+				t.Fatalf("normalizeInput() = %+v, want %+v", got, tt.want)
+			}
+			for index := range got.Steps {
+				if got.Steps[index] != tt.want.Steps[index] {
+					t.Fatalf("normalizeInput() step %d = %+v, want %+v", index, got.Steps[index], tt.want.Steps[index])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/goal"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ goal.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetBySite(ctx context.Context, siteID int64, limit, offset int) ([]*goal.Goal, error) {
+	var rows []*Goal
+	q := r.db.NewSelect().
+		Model(&rows).
+		Where("site_id = ?", siteID).
+		Order("name ASC", "id ASC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get goals by site: %w", err)
+	}
+	result := make([]*goal.Goal, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, goalFromModel(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, siteID, id int64) (*goal.Goal, error) {
+	row := new(Goal)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("id = ?", id).
+		Where("site_id = ?", siteID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get goal by id: %w", goal.ErrGoalNotFound)
+		}
+		return nil, fmt.Errorf("failed to get goal by id: %w", err)
+	}
+	return goalFromModel(row), nil
+}
+
+func (r *Repository) NameExists(ctx context.Context, siteID int64, name string, excludedID int64) (bool, error) {
+	q := r.db.NewSelect().
+		Model((*Goal)(nil)).
+		Where("site_id = ?", siteID).
+		Where("name = ?", name)
+	if excludedID > 0 {
+		q = q.Where("id != ?", excludedID)
+	}
+	exists, err := q.Exists(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check goal name: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *Repository) Create(ctx context.Context, value *goal.Goal) error {
+	now := time.Now()
+	row := goalModel(value)
+	row.CreatedAt = now
+	row.UpdatedAt = now
+	if _, err := r.db.NewInsert().Model(row).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to insert goal: %w", err)
+	}
+	*value = *goalFromModel(row)
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, value *goal.Goal) error {
+	row := goalModel(value)
+	row.UpdatedAt = time.Now()
+	result, err := r.db.NewUpdate().
+		Model(row).
+		Column("name", "type", "value", "updated_at").
+		Where("id = ?", row.ID).
+		Where("site_id = ?", row.SiteID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+	if err := requireAffectedGoal(result, "update goal"); err != nil {
+		return err
+	}
+	value.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, siteID, id int64) error {
+	result, err := r.db.NewDelete().
+		Model((*Goal)(nil)).
+		Where("id = ?", id).
+		Where("site_id = ?", siteID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	return requireAffectedGoal(result, "delete goal")
+}
+
+func requireAffectedGoal(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, goal.ErrGoalNotFound)
+	}
+	return nil
+}
+
+func goalFromModel(row *Goal) *goal.Goal {
+	return &goal.Goal{
+		ID:        row.ID,
+		SiteID:    row.SiteID,
+		Name:      row.Name,
+		Type:      goal.Type(row.Type),
+		Value:     row.Value,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func goalModel(value *goal.Goal) *Goal {
+	return &Goal{
+		ID:        value.ID,
+		SiteID:    value.SiteID,
+		Name:      value.Name,
+		Type:      GoalType(value.Type),
+		Value:     value.Value,
+		CreatedAt: value.CreatedAt,
+		UpdatedAt: value.UpdatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/goal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CreateUpdateDeleteGoal(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := New(db)
+	site := createTestSite(t, db)
+	ctx := context.Background()
+
+	created := &goal.Goal{SiteID: site.ID, Name: "Checkout", Type: goal.TypePage, Value: "/checkout/thanks"}
+	require.NoError(t, repo.Create(ctx, created))
+	require.NotZero(t, created.ID)
+	require.False(t, created.CreatedAt.IsZero())
+
+	exists, err := repo.NameExists(ctx, site.ID, "Checkout", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = repo.NameExists(ctx, site.ID, "Checkout", created.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	created.Name = "Signup"
+	created.Type = goal.TypeEvent
+	created.Value = "signup_completed"
+	require.NoError(t, repo.Update(ctx, created))
+
+	loaded, err := repo.GetByID(ctx, site.ID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Signup", loaded.Name)
+	require.Equal(t, goal.TypeEvent, loaded.Type)
+	require.Equal(t, "signup_completed", loaded.Value)
+
+	goals, err := repo.GetBySite(ctx, site.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, goals, 1)
+
+	require.NoError(t, repo.Delete(ctx, site.ID, created.ID))
+	_, err = repo.GetByID(ctx, site.ID, created.ID)
+	require.True(t, errors.Is(err, goal.ErrGoalNotFound))
+}
+
+func TestRepository_GoalMutationsAreScopedToSite(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := New(db)
+	site := createTestSite(t, db)
+	ctx := context.Background()
+
+	created := &goal.Goal{SiteID: site.ID, Name: "Checkout", Type: goal.TypePage, Value: "/checkout/thanks"}
+	require.NoError(t, repo.Create(ctx, created))
+
+	otherSiteID := site.ID + 1
+	_, err := repo.GetByID(ctx, otherSiteID, created.ID)
+	require.True(t, errors.Is(err, goal.ErrGoalNotFound))
+
+	err = repo.Delete(ctx, otherSiteID, created.ID)
+	require.True(t, errors.Is(err, goal.ErrGoalNotFound))
+
+	err = repo.Update(ctx, &goal.Goal{ID: created.ID, SiteID: otherSiteID, Name: "Moved", Type: goal.TypePage, Value: "/"})
+	require.True(t, errors.Is(err, goal.ErrGoalNotFound))
+}
@@ -0,0 +1,26 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type GoalType int8
+
+const (
+	GoalTypePage GoalType = iota
+	GoalTypeEvent
+)
+
+type Goal struct {
+	bun.BaseModel `bun:"table:goals,alias:g"`
+
+	ID        int64     `bun:"id,pk,autoincrement"`
+	SiteID    int64     `bun:"site_id,notnull,unique:goals_site_id_name"`
+	Name      string    `bun:"name,notnull,type:varchar(100),unique:goals_site_id_name"`
+	Type      GoalType  `bun:"type,notnull"`
+	Value     string    `bun:"value,notnull,type:varchar(2048)"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
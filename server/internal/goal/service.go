@@ -0,0 +1,157 @@
+package goal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	maxGoalNameLength  = 100
+	maxGoalPathLength  = 2048
+	maxGoalEventLength = 100
+)
+
+var (
+	ErrGoalNotFound     = errors.New("goal not found")
+	ErrGoalExists       = errors.New("goal with this name already exists")
+	ErrInvalidGoalName  = errors.New("invalid goal name")
+	ErrInvalidGoalType  = errors.New("invalid goal type")
+	ErrInvalidGoalValue = errors.New("invalid goal value")
+)
+
+// Type selects what a goal matches: a page view path or a predefined event name.
+type Type int8
+
+const (
+	TypePage  Type = 0
+	TypeEvent Type = 1
+)
+
+type Goal struct {
+	ID        int64
+	SiteID    int64
+	Name      string
+	Type      Type
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Store interface {
+	GetBySite(ctx context.Context, siteID int64, limit, offset int) ([]*Goal, error)
+	GetByID(ctx context.Context, siteID, id int64) (*Goal, error)
+	NameExists(ctx context.Context, siteID int64, name string, excludedID int64) (bool, error)
+	Create(ctx context.Context, goal *Goal) error
+	Update(ctx context.Context, goal *Goal) error
+	Delete(ctx context.Context, siteID, id int64) error
+}
+
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+type Input struct {
+	Name  string
+	Type  Type
+	Value string
+}
+
+func (s *Service) List(ctx context.Context, siteID int64, limit, offset int) ([]*Goal, error) {
+	goals, err := s.store.GetBySite(ctx, siteID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	return goals, nil
+}
+
+func (s *Service) Create(ctx context.Context, siteID int64, input Input) (*Goal, error) {
+	normalized, err := normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireUniqueName(ctx, siteID, normalized.Name, 0); err != nil {
+		return nil, err
+	}
+
+	goal := &Goal{
+		SiteID: siteID,
+		Name:   normalized.Name,
+		Type:   normalized.Type,
+		Value:  normalized.Value,
+	}
+	if err := s.store.Create(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+	return goal, nil
+}
+
+func (s *Service) Update(ctx context.Context, siteID, id int64, input Input) (*Goal, error) {
+	normalized, err := normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := s.store.GetByID(ctx, siteID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+	if err := s.requireUniqueName(ctx, siteID, normalized.Name, id); err != nil {
+		return nil, err
+	}
+
+	goal.Name = normalized.Name
+	goal.Type = normalized.Type
+	goal.Value = normalized.Value
+	if err := s.store.Update(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to update goal: %w", err)
+	}
+	return goal, nil
+}
+
+func (s *Service) Delete(ctx context.Context, siteID, id int64) error {
+	if err := s.store.Delete(ctx, siteID, id); err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) requireUniqueName(ctx context.Context, siteID int64, name string, excludedID int64) error {
+	exists, err := s.store.NameExists(ctx, siteID, name, excludedID)
+	if err != nil {
+		return fmt.Errorf("failed to check goal name availability: %w", err)
+	}
+	if exists {
+		return ErrGoalExists
+	}
+	return nil
+}
+
+func normalizeInput(input Input) (Input, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxGoalNameLength {
+		return Input{}, ErrInvalidGoalName
+	}
+
+	value := strings.TrimSpace(input.Value)
+	switch input.Type {
+	case TypePage:
+		if !strings.HasPrefix(value, "/") || len(value) > maxGoalPathLength {
+			return Input{}, ErrInvalidGoalValue
+		}
+	case TypeEvent:
+		if value == "" || len(value) > maxGoalEventLength {
+			return Input{}, ErrInvalidGoalValue
+		}
+	default:
+		return Input{}, ErrInvalidGoalType
+	}
+
+	return Input{Name: name, Type: input.Type, Value: value}, nil
+}
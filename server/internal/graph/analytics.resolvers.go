@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"time"
 
 	analyticfeature "github.com/lovely-eye/server/internal/analytics"
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/site"
 )
 
 // TopPages is the resolver for the topPages field.
@@ -42,6 +42,70 @@ func (r *dashboardStatsResolver) TopPages(ctx context.Context, obj *model.Dashbo
 	}, nil
 }
 
+// EntryPages is the resolver for the entryPages field.
+func (r *dashboardStatsResolver) EntryPages(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedEntryPageStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, err := r.AnalyticsService.GetEntryPagesWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry pages: %w", err)
+	}
+
+	items := make([]*model.EntryPageStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.EntryPageStats{
+			Path:       stat.Path,
+			Sessions:   stat.Sessions,
+			Visitors:   stat.Visitors,
+			BounceRate: stat.BounceRate,
+		})
+	}
+
+	return &model.PagedEntryPageStats{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// ExitPages is the resolver for the exitPages field.
+func (r *dashboardStatsResolver) ExitPages(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedExitPageStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, err := r.AnalyticsService.GetExitPagesWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exit pages: %w", err)
+	}
+
+	items := make([]*model.ExitPageStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.ExitPageStats{
+			Path:     stat.Path,
+			Exits:    stat.Exits,
+			Visitors: stat.Visitors,
+			ExitRate: stat.ExitRate,
+		})
+	}
+
+	return &model.PagedExitPageStats{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
 // TopReferrers is the resolver for the topReferrers field.
 func (r *dashboardStatsResolver) TopReferrers(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedReferrerStats, error) {
 	limit, offset := normalizePaging(paging)
@@ -72,6 +136,92 @@ func (r *dashboardStatsResolver) TopReferrers(ctx context.Context, obj *model.Da
 	}, nil
 }
 
+// ReferrerDomains is the resolver for the referrerDomains field.
+func (r *dashboardStatsResolver) ReferrerDomains(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedReferrerStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, err := r.AnalyticsService.GetReferrerDomainsWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrer domains: %w", err)
+	}
+
+	items := make([]*model.ReferrerStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.ReferrerStats{
+			Referrer: stat.Referrer,
+			Visitors: stat.Visitors,
+		})
+	}
+
+	return &model.PagedReferrerStats{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// Channels is the resolver for the channels field.
+func (r *dashboardStatsResolver) Channels(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedChannelStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, totalVisitors, err := r.AnalyticsService.GetChannelStatsWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel stats: %w", err)
+	}
+
+	items := make([]*model.ChannelStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.ChannelStats{
+			Channel:  stat.Channel,
+			Visitors: stat.Visitors,
+		})
+	}
+
+	return &model.PagedChannelStats{
+		Items:         items,
+		Total:         total,
+		TotalVisitors: totalVisitors,
+	}, nil
+}
+
+// UtmSources is the resolver for the utmSources field.
+func (r *dashboardStatsResolver) UtmSources(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedUTMStats, error) {
+	return r.utmStats(ctx, obj, paging, analyticfeature.UTMDimensionSource)
+}
+
+// UtmMediums is the resolver for the utmMediums field.
+func (r *dashboardStatsResolver) UtmMediums(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedUTMStats, error) {
+	return r.utmStats(ctx, obj, paging, analyticfeature.UTMDimensionMedium)
+}
+
+// UtmCampaigns is the resolver for the utmCampaigns field.
+func (r *dashboardStatsResolver) UtmCampaigns(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedUTMStats, error) {
+	return r.utmStats(ctx, obj, paging, analyticfeature.UTMDimensionCampaign)
+}
+
+// UtmTerms is the resolver for the utmTerms field.
+func (r *dashboardStatsResolver) UtmTerms(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedUTMStats, error) {
+	return r.utmStats(ctx, obj, paging, analyticfeature.UTMDimensionTerm)
+}
+
+// UtmContents is the resolver for the utmContents field.
+func (r *dashboardStatsResolver) UtmContents(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedUTMStats, error) {
+	return r.utmStats(ctx, obj, paging, analyticfeature.UTMDimensionContent)
+}
+
 // Browsers is the resolver for the browsers field.
 func (r *dashboardStatsResolver) Browsers(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) ([]*model.BrowserStats, error) {
 	limit, offset := normalizePaging(paging)
@@ -160,6 +310,37 @@ func (r *dashboardStatsResolver) OperatingSystems(ctx context.Context, obj *mode
 	}, nil
 }
 
+// ScreenSizes is the resolver for the screenSizes field.
+func (r *dashboardStatsResolver) ScreenSizes(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedScreenSizeStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, totalVisitors, err := r.AnalyticsService.GetScreenSizeStatsWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screen size stats: %w", err)
+	}
+
+	items := make([]*model.ScreenSizeStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.ScreenSizeStats{
+			ScreenSize: stat.ScreenSize,
+			Visitors:   stat.Visitors,
+		})
+	}
+
+	return &model.PagedScreenSizeStats{
+		Items:         items,
+		Total:         total,
+		TotalVisitors: totalVisitors,
+	}, nil
+}
+
 // Countries is the resolver for the countries field.
 func (r *dashboardStatsResolver) Countries(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedCountryStats, error) {
 	limit, offset := normalizePaging(paging)
@@ -191,6 +372,78 @@ func (r *dashboardStatsResolver) Countries(ctx context.Context, obj *model.Dashb
 	}, nil
 }
 
+// Goals is the resolver for the goals field.
+func (r *dashboardStatsResolver) Goals(ctx context.Context, obj *model.DashboardStats, paging model.PagingInput) (*model.PagedGoalStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, totalVisitors, err := r.AnalyticsService.GetGoalStatsWithFilterPaged(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal stats: %w", err)
+	}
+
+	items := make([]*model.GoalStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.GoalStats{
+			Goal:           buildGraphQLGoal(&stat.Goal),
+			Conversions:    stat.Conversions,
+			ConversionRate: stat.ConversionRate,
+		})
+	}
+
+	return &model.PagedGoalStats{
+		Items:         items,
+		Total:         total,
+		TotalVisitors: totalVisitors,
+	}, nil
+}
+
+// Funnel is the resolver for the funnel field.
+func (r *dashboardStatsResolver) Funnel(ctx context.Context, obj *model.DashboardStats, id string) (*model.FunnelReport, error) {
+	funnelID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid funnel ID")
+	}
+
+	definition, err := r.FunnelService.Get(ctx, obj.SiteID, funnelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funnel: %w", err)
+	}
+
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Filter: obj.Filter,
+	}
+	stats, err := r.AnalyticsService.GetFunnelStatsWithFilter(ctx, query, definition.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funnel stats: %w", err)
+	}
+
+	steps := make([]*model.FunnelStepStats, 0, len(stats))
+	for _, stat := range stats {
+		steps = append(steps, &model.FunnelStepStats{
+			Step:           buildGraphQLFunnelStep(stat.Step),
+			Visitors:       stat.Visitors,
+			ConversionRate: stat.ConversionRate,
+			DropOff:        stat.DropOff,
+			DropOffRate:    stat.DropOffRate,
+		})
+	}
+
+	return &model.FunnelReport{
+		Funnel: buildGraphQLFunnel(definition),
+		Steps:  steps,
+	}, nil
+}
+
 // DailyStats is the resolver for the dailyStats field.
 func (r *dashboardStatsResolver) DailyStats(ctx context.Context, obj *model.DashboardStats, bucket *model.TimeBucket, paging model.PagingInput) ([]*model.DailyStats, error) {
 	var selectedBucket analyticfeature.TimeBucket
@@ -199,6 +452,10 @@ func (r *dashboardStatsResolver) DailyStats(ctx context.Context, obj *model.Dash
 		selectedBucket = analyticfeature.TimeBucketHourly
 	case model.TimeBucketDaily:
 		selectedBucket = analyticfeature.TimeBucketDaily
+	case model.TimeBucketWeekly:
+		selectedBucket = analyticfeature.TimeBucketWeekly
+	case model.TimeBucketMonthly:
+		selectedBucket = analyticfeature.TimeBucketMonthly
 	default:
 		return nil, badUserInput("invalid time bucket")
 	}
@@ -218,14 +475,32 @@ func (r *dashboardStatsResolver) DailyStats(ctx context.Context, obj *model.Dash
 	}
 
 	query := analyticfeature.Query{
-		SiteID: obj.SiteID,
-		From:   obj.From,
-		To:     obj.To,
-		Limit:  pointLimit,
-		Offset: offsetValue,
-		Bucket: selectedBucket,
-		Filter: obj.Filter,
+		SiteID:   obj.SiteID,
+		From:     obj.From,
+		To:       obj.To,
+		Limit:    pointLimit,
+		Offset:   offsetValue,
+		Bucket:   selectedBucket,
+		Location: obj.Location,
+		Filter:   obj.Filter,
+	}
+	if obj.Comparison != nil {
+		if err := validateDateRange(obj.Comparison.From, obj.Comparison.To, maxRangeDays); err != nil {
+			return nil, err
+		}
+		comparisons, err := r.AnalyticsService.CompareTimeSeriesStatsWithFilter(ctx, query, obj.Comparison.From, obj.Comparison.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time series comparison: %w", err)
+		}
+		items := make([]*model.DailyStats, 0, len(comparisons))
+		for _, stat := range comparisons {
+			item := buildGraphQLDailyStats(stat.TimeSeriesStats)
+			item.Comparison = buildGraphQLDailyStats(stat.Comparison)
+			items = append(items, item)
+		}
+		return items, nil
 	}
+
 	stats, err := r.AnalyticsService.GetTimeSeriesStatsWithFilter(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get time series stats: %w", err)
@@ -233,26 +508,14 @@ func (r *dashboardStatsResolver) DailyStats(ctx context.Context, obj *model.Dash
 
 	items := make([]*model.DailyStats, 0, len(stats))
 	for _, stat := range stats {
-		bucketSeconds := stat.DateBucket
-		switch selectedBucket {
-		case analyticfeature.TimeBucketDaily:
-			bucketSeconds = stat.DateBucket * 86400
-		case analyticfeature.TimeBucketHourly:
-			bucketSeconds = stat.DateBucket * 3600
-		}
-		items = append(items, &model.DailyStats{
-			Date:      time.Unix(bucketSeconds, 0),
-			Visitors:  stat.Visitors,
-			PageViews: stat.PageViews,
-			Sessions:  stat.Sessions,
-		})
+		items = append(items, buildGraphQLDailyStats(stat))
 	}
 
 	return items, nil
 }
 
 // Dashboard is the resolver for the dashboard field.
-func (r *queryResolver) Dashboard(ctx context.Context, siteID string, dateRange *model.DateRangeInput, filter *model.FilterInput) (*model.DashboardStats, error) {
+func (r *queryResolver) Dashboard(ctx context.Context, siteID string, dateRange *model.DateRangeInput, filter *model.FilterInput, compare *model.ComparisonInput) (*model.DashboardStats, error) {
 	claims := auth.GetUserFromContext(ctx)
 	if claims == nil {
 		return nil, unauthenticated()
@@ -263,40 +526,50 @@ func (r *queryResolver) Dashboard(ctx context.Context, siteID string, dateRange
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	location, err := r.requireSiteLocation(ctx, claims, id)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
-	from, to, err := parseDateRangeInput(dateRange, r.DashboardLimits.MaxDailyRangeDays)
+	from, to, err := parseDateRangeInput(dateRange, location, r.DashboardLimits.MaxDailyRangeDays)
 	if err != nil {
 		return nil, err
 	}
+	if err := requireSharedFilter(claims, filter); err != nil {
+		return nil, err
+	}
 	filterOpts, err := parseFilterInput(filter, r.DashboardLimits)
 	if err != nil {
 		return nil, err
 	}
+	query := analyticfeature.Query{
+		SiteID:   id,
+		From:     from,
+		To:       to,
+		Location: location,
+		Filter:   filterOpts,
+	}
 
-	stats, err := r.AnalyticsService.GetDashboardOverviewWithFilter(ctx, analyticfeature.Query{
-		SiteID: id,
-		From:   from,
-		To:     to,
-		Filter: filterOpts,
-	})
+	if compare != nil {
+		compareFrom, compareTo, err := parseComparisonInput(compare, from, to, location, r.DashboardLimits.MaxDailyRangeDays)
+		if err != nil {
+			return nil, err
+		}
+		comparison, err := r.AnalyticsService.CompareDashboardOverviewWithFilter(ctx, query, compareFrom, compareTo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dashboard comparison: %w", err)
+		}
+		stats := buildGraphQLDashboardStats(&comparison.Current, query)
+		stats.Comparison = buildGraphQLDashboardComparison(comparison, compareFrom, compareTo)
+		return stats, nil
+	}
+
+	stats, err := r.AnalyticsService.GetDashboardOverviewWithFilter(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dashboard overview: %w", err)
 	}
 
-	return &model.DashboardStats{
-		Visitors:    stats.Visitors,
-		PageViews:   stats.PageViews,
-		Sessions:    stats.Sessions,
-		BounceRate:  stats.BounceRate,
-		AvgDuration: stats.AvgDuration,
-		SiteID:      id,
-		From:        from,
-		To:          to,
-		Filter:      filterOpts,
-	}, nil
+	return buildGraphQLDashboardStats(stats, query), nil
 }
 
 // Realtime is the resolver for the realtime field.
@@ -311,7 +584,7 @@ func (r *queryResolver) Realtime(ctx context.Context, siteID string) (*model.Rea
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleViewer); err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+// CreateAPIToken is the resolver for the createAPIToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, input model.CreateAPITokenInput) (*model.CreatedAPIToken, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	siteIDs, err := parseAPITokenSiteIDs(input.SiteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	token, secret, err := r.APITokenService.Create(ctx, apitoken.CreateInput{
+		UserID:  claims.UserID,
+		Name:    input.Name,
+		SiteIDs: siteIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return &model.CreatedAPIToken{
+		Token:  buildGraphQLAPIToken(token),
+		Secret: secret,
+	}, nil
+}
+
+// RevokeAPIToken is the resolver for the revokeAPIToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, id string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	tokenID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid API token ID")
+	}
+
+	if err := r.APITokenService.Revoke(ctx, claims.UserID, tokenID); err != nil {
+		return false, fmt.Errorf("failed to revoke API token: %w", err)
+	}
+
+	return true, nil
+}
+
+// APITokens is the resolver for the apiTokens field.
+func (r *queryResolver) APITokens(ctx context.Context) ([]*model.APIToken, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := r.APITokenService.List(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %w", err)
+	}
+
+	result := make([]*model.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, buildGraphQLAPIToken(token))
+	}
+	return result, nil
+}
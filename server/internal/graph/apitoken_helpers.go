@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+func buildGraphQLAPIToken(token *apitoken.Token) *model.APIToken {
+	result := &model.APIToken{
+		ID:         strconv.FormatInt(token.ID, 10),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+	if token.SiteScoped {
+		result.SiteIds = make([]string, 0, len(token.SiteIDs))
+		for _, siteID := range token.SiteIDs {
+			result.SiteIds = append(result.SiteIds, strconv.FormatInt(siteID, 10))
+		}
+	}
+	return result
+}
+
+func parseAPITokenSiteIDs(values []string) ([]int64, error) {
+	siteIDs := make([]int64, 0, len(values))
+	for _, value := range values {
+		siteID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, badUserInput("invalid site ID")
+		}
+		siteIDs = append(siteIDs, siteID)
+	}
+	return siteIDs, nil
+}
+
+// requireSessionClaims rejects API tokens so that a leaked token cannot mint tokens or manage accounts.
+func requireSessionClaims(ctx context.Context) (*auth.Claims, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+	if claims.IsAPIToken() {
+		return nil, forbidden("this operation requires a signed-in session")
+	}
+	return claims, nil
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/audit"
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, siteID *string, paging model.PagingInput) (*model.PagedAuditEntries, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := normalizePaging(paging)
+	filter := audit.ListFilter{Limit: limit, Offset: offset}
+	if siteID != nil {
+		filter.SiteID, err = strconv.ParseInt(*siteID, 10, 64)
+		if err != nil {
+			return nil, badUserInput("invalid site ID")
+		}
+	}
+
+	admin, err := r.AuthService.IsAdmin(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check admin role: %w", err)
+	}
+	if !admin {
+		if filter.SiteID == 0 {
+			return nil, auth.ErrAdminRequired
+		}
+		if err := r.requireSiteRole(ctx, claims, filter.SiteID, site.RoleOwner); err != nil {
+			return nil, fmt.Errorf("failed to authorize audit log: %w", err)
+		}
+	}
+
+	entries, total, err := r.AuditService.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	items := make([]*model.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, buildGraphQLAuditEntry(entry))
+	}
+	return &model.PagedAuditEntries{Items: items, Total: total}, nil
+}
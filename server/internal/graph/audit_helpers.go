@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/audit"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+var graphQLAuditActions = map[audit.Action]model.AuditAction{
+	audit.ActionSiteUpdate:            model.AuditActionSiteUpdate,
+	audit.ActionSiteDelete:            model.AuditActionSiteDelete,
+	audit.ActionSiteKeyRegenerate:     model.AuditActionSiteRegenerateKey,
+	audit.ActionEventDefinitionUpsert: model.AuditActionEventDefinitionUpsert,
+	audit.ActionEventDefinitionDelete: model.AuditActionEventDefinitionDelete,
+	audit.ActionUserRoleChange:        model.AuditActionUserRoleChange,
+	audit.ActionUserLogin:             model.AuditActionUserLogin,
+}
+
+func buildGraphQLAuditEntry(entry *audit.Entry) *model.AuditEntry {
+	changes := make([]*model.AuditChange, 0, len(entry.Changes))
+	for _, change := range entry.Changes {
+		changes = append(changes, &model.AuditChange{
+			Field:  change.Field,
+			Before: auditValue(change.Before),
+			After:  auditValue(change.After),
+		})
+	}
+	result := &model.AuditEntry{
+		ID:           strconv.FormatInt(entry.ID, 10),
+		ActorID:      optionalID(entry.ActorID),
+		Action:       graphQLAuditActions[entry.Action],
+		SiteID:       optionalID(entry.SiteID),
+		TargetUserID: optionalID(entry.TargetUserID),
+		Changes:      changes,
+		CreatedAt:    entry.CreatedAt,
+	}
+	if entry.ActorName != "" {
+		result.ActorName = &entry.ActorName
+	}
+	return result
+}
+
+func auditValue(value json.RawMessage) *string {
+	if value == nil {
+		return nil
+	}
+	encoded := string(value)
+	return &encoded
+}
+
+func optionalID(id int64) *string {
+	if id == 0 {
+		return nil
+	}
+	value := strconv.FormatInt(id, 10)
+	return &value
+}
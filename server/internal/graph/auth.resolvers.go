@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/graph/model"
@@ -11,8 +13,9 @@ import (
 // Register is the resolver for the register field.
 func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInput) (*model.AuthPayload, error) {
 	user, tokens, err := r.AuthService.Register(ctx, auth.RegisterInput{
-		Username: input.Username,
-		Password: input.Password,
+		Username:  input.Username,
+		Password:  input.Password,
+		UserAgent: getUserAgent(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to register user: %w", err)
@@ -29,14 +32,43 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 
 // Login is the resolver for the login field.
 func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*model.AuthPayload, error) {
-	user, tokens, err := r.AuthService.Login(ctx, auth.LoginInput{
-		Username: input.Username,
-		Password: input.Password,
+	result, err := r.AuthService.Login(ctx, auth.LoginInput{
+		Username:  input.Username,
+		Password:  input.Password,
+		UserAgent: getUserAgent(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to login: %w", err)
 	}
 
+	if result.Tokens == nil {
+		return &model.AuthPayload{
+			User:              convertToGraphQLUser(result.User),
+			TwoFactorRequired: true,
+			TwoFactorToken:    &result.TwoFactorToken,
+		}, nil
+	}
+
+	if w := GetResponseWriter(ctx); w != nil {
+		r.AuthCookies.SetAuthCookies(w, result.Tokens)
+	}
+
+	return &model.AuthPayload{
+		User: convertToGraphQLUser(result.User),
+	}, nil
+}
+
+// VerifyTwoFactorLogin is the resolver for the verifyTwoFactorLogin field.
+func (r *mutationResolver) VerifyTwoFactorLogin(ctx context.Context, input model.VerifyTwoFactorLoginInput) (*model.AuthPayload, error) {
+	user, tokens, err := r.AuthService.VerifyTwoFactorLogin(ctx, auth.TwoFactorLoginInput{
+		TwoFactorToken: input.TwoFactorToken,
+		Code:           input.Code,
+		UserAgent:      getUserAgent(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify two-factor login: %w", err)
+	}
+
 	if w := GetResponseWriter(ctx); w != nil {
 		r.AuthCookies.SetAuthCookies(w, tokens)
 	}
@@ -48,9 +80,224 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 
 // Logout is the resolver for the logout field.
 func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
+	if claims := auth.GetUserFromContext(ctx); claims != nil && claims.SessionID != 0 {
+		err := r.AuthService.RevokeSession(ctx, claims.UserID, claims.SessionID)
+		if err != nil && !errors.Is(err, auth.ErrSessionNotFound) {
+			return false, fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	if w := GetResponseWriter(ctx); w != nil {
+		r.AuthCookies.ClearAuthCookies(w)
+	}
+	return true, nil
+}
+
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.AuthService.ChangePassword(ctx, claims.UserID, claims.SessionID, currentPassword, newPassword); err != nil {
+		return false, fmt.Errorf("failed to change password: %w", err)
+	}
+
+	return true, nil
+}
+
+// BeginTwoFactorEnrollment is the resolver for the beginTwoFactorEnrollment field.
+func (r *mutationResolver) BeginTwoFactorEnrollment(ctx context.Context) (*model.TwoFactorEnrollment, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := r.AuthService.BeginTwoFactorEnrollment(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin two-factor enrollment: %w", err)
+	}
+
+	return &model.TwoFactorEnrollment{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}, nil
+}
+
+// EnableTwoFactor is the resolver for the enableTwoFactor field.
+func (r *mutationResolver) EnableTwoFactor(ctx context.Context, code string) ([]string, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := r.AuthService.EnableTwoFactor(ctx, claims.UserID, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor is the resolver for the disableTwoFactor field.
+func (r *mutationResolver) DisableTwoFactor(ctx context.Context, password string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.AuthService.DisableTwoFactor(ctx, claims.UserID, password); err != nil {
+		return false, fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sessionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid session ID")
+	}
+
+	if err := r.AuthService.RevokeSession(ctx, claims.UserID, sessionID); err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if sessionID == claims.SessionID {
+		if w := GetResponseWriter(ctx); w != nil {
+			r.AuthCookies.ClearAuthCookies(w)
+		}
+	}
+
+	return true, nil
+}
+
+// RevokeAllSessions is the resolver for the revokeAllSessions field.
+func (r *mutationResolver) RevokeAllSessions(ctx context.Context) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.AuthService.RevokeAllSessions(ctx, claims.UserID); err != nil {
+		return false, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
 	if w := GetResponseWriter(ctx); w != nil {
 		r.AuthCookies.ClearAuthCookies(w)
 	}
+
+	return true, nil
+}
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*model.User, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var role string
+	if input.Role != nil {
+		role = *input.Role
+	}
+	user, err := r.AuthService.CreateUser(ctx, claims.UserID, auth.CreateUserInput{
+		Username: input.Username,
+		Password: input.Password,
+		Role:     role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return convertToGraphQLUser(user), nil
+}
+
+// ResetUserPassword is the resolver for the resetUserPassword field.
+func (r *mutationResolver) ResetUserPassword(ctx context.Context, id string, password string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid user ID")
+	}
+
+	if err := r.AuthService.ResetPassword(ctx, claims.UserID, userID, password); err != nil {
+		return false, fmt.Errorf("failed to reset user password: %w", err)
+	}
+
+	return true, nil
+}
+
+// SetUserRole is the resolver for the setUserRole field.
+func (r *mutationResolver) SetUserRole(ctx context.Context, id string, role string) (*model.User, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid user ID")
+	}
+
+	user, err := r.AuthService.SetRole(ctx, claims.UserID, userID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set user role: %w", err)
+	}
+
+	return convertToGraphQLUser(user), nil
+}
+
+// ResetUserTwoFactor is the resolver for the resetUserTwoFactor field.
+func (r *mutationResolver) ResetUserTwoFactor(ctx context.Context, id string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid user ID")
+	}
+
+	if err := r.AuthService.ResetTwoFactor(ctx, claims.UserID, userID); err != nil {
+		return false, fmt.Errorf("failed to reset user two-factor authentication: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string, transferSitesTo *string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid user ID")
+	}
+	var transferUserID int64
+	if transferSitesTo != nil {
+		transferUserID, err = strconv.ParseInt(*transferSitesTo, 10, 64)
+		if err != nil {
+			return false, badUserInput("invalid transfer user ID")
+		}
+	}
+
+	if err := r.AuthService.DeleteUser(ctx, claims.UserID, userID, transferUserID); err != nil {
+		return false, fmt.Errorf("failed to delete user: %w", err)
+	}
+
 	return true, nil
 }
 
@@ -82,6 +329,45 @@ func (r *queryResolver) RegistrationStatus(ctx context.Context) (*model.Registra
 	}, nil
 }
 
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, paging model.PagingInput) ([]*model.User, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := normalizePaging(paging)
+	users, err := r.AuthService.ListUsers(ctx, claims.UserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	result := make([]*model.User, 0, len(users))
+	for _, user := range users {
+		result = append(result, convertToGraphQLUser(user))
+	}
+	return result, nil
+}
+
+// Sessions is the resolver for the sessions field.
+func (r *queryResolver) Sessions(ctx context.Context) ([]*model.AuthSession, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := r.AuthService.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	result := make([]*model.AuthSession, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, convertToGraphQLAuthSession(session, claims.SessionID))
+	}
+	return result, nil
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/lovely-eye/server/internal/analytics"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+func parseComparisonInput(input *model.ComparisonInput, from, to time.Time, loc *time.Location, maxRangeDays int) (time.Time, time.Time, error) {
+	switch input.Mode {
+	case model.ComparisonModePreviousPeriod:
+		compareFrom, compareTo := analytics.PreviousPeriod(from, to)
+		return compareFrom, compareTo, nil
+	case model.ComparisonModeCustom:
+		if input.DateRange == nil || input.DateRange.From == nil || input.DateRange.To == nil {
+			return time.Time{}, time.Time{}, badUserInput("comparison date range requires from and to")
+		}
+		compareFrom, compareTo, err := parseDateRangeInput(input.DateRange, loc, maxRangeDays)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return compareFrom, compareTo, nil
+	default:
+		return time.Time{}, time.Time{}, badUserInput("invalid comparison mode")
+	}
+}
+
+func buildGraphQLDashboardComparison(comparison *analytics.OverviewComparison, from, to time.Time) *model.DashboardComparison {
+	previous := comparison.Previous
+	return &model.DashboardComparison{
+		From:        from,
+		To:          to,
+		Visitors:    previous.Visitors,
+		PageViews:   previous.PageViews,
+		Sessions:    previous.Sessions,
+		BounceRate:  previous.BounceRate,
+		AvgDuration: previous.AvgDuration,
+		Deltas: &model.OverviewDeltas{
+			Visitors:    buildGraphQLMetricDelta(comparison.Deltas.Visitors),
+			PageViews:   buildGraphQLMetricDelta(comparison.Deltas.PageViews),
+			Sessions:    buildGraphQLMetricDelta(comparison.Deltas.Sessions),
+			BounceRate:  buildGraphQLMetricDelta(comparison.Deltas.BounceRate),
+			AvgDuration: buildGraphQLMetricDelta(comparison.Deltas.AvgDuration),
+		},
+	}
+}
+
+func buildGraphQLMetricDelta(delta analytics.MetricDelta) *model.MetricDelta {
+	return &model.MetricDelta{Change: delta.Change, PercentChange: delta.PercentChange}
+}
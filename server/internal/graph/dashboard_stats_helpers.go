@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"github.com/lovely-eye/server/internal/analytics"
 	"github.com/lovely-eye/server/internal/graph/model"
 )
 
@@ -31,3 +32,27 @@ func bucketValueOrDefault(value *model.TimeBucket) model.TimeBucket {
 	}
 	return *value
 }
+
+func buildGraphQLDashboardStats(overview *analytics.Overview, query analytics.Query) *model.DashboardStats {
+	return &model.DashboardStats{
+		Visitors:    overview.Visitors,
+		PageViews:   overview.PageViews,
+		Sessions:    overview.Sessions,
+		BounceRate:  overview.BounceRate,
+		AvgDuration: overview.AvgDuration,
+		SiteID:      query.SiteID,
+		From:        query.From,
+		To:          query.To,
+		Location:    query.Location,
+		Filter:      query.Filter,
+	}
+}
+
+func buildGraphQLDailyStats(stat analytics.TimeSeriesStats) *model.DailyStats {
+	return &model.DailyStats{
+		Date:      stat.Date,
+		Visitors:  stat.Visitors,
+		PageViews: stat.PageViews,
+		Sessions:  stat.Sessions,
+	}
+}
@@ -7,9 +7,14 @@ import (
 	"log/slog"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/lovely-eye/server/internal/apitoken"
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/country"
 	"github.com/lovely-eye/server/internal/event"
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/goal"
+	"github.com/lovely-eye/server/internal/ingestkey"
+	"github.com/lovely-eye/server/internal/share"
 	"github.com/lovely-eye/server/internal/site"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -40,6 +45,10 @@ func badUserInputf(format string, args ...any) error {
 	return badUserInput(fmt.Sprintf(format, args...))
 }
 
+func forbidden(message string) error {
+	return &operationError{code: errorCodeForbidden, message: message}
+}
+
 func unauthenticated() error {
 	return &operationError{code: errorCodeUnauthenticated, message: "unauthorized"}
 }
@@ -68,13 +77,13 @@ func classifyError(err error) errorCode {
 	}
 
 	switch {
-	case errors.Is(err, site.ErrNotAuthorized), errors.Is(err, auth.ErrRegistrationDisabled):
+	case errors.Is(err, site.ErrNotAuthorized), errors.Is(err, auth.ErrRegistrationDisabled), errors.Is(err, auth.ErrAdminRequired):
 		return errorCodeForbidden
-	case errors.Is(err, site.ErrSiteNotFound), errors.Is(err, country.ErrNotFound):
+	case errors.Is(err, site.ErrSiteNotFound), errors.Is(err, country.ErrNotFound), errors.Is(err, goal.ErrGoalNotFound), errors.Is(err, funnel.ErrFunnelNotFound), errors.Is(err, apitoken.ErrTokenNotFound), errors.Is(err, site.ErrMemberNotFound), errors.Is(err, auth.ErrAccountNotFound), errors.Is(err, auth.ErrSessionNotFound), errors.Is(err, share.ErrLinkNotFound), errors.Is(err, share.ErrInvalidLink), errors.Is(err, ingestkey.ErrKeyNotFound):
 		return errorCodeNotFound
-	case errors.Is(err, site.ErrSiteExists), errors.Is(err, auth.ErrUserExists):
+	case errors.Is(err, site.ErrSiteExists), errors.Is(err, auth.ErrUserExists), errors.Is(err, goal.ErrGoalExists), errors.Is(err, funnel.ErrFunnelExists), errors.Is(err, site.ErrMemberExists):
 		return errorCodeConflict
-	case errors.Is(err, auth.ErrUserNotFound):
+	case errors.Is(err, auth.ErrUserNotFound), errors.Is(err, apitoken.ErrInvalidToken), errors.Is(err, share.ErrWrongPassword):
 		return errorCodeUnauthenticated
 	case isValidationError(err):
 		return errorCodeBadUserInput
@@ -92,16 +101,46 @@ func isValidationError(err error) bool {
 	return errors.Is(err, auth.ErrInvalidCredentials) ||
 		errors.Is(err, auth.ErrInvalidUsername) ||
 		errors.Is(err, auth.ErrInvalidPassword) ||
+		errors.Is(err, auth.ErrInvalidRole) ||
+		errors.Is(err, auth.ErrLastAdmin) ||
+		errors.Is(err, auth.ErrCannotDeleteSelf) ||
+		errors.Is(err, auth.ErrInvalidSiteTransfer) ||
+		errors.Is(err, auth.ErrInvalidTwoFactorCode) ||
+		errors.Is(err, auth.ErrTwoFactorEnabled) ||
+		errors.Is(err, auth.ErrTwoFactorNotEnabled) ||
+		errors.Is(err, auth.ErrTwoFactorNotEnrolling) ||
 		errors.Is(err, site.ErrInvalidDomain) ||
 		errors.Is(err, site.ErrInvalidSiteName) ||
 		errors.Is(err, site.ErrDomainTooLong) ||
 		errors.Is(err, site.ErrSiteNameTooLong) ||
 		errors.Is(err, site.ErrInvalidIPAddress) ||
 		errors.Is(err, site.ErrInvalidCountryCode) ||
+		errors.Is(err, site.ErrInvalidTimezone) ||
 		errors.Is(err, site.ErrTooManyBlockedIPs) ||
 		errors.Is(err, site.ErrTooManyBlockedCountries) ||
+		errors.Is(err, site.ErrInvalidRole) ||
+		errors.Is(err, site.ErrMemberUserAbsent) ||
+		errors.Is(err, site.ErrTooManyMembers) ||
 		errors.Is(err, event.ErrInvalidEventName) ||
+		errors.Is(err, event.ErrReservedEventName) ||
 		errors.Is(err, event.ErrInvalidFieldKey) ||
 		errors.Is(err, event.ErrInvalidFieldType) ||
-		errors.Is(err, event.ErrInvalidFieldLimit)
+		errors.Is(err, event.ErrInvalidFieldLimit) ||
+		errors.Is(err, goal.ErrInvalidGoalName) ||
+		errors.Is(err, goal.ErrInvalidGoalType) ||
+		errors.Is(err, goal.ErrInvalidGoalValue) ||
+		errors.Is(err, funnel.ErrInvalidFunnelName) ||
+		errors.Is(err, funnel.ErrInvalidFunnelSteps) ||
+		errors.Is(err, funnel.ErrInvalidStepType) ||
+		errors.Is(err, funnel.ErrInvalidStepValue) ||
+		errors.Is(err, funnel.ErrRepeatedStep) ||
+		errors.Is(err, apitoken.ErrInvalidTokenName) ||
+		errors.Is(err, apitoken.ErrInvalidTokenSites) ||
+		errors.Is(err, apitoken.ErrTooManyTokens) ||
+		errors.Is(err, share.ErrInvalidLinkName) ||
+		errors.Is(err, share.ErrInvalidLinkPassword) ||
+		errors.Is(err, share.ErrInvalidBreakdown) ||
+		errors.Is(err, share.ErrTooManyLinks) ||
+		errors.Is(err, ingestkey.ErrInvalidKeyName) ||
+		errors.Is(err, ingestkey.ErrTooManyKeys)
 }
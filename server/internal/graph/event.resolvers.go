@@ -10,6 +10,7 @@ import (
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/event"
 	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/site"
 )
 
 // UpsertEventDefinition is the resolver for the upsertEventDefinition field.
@@ -24,7 +25,7 @@ func (r *mutationResolver) UpsertEventDefinition(ctx context.Context, siteID str
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleEditor); err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
@@ -38,7 +39,7 @@ func (r *mutationResolver) UpsertEventDefinition(ctx context.Context, siteID str
 		})
 	}
 
-	definition, err := r.EventDefService.Upsert(ctx, id, event.DefinitionInput{
+	definition, err := r.EventDefService.Upsert(ctx, id, claims.UserID, event.DefinitionInput{
 		Name:   input.Name,
 		Fields: fields,
 	})
@@ -62,11 +63,11 @@ func (r *mutationResolver) DeleteEventDefinition(ctx context.Context, siteID str
 		return false, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleEditor); err != nil {
 		return false, fmt.Errorf("failed to get site: %w", err)
 	}
 
-	if err := r.EventDefService.Delete(ctx, id, name); err != nil {
+	if err := r.EventDefService.Delete(ctx, id, claims.UserID, name); err != nil {
 		return false, fmt.Errorf("failed to delete event definition: %w", err)
 	}
 
@@ -85,11 +86,12 @@ func (r *queryResolver) Events(ctx context.Context, siteID string, dateRange *mo
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	location, err := r.requireSiteLocation(ctx, claims, id)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
-	from, to, err := parseDateRangeInput(dateRange, r.DashboardLimits.MaxDailyRangeDays)
+	from, to, err := parseDateRangeInput(dateRange, location, r.DashboardLimits.MaxDailyRangeDays)
 	if err != nil {
 		return nil, err
 	}
@@ -134,17 +136,21 @@ func (r *queryResolver) EventCounts(ctx context.Context, siteID string, dateRang
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	location, err := r.requireSiteLocation(ctx, claims, id)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
-	from, to, err := parseDateRangeInput(dateRange, r.DashboardLimits.MaxDailyRangeDays)
+	from, to, err := parseDateRangeInput(dateRange, location, r.DashboardLimits.MaxDailyRangeDays)
 	if err != nil {
 		return nil, err
 	}
 
 	limit, offset := normalizePaging(paging)
 
+	if err := requireSharedFilter(claims, filter); err != nil {
+		return nil, err
+	}
 	filterOpts, err := parseFilterInput(filter, r.DashboardLimits)
 	if err != nil {
 		return nil, err
@@ -185,7 +191,7 @@ func (r *queryResolver) EventDefinitions(ctx context.Context, siteID string, pag
 		return nil, badUserInput("invalid site ID")
 	}
 
-	if err := r.SiteService.RequireOwnership(ctx, id, claims.UserID); err != nil {
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleViewer); err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
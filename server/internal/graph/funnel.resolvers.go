@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+// CreateFunnel is the resolver for the createFunnel field.
+func (r *mutationResolver) CreateFunnel(ctx context.Context, siteID string, input model.FunnelInput) (*model.Funnel, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleEditor); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	funnelInput, err := parseFunnelInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.FunnelService.Create(ctx, id, funnelInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create funnel: %w", err)
+	}
+
+	return buildGraphQLFunnel(created), nil
+}
+
+// UpdateFunnel is the resolver for the updateFunnel field.
+func (r *mutationResolver) UpdateFunnel(ctx context.Context, siteID string, id string, input model.FunnelInput) (*model.Funnel, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	parsedSiteID, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+	funnelID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid funnel ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, parsedSiteID, site.RoleEditor); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	funnelInput, err := parseFunnelInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.FunnelService.Update(ctx, parsedSiteID, funnelID, funnelInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update funnel: %w", err)
+	}
+
+	return buildGraphQLFunnel(updated), nil
+}
+
+// DeleteFunnel is the resolver for the deleteFunnel field.
+func (r *mutationResolver) DeleteFunnel(ctx context.Context, siteID string, id string) (bool, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return false, unauthenticated()
+	}
+
+	parsedSiteID, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid site ID")
+	}
+	funnelID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid funnel ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, parsedSiteID, site.RoleEditor); err != nil {
+		return false, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	if err := r.FunnelService.Delete(ctx, parsedSiteID, funnelID); err != nil {
+		return false, fmt.Errorf("failed to delete funnel: %w", err)
+	}
+
+	return true, nil
+}
+
+// Funnels is the resolver for the funnels field.
+func (r *queryResolver) Funnels(ctx context.Context, siteID string, paging model.PagingInput) ([]*model.Funnel, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleViewer); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	limit, offset := normalizePaging(paging)
+	funnels, err := r.FunnelService.List(ctx, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list funnels: %w", err)
+	}
+
+	result := make([]*model.Funnel, 0, len(funnels))
+	for _, value := range funnels {
+		result = append(result, buildGraphQLFunnel(value))
+	}
+	return result, nil
+}
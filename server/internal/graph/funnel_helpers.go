@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+func buildGraphQLFunnel(value *funnel.Funnel) *model.Funnel {
+	steps := make([]*model.FunnelStep, 0, len(value.Steps))
+	for _, step := range value.Steps {
+		steps = append(steps, buildGraphQLFunnelStep(step))
+	}
+	return &model.Funnel{
+		ID:        strconv.FormatInt(value.ID, 10),
+		Name:      value.Name,
+		Steps:     steps,
+		CreatedAt: value.CreatedAt,
+		UpdatedAt: value.UpdatedAt,
+	}
+}
+
+func buildGraphQLFunnelStep(step funnel.Step) *model.FunnelStep {
+	stepType := model.FunnelStepTypePage
+	if step.Type == funnel.StepTypeEvent {
+		stepType = model.FunnelStepTypeEvent
+	}
+	return &model.FunnelStep{Type: stepType, Value: step.Value}
+}
+
+func parseFunnelInput(input model.FunnelInput) (funnel.Input, error) {
+	steps := make([]funnel.Step, 0, len(input.Steps))
+	for _, step := range input.Steps {
+		if step == nil {
+			return funnel.Input{}, badUserInput("invalid funnel step")
+		}
+		var stepType funnel.StepType
+		switch step.Type {
+		case model.FunnelStepTypePage:
+			stepType = funnel.StepTypePage
+		case model.FunnelStepTypeEvent:
+			stepType = funnel.StepTypeEvent
+		default:
+			return funnel.Input{}, badUserInput("invalid funnel step type")
+		}
+		steps = append(steps, funnel.Step{Type: stepType, Value: step.Value})
+	}
+	return funnel.Input{Name: input.Name, Steps: steps}, nil
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+// CreateGoal is the resolver for the createGoal field.
+func (r *mutationResolver) CreateGoal(ctx context.Context, siteID string, input model.GoalInput) (*model.Goal, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleEditor); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	goalInput, err := parseGoalInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.GoalService.Create(ctx, id, goalInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	return buildGraphQLGoal(created), nil
+}
+
+// UpdateGoal is the resolver for the updateGoal field.
+func (r *mutationResolver) UpdateGoal(ctx context.Context, siteID string, id string, input model.GoalInput) (*model.Goal, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	parsedSiteID, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+	goalID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid goal ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, parsedSiteID, site.RoleEditor); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	goalInput, err := parseGoalInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.GoalService.Update(ctx, parsedSiteID, goalID, goalInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	return buildGraphQLGoal(updated), nil
+}
+
+// DeleteGoal is the resolver for the deleteGoal field.
+func (r *mutationResolver) DeleteGoal(ctx context.Context, siteID string, id string) (bool, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return false, unauthenticated()
+	}
+
+	parsedSiteID, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid site ID")
+	}
+	goalID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid goal ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, parsedSiteID, site.RoleEditor); err != nil {
+		return false, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	if err := r.GoalService.Delete(ctx, parsedSiteID, goalID); err != nil {
+		return false, fmt.Errorf("failed to delete goal: %w", err)
+	}
+
+	return true, nil
+}
+
+// Goals is the resolver for the goals field.
+func (r *queryResolver) Goals(ctx context.Context, siteID string, paging model.PagingInput) ([]*model.Goal, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	if err := r.requireSiteRole(ctx, claims, id, site.RoleViewer); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	limit, offset := normalizePaging(paging)
+	goals, err := r.GoalService.List(ctx, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	result := make([]*model.Goal, 0, len(goals))
+	for _, value := range goals {
+		result = append(result, buildGraphQLGoal(value))
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/goal"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+func buildGraphQLGoal(value *goal.Goal) *model.Goal {
+	return &model.Goal{
+		ID:        strconv.FormatInt(value.ID, 10),
+		Name:      value.Name,
+		Type:      graphQLGoalType(value.Type),
+		Value:     value.Value,
+		CreatedAt: value.CreatedAt,
+		UpdatedAt: value.UpdatedAt,
+	}
+}
+
+func graphQLGoalType(value goal.Type) model.GoalType {
+	if value == goal.TypeEvent {
+		return model.GoalTypeEvent
+	}
+	return model.GoalTypePage
+}
+
+func parseGoalInput(input model.GoalInput) (goal.Input, error) {
+	var goalType goal.Type
+	switch input.Type {
+	case model.GoalTypePage:
+		goalType = goal.TypePage
+	case model.GoalTypeEvent:
+		goalType = goal.TypeEvent
+	default:
+		return goal.Input{}, badUserInput("invalid goal type")
+	}
+	return goal.Input{Name: input.Name, Type: goalType, Value: input.Value}, nil
+}
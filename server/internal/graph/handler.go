@@ -4,14 +4,19 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 type contextKey string
 
 const (
 	responseWriterKey contextKey = "response_writer"
+	userAgentKey      contextKey = "user_agent"
 )
 
 func Handler(resolver *Resolver, maxBodyBytes int64, maxComplexity int) http.HandlerFunc {
@@ -26,15 +31,33 @@ func Handler(resolver *Resolver, maxBodyBytes int64, maxComplexity int) http.Han
 	}))
 	srv.Use(extension.FixedComplexityLimit(maxComplexity))
 	srv.SetErrorPresenter(presentError)
+	srv.AroundOperations(rejectAPITokenWrites)
+	srv.AroundRootFields(restrictSharedRootFields)
+	srv.AroundFields(restrictSharedBreakdowns)
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
 
 		ctx := context.WithValue(r.Context(), responseWriterKey, w)
+		ctx = context.WithValue(ctx, userAgentKey, r.UserAgent())
 		srv.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
 
+// rejectAPITokenWrites keeps API token requests read-only by refusing anything but queries.
+func rejectAPITokenWrites(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil || !claims.IsAPIToken() {
+		return next(ctx)
+	}
+	if operation := graphql.GetOperationContext(ctx).Operation; operation != nil && operation.Operation == ast.Query {
+		return next(ctx)
+	}
+	return graphql.OneShot(&graphql.Response{
+		Errors: gqlerror.List{presentError(ctx, forbidden("API tokens are read-only"))},
+	})
+}
+
 func GetResponseWriter(ctx context.Context) http.ResponseWriter {
 	w, ok := ctx.Value(responseWriterKey).(http.ResponseWriter)
 	if !ok {
@@ -42,3 +65,9 @@ func GetResponseWriter(ctx context.Context) http.ResponseWriter {
 	}
 	return w
 }
+
+// getUserAgent returns the request User-Agent that labels sessions started by login.
+func getUserAgent(ctx context.Context) string {
+	userAgent, _ := ctx.Value(userAgentKey).(string)
+	return userAgent
+}
@@ -14,16 +14,33 @@ import (
 
 func convertToGraphQLUser(user *auth.User) *model.User {
 	return &model.User{
-		ID:        strconv.FormatInt(user.ID, 10),
-		Username:  user.Username,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
+		ID:               strconv.FormatInt(user.ID, 10),
+		Username:         user.Username,
+		Role:             user.Role,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt,
 	}
 }
 
-func parseDateRangeInput(input *model.DateRangeInput, maxRangeDays int) (time.Time, time.Time, error) {
-	now := time.Now()
-	defaultFrom := now.AddDate(0, 0, -30)
+func convertToGraphQLAuthSession(session *auth.Session, currentSessionID int64) *model.AuthSession {
+	return &model.AuthSession{
+		ID:         strconv.FormatInt(session.ID, 10),
+		UserAgent:  session.UserAgent,
+		Current:    session.ID == currentSessionID,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// parseDateRangeInput defaults to the last 30 days, starting at local midnight in loc
+// so the first daily bucket is complete.
+func parseDateRangeInput(input *model.DateRangeInput, loc *time.Location, maxRangeDays int) (time.Time, time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	defaultFrom := time.Date(now.Year(), now.Month(), now.Day()-30, 0, 0, 0, 0, loc)
 	defaultTo := now
 
 	if input == nil {
@@ -61,7 +78,7 @@ func parseFilterInput(input *model.FilterInput, limits DashboardLimits) (analyti
 		return analytics.Filter{}, nil
 	}
 
-	if err := validateStringFilters(limits, input.Referrer, input.Browser, input.Device, input.Os, input.Page, input.Country, input.EventName, input.EventPath, input.EventDefinitionID); err != nil {
+	if err := validateStringFilters(limits, input.Referrer, input.ReferrerDomain, input.Channel, input.Browser, input.Device, input.Os, input.ScreenSize, input.Page, input.Country, input.EventName, input.EventPath, input.EventDefinitionID, input.UtmSource, input.UtmMedium, input.UtmCampaign, input.UtmTerm, input.UtmContent); err != nil {
 		return analytics.Filter{}, err
 	}
 	if limits.MaxFilterValues > 0 && len(input.EventType) > limits.MaxFilterValues {
@@ -72,28 +89,40 @@ func parseFilterInput(input *model.FilterInput, limits DashboardLimits) (analyti
 		return analytics.Filter{}, err
 	}
 
-	referrers := make([]string, 0, len(input.Referrer))
-	for _, referrer := range input.Referrer {
-		if referrer == "(direct)" {
-			referrer = ""
-		}
-		referrers = append(referrers, referrer)
-	}
-
 	return analytics.Filter{
-		Referrer:           referrers,
+		Referrer:           parseReferrerFilter(input.Referrer),
+		ReferrerDomain:     parseReferrerFilter(input.ReferrerDomain),
+		Channel:            input.Channel,
 		Browser:            input.Browser,
 		Device:             input.Device,
 		OS:                 input.Os,
+		ScreenSize:         input.ScreenSize,
 		Page:               input.Page,
 		Country:            input.Country,
 		EventTypes:         parseEventTypes(input.EventType),
 		EventName:          input.EventName,
 		EventPath:          input.EventPath,
 		EventDefinitionIDs: eventDefinitionIDs,
+		UTMSource:          input.UtmSource,
+		UTMMedium:          input.UtmMedium,
+		UTMCampaign:        input.UtmCampaign,
+		UTMTerm:            input.UtmTerm,
+		UTMContent:         input.UtmContent,
 	}, nil
 }
 
+// parseReferrerFilter maps the "(direct)" label shown in breakdowns back to an empty referrer.
+func parseReferrerFilter(values []string) []string {
+	referrers := make([]string, 0, len(values))
+	for _, referrer := range values {
+		if referrer == "(direct)" {
+			referrer = ""
+		}
+		referrers = append(referrers, referrer)
+	}
+	return referrers
+}
+
 func validateStringFilters(limits DashboardLimits, groups ...[]string) error {
 	for _, values := range groups {
 		if limits.MaxFilterValues > 0 && len(values) > limits.MaxFilterValues {
@@ -113,15 +142,23 @@ func validateStringFilters(limits DashboardLimits, groups ...[]string) error {
 
 func isFilterEmpty(filter analytics.Filter) bool {
 	return len(filter.Referrer) == 0 &&
+		len(filter.ReferrerDomain) == 0 &&
+		len(filter.Channel) == 0 &&
 		len(filter.Browser) == 0 &&
 		len(filter.Device) == 0 &&
 		len(filter.OS) == 0 &&
+		len(filter.ScreenSize) == 0 &&
 		len(filter.Page) == 0 &&
 		len(filter.Country) == 0 &&
 		len(filter.EventTypes) == 0 &&
 		len(filter.EventName) == 0 &&
 		len(filter.EventPath) == 0 &&
-		len(filter.EventDefinitionIDs) == 0
+		len(filter.EventDefinitionIDs) == 0 &&
+		len(filter.UTMSource) == 0 &&
+		len(filter.UTMMedium) == 0 &&
+		len(filter.UTMCampaign) == 0 &&
+		len(filter.UTMTerm) == 0 &&
+		len(filter.UTMContent) == 0
 }
 
 func convertToGraphQLEvent(e *analytics.Event) *model.Event {
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/ingestkey"
+)
+
+// CreateIngestKey is the resolver for the createIngestKey field.
+func (r *mutationResolver) CreateIngestKey(ctx context.Context, siteID string, name string) (*model.CreatedIngestKey, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	key, secret, err := r.IngestKeyService.Create(ctx, ingestkey.CreateInput{
+		UserID: claims.UserID,
+		SiteID: id,
+		Name:   name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingestion key: %w", err)
+	}
+
+	return &model.CreatedIngestKey{
+		Key:    buildGraphQLIngestKey(key),
+		Secret: secret,
+	}, nil
+}
+
+// RevokeIngestKey is the resolver for the revokeIngestKey field.
+func (r *mutationResolver) RevokeIngestKey(ctx context.Context, id string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	keyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid ingestion key ID")
+	}
+
+	if err := r.IngestKeyService.Revoke(ctx, claims.UserID, keyID); err != nil {
+		return false, fmt.Errorf("failed to revoke ingestion key: %w", err)
+	}
+
+	return true, nil
+}
+
+// IngestKeys is the resolver for the ingestKeys field.
+func (r *queryResolver) IngestKeys(ctx context.Context, siteID string) ([]*model.IngestKey, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	keys, err := r.IngestKeyService.List(ctx, claims.UserID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingestion keys: %w", err)
+	}
+
+	result := make([]*model.IngestKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, buildGraphQLIngestKey(key))
+	}
+	return result, nil
+}
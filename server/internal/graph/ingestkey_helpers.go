@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/ingestkey"
+)
+
+func buildGraphQLIngestKey(key *ingestkey.Key) *model.IngestKey {
+	return &model.IngestKey{
+		ID:         strconv.FormatInt(key.ID, 10),
+		SiteID:     strconv.FormatInt(key.SiteID, 10),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
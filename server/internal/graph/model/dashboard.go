@@ -12,9 +12,11 @@ type DashboardStats struct {
 	Sessions    int
 	BounceRate  float64
 	AvgDuration float64
+	Comparison  *DashboardComparison
 
-	SiteID int64
-	From   time.Time
-	To     time.Time
-	Filter analytics.Filter
+	SiteID   int64
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+	Filter   analytics.Filter
 }
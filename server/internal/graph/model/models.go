@@ -3,11 +3,12 @@ package model
 import "time"
 
 type User struct {
-	ID        string    `json:"id"`
-	Username  string    `json:"username"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"createdAt"`
-	Sites     []*Site   `json:"sites,omitempty"`
+	ID               string    `json:"id"`
+	Username         string    `json:"username"`
+	Role             string    `json:"role"`
+	TwoFactorEnabled bool      `json:"twoFactorEnabled"`
+	CreatedAt        time.Time `json:"createdAt"`
+	Sites            []*Site   `json:"sites,omitempty"`
 }
 
 type Site struct {
@@ -16,14 +17,18 @@ type Site struct {
 	Name             string    `json:"name"`
 	PublicKey        string    `json:"publicKey"`
 	TrackCountry     bool      `json:"trackCountry"`
+	Timezone         string    `json:"timezone"`
 	BlockedIPs       []string  `json:"blockedIPs"`
 	BlockedCountries []string  `json:"blockedCountries"`
+	Role             SiteRole  `json:"role"`
 	CreatedAt        time.Time `json:"createdAt"`
 }
 
 type AuthPayload struct {
 	User *User `json:"user"`
 	// Tokens are set as HttpOnly cookies, not returned in response.
+	TwoFactorRequired bool    `json:"twoFactorRequired"`
+	TwoFactorToken    *string `json:"twoFactorToken,omitempty"`
 }
 
 type PageStats struct {
@@ -52,6 +57,11 @@ type OperatingSystemStats struct {
 	Visitors int    `json:"visitors"`
 }
 
+type ScreenSizeStats struct {
+	ScreenSize string `json:"screenSize"`
+	Visitors   int    `json:"visitors"`
+}
+
 type Country struct {
 	Code      string  `json:"code"`
 	NameCache *string `json:"-"`
@@ -67,6 +77,8 @@ type DailyStats struct {
 	Visitors  int       `json:"visitors"`
 	PageViews int       `json:"pageViews"`
 	Sessions  int       `json:"sessions"`
+	// Comparison is set only when the dashboard was asked for a comparison range.
+	Comparison *DailyStats `json:"comparison"`
 }
 
 type RealtimeStats struct {
@@ -85,13 +97,15 @@ type LoginInput struct {
 }
 
 type CreateSiteInput struct {
-	Domains []string `json:"domains"`
-	Name    string   `json:"name"`
+	Domains  []string `json:"domains"`
+	Name     string   `json:"name"`
+	Timezone *string  `json:"timezone,omitempty"`
 }
 
 type UpdateSiteInput struct {
 	Name             string   `json:"name"`
 	TrackCountry     *bool    `json:"trackCountry,omitempty"`
+	Timezone         *string  `json:"timezone,omitempty"`
 	Domains          []string `json:"domains,omitempty"`
 	BlockedIPs       []string `json:"blockedIPs,omitempty"`
 	BlockedCountries []string `json:"blockedCountries,omitempty"`
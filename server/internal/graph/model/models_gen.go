@@ -10,12 +10,127 @@ import (
 	"time"
 )
 
+// Read-only credential for scripts, sent as an Authorization: Bearer header
+type APIToken struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Leading characters of the secret, for identification
+	Prefix string `json:"prefix"`
+	// Sites the token may read; null means every site the owner can access
+	SiteIds    []string   `json:"siteIds,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
 type ActivePageStats struct {
 	Path string `json:"path"`
 	// Number of visitors currently viewing this page
 	Visitors int `json:"visitors"`
 }
 
+type AuditChange struct {
+	Field string `json:"field"`
+	// JSON-encoded value before the change; null when the field did not exist
+	Before *string `json:"before,omitempty"`
+	// JSON-encoded value after the change; null when the field was removed
+	After *string `json:"after,omitempty"`
+}
+
+// One recorded administrative change or login
+type AuditEntry struct {
+	ID string `json:"id"`
+	// Null for changes made by the server itself or synced from an identity provider
+	ActorID *string `json:"actorId,omitempty"`
+	// Username of the actor when the entry was recorded
+	ActorName    *string        `json:"actorName,omitempty"`
+	Action       AuditAction    `json:"action"`
+	SiteID       *string        `json:"siteId,omitempty"`
+	TargetUserID *string        `json:"targetUserId,omitempty"`
+	Changes      []*AuditChange `json:"changes"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+// A signed-in browser session backed by a rotating refresh token
+type AuthSession struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"userAgent"`
+	// True for the session making this request
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type ChannelStats struct {
+	Channel  string `json:"channel"`
+	Visitors int    `json:"visitors"`
+}
+
+type ComparisonInput struct {
+	Mode ComparisonMode `json:"mode"`
+	// Required when mode is CUSTOM
+	DateRange *DateRangeInput `json:"dateRange,omitempty"`
+}
+
+type CreateAPITokenInput struct {
+	Name string `json:"name"`
+	// Limits the token to these sites; omit for every owned site
+	SiteIds []string `json:"siteIds,omitempty"`
+}
+
+type CreateShareLinkInput struct {
+	Name string `json:"name"`
+	// Requires visitors to enter this password before the dashboard loads
+	Password *string `json:"password,omitempty"`
+	// Limits the link to these breakdowns; omit to expose every breakdown
+	Breakdowns []ShareBreakdown `json:"breakdowns,omitempty"`
+}
+
+type CreateUserInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// admin or user, defaults to user
+	Role *string `json:"role,omitempty"`
+}
+
+type CreatedAPIToken struct {
+	Token *APIToken `json:"token"`
+	// Returned only once; the server keeps just a hash
+	Secret string `json:"secret"`
+}
+
+type CreatedIngestKey struct {
+	Key *IngestKey `json:"key"`
+	// Returned only once; the server keeps just a hash
+	Secret string `json:"secret"`
+}
+
+type CreatedShareLink struct {
+	Link *ShareLink `json:"link"`
+	// Returned only once; the server keeps just a hash
+	Secret string `json:"secret"`
+}
+
+type DashboardComparison struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Visitors    int       `json:"visitors"`
+	PageViews   int       `json:"pageViews"`
+	Sessions    int       `json:"sessions"`
+	BounceRate  float64   `json:"bounceRate"`
+	AvgDuration float64   `json:"avgDuration"`
+	// Change from the comparison range to the selected range
+	Deltas *OverviewDeltas `json:"deltas"`
+}
+
+type EntryPageStats struct {
+	Path     string `json:"path"`
+	Sessions int    `json:"sessions"`
+	Visitors int    `json:"visitors"`
+	// Percentage of sessions starting here that viewed a single page
+	BounceRate float64 `json:"bounceRate"`
+}
+
 type EventCount struct {
 	Event *Event `json:"event"`
 	Count int    `json:"count"`
@@ -26,15 +141,30 @@ type EventCountsResult struct {
 	Total int           `json:"total"`
 }
 
+type ExitPageStats struct {
+	Path string `json:"path"`
+	// Sessions that ended on this page
+	Exits    int `json:"exits"`
+	Visitors int `json:"visitors"`
+	// Exits as a percentage of this page's views
+	ExitRate float64 `json:"exitRate"`
+}
+
 type FilterInput struct {
 	// Filter by specific referrer
 	Referrer []string `json:"referrer,omitempty"`
+	// Filter by normalized referrer host
+	ReferrerDomain []string `json:"referrerDomain,omitempty"`
+	// Filter by acquisition channel (direct, email, paid, referral, search, social)
+	Channel []string `json:"channel,omitempty"`
 	// Filter by browser type
 	Browser []string `json:"browser,omitempty"`
 	// Filter by device type (desktop, mobile, tablet, smart-tv, console, watch)
 	Device []string `json:"device,omitempty"`
 	// Filter by operating system
 	Os []string `json:"os,omitempty"`
+	// Filter by screen size (watch, xs, sm, md, lg, xl)
+	ScreenSize []string `json:"screenSize,omitempty"`
 	// Filter by page path
 	Page []string `json:"page,omitempty"`
 	// Filter by ISO country code
@@ -47,6 +177,58 @@ type FilterInput struct {
 	EventPath []string `json:"eventPath,omitempty"`
 	// Filter by event definition ID
 	EventDefinitionID []string `json:"eventDefinitionId,omitempty"`
+	// Filter by session utm_source
+	UtmSource []string `json:"utmSource,omitempty"`
+	// Filter by session utm_medium
+	UtmMedium []string `json:"utmMedium,omitempty"`
+	// Filter by session utm_campaign
+	UtmCampaign []string `json:"utmCampaign,omitempty"`
+	// Filter by session utm_term
+	UtmTerm []string `json:"utmTerm,omitempty"`
+	// Filter by session utm_content
+	UtmContent []string `json:"utmContent,omitempty"`
+}
+
+type Funnel struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Steps     []*FunnelStep `json:"steps"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+type FunnelInput struct {
+	Name string `json:"name"`
+	// Ordered steps, between 2 and 8
+	Steps []*FunnelStepInput `json:"steps"`
+}
+
+type FunnelReport struct {
+	Funnel *Funnel            `json:"funnel"`
+	Steps  []*FunnelStepStats `json:"steps"`
+}
+
+type FunnelStep struct {
+	Type FunnelStepType `json:"type"`
+	// Page path for PAGE steps or event definition name for EVENT steps
+	Value string `json:"value"`
+}
+
+type FunnelStepInput struct {
+	Type  FunnelStepType `json:"type"`
+	Value string         `json:"value"`
+}
+
+type FunnelStepStats struct {
+	Step *FunnelStep `json:"step"`
+	// Unique visitors who reached this step after completing the previous ones
+	Visitors int `json:"visitors"`
+	// Visitors as a percentage of those who entered the funnel
+	ConversionRate float64 `json:"conversionRate"`
+	// Visitors lost since the previous step
+	DropOff int `json:"dropOff"`
+	// Drop-off as a percentage of the previous step's visitors
+	DropOffRate float64 `json:"dropOffRate"`
 }
 
 type GeoIPStatus struct {
@@ -57,9 +239,72 @@ type GeoIPStatus struct {
 	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
+type Goal struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Type GoalType `json:"type"`
+	// Page path for PAGE goals or event definition name for EVENT goals
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type GoalInput struct {
+	Name  string   `json:"name"`
+	Type  GoalType `json:"type"`
+	Value string   `json:"value"`
+}
+
+type GoalStats struct {
+	Goal *Goal `json:"goal"`
+	// Unique visitors with at least one matching page view or event
+	Conversions int `json:"conversions"`
+	// Conversions as a percentage of unique visitors
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// Secret key that lets a trusted backend submit page views and events for one site to
+// POST /api/ingest. Backends send it as a bearer token; it is unrelated to the public site key.
+type IngestKey struct {
+	ID     string `json:"id"`
+	SiteID string `json:"siteId"`
+	Name   string `json:"name"`
+	// Leading characters of the secret, for identification
+	Prefix     string     `json:"prefix"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type MetricDelta struct {
+	// Current value minus comparison value
+	Change float64 `json:"change"`
+	// Relative change in percent; null when the comparison value is zero
+	PercentChange *float64 `json:"percentChange,omitempty"`
+}
+
 type Mutation struct {
 }
 
+type OverviewDeltas struct {
+	Visitors  *MetricDelta `json:"visitors"`
+	PageViews *MetricDelta `json:"pageViews"`
+	Sessions  *MetricDelta `json:"sessions"`
+	// Change in percentage points
+	BounceRate  *MetricDelta `json:"bounceRate"`
+	AvgDuration *MetricDelta `json:"avgDuration"`
+}
+
+type PagedAuditEntries struct {
+	Items []*AuditEntry `json:"items"`
+	Total int           `json:"total"`
+}
+
+type PagedChannelStats struct {
+	Items         []*ChannelStats `json:"items"`
+	Total         int             `json:"total"`
+	TotalVisitors int             `json:"totalVisitors"`
+}
+
 type PagedCountryStats struct {
 	Items         []*CountryStats `json:"items"`
 	Total         int             `json:"total"`
@@ -72,6 +317,22 @@ type PagedDeviceStats struct {
 	TotalVisitors int            `json:"totalVisitors"`
 }
 
+type PagedEntryPageStats struct {
+	Items []*EntryPageStats `json:"items"`
+	Total int               `json:"total"`
+}
+
+type PagedExitPageStats struct {
+	Items []*ExitPageStats `json:"items"`
+	Total int              `json:"total"`
+}
+
+type PagedGoalStats struct {
+	Items         []*GoalStats `json:"items"`
+	Total         int          `json:"total"`
+	TotalVisitors int          `json:"totalVisitors"`
+}
+
 type PagedOperatingSystemStats struct {
 	Items         []*OperatingSystemStats `json:"items"`
 	Total         int                     `json:"total"`
@@ -88,6 +349,17 @@ type PagedReferrerStats struct {
 	Total int              `json:"total"`
 }
 
+type PagedScreenSizeStats struct {
+	Items         []*ScreenSizeStats `json:"items"`
+	Total         int                `json:"total"`
+	TotalVisitors int                `json:"totalVisitors"`
+}
+
+type PagedUTMStats struct {
+	Items []*UTMStats `json:"items"`
+	Total int         `json:"total"`
+}
+
 type PagingInput struct {
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
@@ -102,6 +374,236 @@ type RegistrationStatus struct {
 	AllowRegistration bool `json:"allowRegistration"`
 }
 
+// Public read-only link to one site's dashboard. Visitors send the secret in an X-Share-Token header.
+type ShareLink struct {
+	ID     string `json:"id"`
+	SiteID string `json:"siteId"`
+	Name   string `json:"name"`
+	// Leading characters of the secret, for identification
+	Prefix            string `json:"prefix"`
+	PasswordProtected bool   `json:"passwordProtected"`
+	// Exposed breakdowns; null exposes every breakdown
+	Breakdowns []ShareBreakdown `json:"breakdowns,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
+
+// What an anonymous visitor may learn about a share link before opening it
+type SharedDashboard struct {
+	SiteID   string `json:"siteId"`
+	SiteName string `json:"siteName"`
+	// Call unlockSharedDashboard and send the returned key in an X-Share-Key header
+	PasswordRequired bool `json:"passwordRequired"`
+	// Exposed breakdowns; null exposes every breakdown
+	Breakdowns []ShareBreakdown `json:"breakdowns,omitempty"`
+}
+
+type SiteMember struct {
+	UserID    string    `json:"userId"`
+	Username  string    `json:"username"`
+	Role      SiteRole  `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TwoFactorEnrollment struct {
+	// Base32 secret for manual entry
+	Secret string `json:"secret"`
+	// otpauth:// URI to render as a QR code
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type UTMStats struct {
+	Value    string `json:"value"`
+	Sessions int    `json:"sessions"`
+	Visitors int    `json:"visitors"`
+	// Percentage of sessions that viewed a single page
+	BounceRate float64 `json:"bounceRate"`
+}
+
+type VerifyTwoFactorLoginInput struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	// Authenticator code or unused recovery code
+	Code string `json:"code"`
+}
+
+type AuditAction string
+
+const (
+	AuditActionSiteUpdate            AuditAction = "SITE_UPDATE"
+	AuditActionSiteDelete            AuditAction = "SITE_DELETE"
+	AuditActionSiteRegenerateKey     AuditAction = "SITE_REGENERATE_KEY"
+	AuditActionEventDefinitionUpsert AuditAction = "EVENT_DEFINITION_UPSERT"
+	AuditActionEventDefinitionDelete AuditAction = "EVENT_DEFINITION_DELETE"
+	AuditActionUserRoleChange        AuditAction = "USER_ROLE_CHANGE"
+	AuditActionUserLogin             AuditAction = "USER_LOGIN"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionSiteUpdate,
+	AuditActionSiteDelete,
+	AuditActionSiteRegenerateKey,
+	AuditActionEventDefinitionUpsert,
+	AuditActionEventDefinitionDelete,
+	AuditActionUserRoleChange,
+	AuditActionUserLogin,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionSiteUpdate, AuditActionSiteDelete, AuditActionSiteRegenerateKey, AuditActionEventDefinitionUpsert, AuditActionEventDefinitionDelete, AuditActionUserRoleChange, AuditActionUserLogin:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AuditAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AuditAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ComparisonMode string
+
+const (
+	// The range of equal length ending just before dateRange starts
+	ComparisonModePreviousPeriod ComparisonMode = "PREVIOUS_PERIOD"
+	// The range given in ComparisonInput.dateRange
+	ComparisonModeCustom ComparisonMode = "CUSTOM"
+)
+
+var AllComparisonMode = []ComparisonMode{
+	ComparisonModePreviousPeriod,
+	ComparisonModeCustom,
+}
+
+func (e ComparisonMode) IsValid() bool {
+	switch e {
+	case ComparisonModePreviousPeriod, ComparisonModeCustom:
+		return true
+	}
+	return false
+}
+
+func (e ComparisonMode) String() string {
+	return string(e)
+}
+
+func (e *ComparisonMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ComparisonMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ComparisonMode", str)
+	}
+	return nil
+}
+
+func (e ComparisonMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ComparisonMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ComparisonMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FunnelStepType string
+
+const (
+	// Matches page views whose path equals the step value
+	FunnelStepTypePage FunnelStepType = "PAGE"
+	// Matches predefined events whose name equals the step value
+	FunnelStepTypeEvent FunnelStepType = "EVENT"
+)
+
+var AllFunnelStepType = []FunnelStepType{
+	FunnelStepTypePage,
+	FunnelStepTypeEvent,
+}
+
+func (e FunnelStepType) IsValid() bool {
+	switch e {
+	case FunnelStepTypePage, FunnelStepTypeEvent:
+		return true
+	}
+	return false
+}
+
+func (e FunnelStepType) String() string {
+	return string(e)
+}
+
+func (e *FunnelStepType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FunnelStepType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FunnelStepType", str)
+	}
+	return nil
+}
+
+func (e FunnelStepType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FunnelStepType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FunnelStepType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type GeoIPState string
 
 const (
@@ -163,21 +665,216 @@ func (e GeoIPState) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type GoalType string
+
+const (
+	// Matches page views whose path equals the goal value
+	GoalTypePage GoalType = "PAGE"
+	// Matches predefined events whose name equals the goal value
+	GoalTypeEvent GoalType = "EVENT"
+)
+
+var AllGoalType = []GoalType{
+	GoalTypePage,
+	GoalTypeEvent,
+}
+
+func (e GoalType) IsValid() bool {
+	switch e {
+	case GoalTypePage, GoalTypeEvent:
+		return true
+	}
+	return false
+}
+
+func (e GoalType) String() string {
+	return string(e)
+}
+
+func (e *GoalType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GoalType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GoalType", str)
+	}
+	return nil
+}
+
+func (e GoalType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GoalType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GoalType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Dashboard section that a share link may expose. Overview totals and the visitor chart are always shown.
+type ShareBreakdown string
+
+const (
+	// Top, entry, and exit pages and realtime active pages
+	ShareBreakdownPages ShareBreakdown = "PAGES"
+	// Referrers, referrer domains, and channels
+	ShareBreakdownSources ShareBreakdown = "SOURCES"
+	// UTM parameters
+	ShareBreakdownUtm ShareBreakdown = "UTM"
+	// Browsers, devices, and operating systems
+	ShareBreakdownDevices   ShareBreakdown = "DEVICES"
+	ShareBreakdownCountries ShareBreakdown = "COUNTRIES"
+	ShareBreakdownGoals     ShareBreakdown = "GOALS"
+	ShareBreakdownFunnels   ShareBreakdown = "FUNNELS"
+	// The eventCounts query
+	ShareBreakdownEvents ShareBreakdown = "EVENTS"
+)
+
+var AllShareBreakdown = []ShareBreakdown{
+	ShareBreakdownPages,
+	ShareBreakdownSources,
+	ShareBreakdownUtm,
+	ShareBreakdownDevices,
+	ShareBreakdownCountries,
+	ShareBreakdownGoals,
+	ShareBreakdownFunnels,
+	ShareBreakdownEvents,
+}
+
+func (e ShareBreakdown) IsValid() bool {
+	switch e {
+	case ShareBreakdownPages, ShareBreakdownSources, ShareBreakdownUtm, ShareBreakdownDevices, ShareBreakdownCountries, ShareBreakdownGoals, ShareBreakdownFunnels, ShareBreakdownEvents:
+		return true
+	}
+	return false
+}
+
+func (e ShareBreakdown) String() string {
+	return string(e)
+}
+
+func (e *ShareBreakdown) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ShareBreakdown(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ShareBreakdown", str)
+	}
+	return nil
+}
+
+func (e ShareBreakdown) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ShareBreakdown) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ShareBreakdown) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SiteRole string
+
+const (
+	// Reads dashboards, goals, funnels, and event definitions
+	SiteRoleViewer SiteRole = "VIEWER"
+	// Also manages event definitions, goals, funnels, and site settings other than domains and timezone
+	SiteRoleEditor SiteRole = "EDITOR"
+	// Also changes domains and timezone, deletes the site, regenerates its key, and manages members
+	SiteRoleOwner SiteRole = "OWNER"
+)
+
+var AllSiteRole = []SiteRole{
+	SiteRoleViewer,
+	SiteRoleEditor,
+	SiteRoleOwner,
+}
+
+func (e SiteRole) IsValid() bool {
+	switch e {
+	case SiteRoleViewer, SiteRoleEditor, SiteRoleOwner:
+		return true
+	}
+	return false
+}
+
+func (e SiteRole) String() string {
+	return string(e)
+}
+
+func (e *SiteRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SiteRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SiteRole", str)
+	}
+	return nil
+}
+
+func (e SiteRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SiteRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SiteRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type TimeBucket string
 
 const (
 	TimeBucketDaily  TimeBucket = "DAILY"
 	TimeBucketHourly TimeBucket = "HOURLY"
+	// ISO weeks starting on Monday
+	TimeBucketWeekly  TimeBucket = "WEEKLY"
+	TimeBucketMonthly TimeBucket = "MONTHLY"
 )
 
 var AllTimeBucket = []TimeBucket{
 	TimeBucketDaily,
 	TimeBucketHourly,
+	TimeBucketWeekly,
+	TimeBucketMonthly,
 }
 
 func (e TimeBucket) IsValid() bool {
 	switch e {
-	case TimeBucketDaily, TimeBucketHourly:
+	case TimeBucketDaily, TimeBucketHourly, TimeBucketWeekly, TimeBucketMonthly:
 		return true
 	}
 	return false
@@ -5,17 +5,38 @@ import (
 	"net/http"
 
 	"github.com/lovely-eye/server/internal/analytics"
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/lovely-eye/server/internal/audit"
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/country"
 	"github.com/lovely-eye/server/internal/event"
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/goal"
+	"github.com/lovely-eye/server/internal/ingestkey"
+	"github.com/lovely-eye/server/internal/share"
 	"github.com/lovely-eye/server/internal/site"
 )
 
 type AuthService interface {
 	Register(ctx context.Context, input auth.RegisterInput) (*auth.User, *auth.Tokens, error)
-	Login(ctx context.Context, input auth.LoginInput) (*auth.User, *auth.Tokens, error)
+	Login(ctx context.Context, input auth.LoginInput) (*auth.LoginResult, error)
+	VerifyTwoFactorLogin(ctx context.Context, input auth.TwoFactorLoginInput) (*auth.User, *auth.Tokens, error)
+	ChangePassword(ctx context.Context, userID, sessionID int64, currentPassword, newPassword string) error
 	GetUserByID(ctx context.Context, id int64) (*auth.User, error)
 	RegistrationStatus(ctx context.Context) (*auth.RegistrationStatus, error)
+	ListSessions(ctx context.Context, userID int64) ([]*auth.Session, error)
+	RevokeSession(ctx context.Context, userID, id int64) error
+	RevokeAllSessions(ctx context.Context, userID int64) error
+	BeginTwoFactorEnrollment(ctx context.Context, userID int64) (*auth.TwoFactorEnrollment, error)
+	EnableTwoFactor(ctx context.Context, userID int64, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID int64, password string) error
+	ListUsers(ctx context.Context, actorID int64, limit, offset int) ([]*auth.User, error)
+	CreateUser(ctx context.Context, actorID int64, input auth.CreateUserInput) (*auth.User, error)
+	ResetPassword(ctx context.Context, actorID, id int64, password string) error
+	SetRole(ctx context.Context, actorID, id int64, role string) (*auth.User, error)
+	ResetTwoFactor(ctx context.Context, actorID, id int64) error
+	DeleteUser(ctx context.Context, actorID, id, transferSitesTo int64) error
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
 }
 
 type AuthCookies interface {
@@ -30,6 +51,12 @@ type Resolver struct {
 	AnalyticsService *analytics.Service
 	CountryService   *country.Service
 	EventDefService  *event.Service
+	GoalService      *goal.Service
+	FunnelService    *funnel.Service
+	APITokenService  *apitoken.Service
+	ShareService     *share.Service
+	IngestKeyService *ingestkey.Service
+	AuditService     *audit.Service
 	DashboardLimits  DashboardLimits
 }
 
@@ -47,6 +74,12 @@ func NewResolver(
 	analyticsService *analytics.Service,
 	countryService *country.Service,
 	eventDefService *event.Service,
+	goalService *goal.Service,
+	funnelService *funnel.Service,
+	apiTokenService *apitoken.Service,
+	shareService *share.Service,
+	ingestKeyService *ingestkey.Service,
+	auditService *audit.Service,
 	dashboardLimits DashboardLimits,
 ) *Resolver {
 	if dashboardLimits.MaxDailyRangeDays <= 0 {
@@ -68,6 +101,12 @@ func NewResolver(
 		AnalyticsService: analyticsService,
 		CountryService:   countryService,
 		EventDefService:  eventDefService,
+		GoalService:      goalService,
+		FunnelService:    funnelService,
+		APITokenService:  apiTokenService,
+		ShareService:     shareService,
+		IngestKeyService: ingestKeyService,
+		AuditService:     auditService,
 		DashboardLimits:  dashboardLimits,
 	}
 }
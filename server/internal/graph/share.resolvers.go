@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/share"
+)
+
+// CreateShareLink is the resolver for the createShareLink field.
+func (r *mutationResolver) CreateShareLink(ctx context.Context, siteID string, input model.CreateShareLinkInput) (*model.CreatedShareLink, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	createInput := share.CreateInput{
+		UserID:     claims.UserID,
+		SiteID:     id,
+		Name:       input.Name,
+		Breakdowns: parseShareBreakdowns(input.Breakdowns),
+	}
+	if input.Password != nil {
+		createInput.Password = *input.Password
+	}
+	link, secret, err := r.ShareService.Create(ctx, createInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return &model.CreatedShareLink{
+		Link:   buildGraphQLShareLink(link),
+		Secret: secret,
+	}, nil
+}
+
+// RevokeShareLink is the resolver for the revokeShareLink field.
+func (r *mutationResolver) RevokeShareLink(ctx context.Context, id string) (bool, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	linkID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid share link ID")
+	}
+
+	if err := r.ShareService.Revoke(ctx, claims.UserID, linkID); err != nil {
+		return false, fmt.Errorf("failed to revoke share link: %w", err)
+	}
+
+	return true, nil
+}
+
+// UnlockSharedDashboard is the resolver for the unlockSharedDashboard field.
+func (r *mutationResolver) UnlockSharedDashboard(ctx context.Context, token string, password string) (string, error) {
+	key, err := r.ShareService.Unlock(ctx, token, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to unlock shared dashboard: %w", err)
+	}
+	return key, nil
+}
+
+// ShareLinks is the resolver for the shareLinks field.
+func (r *queryResolver) ShareLinks(ctx context.Context, siteID string) ([]*model.ShareLink, error) {
+	claims, err := requireSessionClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+
+	links, err := r.ShareService.List(ctx, claims.UserID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+
+	result := make([]*model.ShareLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, buildGraphQLShareLink(link))
+	}
+	return result, nil
+}
+
+// SharedDashboard is the resolver for the sharedDashboard field.
+func (r *queryResolver) SharedDashboard(ctx context.Context, token string) (*model.SharedDashboard, error) {
+	credential, err := r.ShareService.Open(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shared dashboard: %w", err)
+	}
+
+	return &model.SharedDashboard{
+		SiteID:           strconv.FormatInt(credential.Link.SiteID, 10),
+		SiteName:         credential.SiteName,
+		PasswordRequired: credential.Link.PasswordProtected,
+		Breakdowns:       graphQLShareBreakdowns(credential.Link),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/graph/model"
+	"github.com/lovely-eye/server/internal/share"
+)
+
+// sharedRootFields are the operations a shared dashboard visitor may run.
+var sharedRootFields = map[string]bool{
+	"dashboard":       true,
+	"realtime":        true,
+	"eventCounts":     true,
+	"sharedDashboard": true,
+	"__typename":      true,
+	"__schema":        true,
+	"__type":          true,
+}
+
+// sharedBreakdownFields maps "Type.field" to the breakdown that must be exposed to resolve it.
+var sharedBreakdownFields = map[string]share.Breakdown{
+	"DashboardStats.topPages":         share.BreakdownPages,
+	"DashboardStats.entryPages":       share.BreakdownPages,
+	"DashboardStats.exitPages":        share.BreakdownPages,
+	"RealtimeStats.activePages":       share.BreakdownPages,
+	"DashboardStats.topReferrers":     share.BreakdownSources,
+	"DashboardStats.referrerDomains":  share.BreakdownSources,
+	"DashboardStats.channels":         share.BreakdownSources,
+	"DashboardStats.utmSources":       share.BreakdownUTM,
+	"DashboardStats.utmMediums":       share.BreakdownUTM,
+	"DashboardStats.utmCampaigns":     share.BreakdownUTM,
+	"DashboardStats.utmTerms":         share.BreakdownUTM,
+	"DashboardStats.utmContents":      share.BreakdownUTM,
+	"DashboardStats.browsers":         share.BreakdownDevices,
+	"DashboardStats.devices":          share.BreakdownDevices,
+	"DashboardStats.operatingSystems": share.BreakdownDevices,
+	"DashboardStats.screenSizes":      share.BreakdownDevices,
+	"DashboardStats.countries":        share.BreakdownCountries,
+	"DashboardStats.goals":            share.BreakdownGoals,
+	"DashboardStats.funnel":           share.BreakdownFunnels,
+	"Query.eventCounts":               share.BreakdownEvents,
+}
+
+// restrictSharedRootFields limits shared dashboard visitors to the read-only dashboard queries of
+// their site, so they cannot reach settings, other sites, or any mutation.
+func restrictSharedRootFields(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil || !claims.IsShareLink() {
+		return next(ctx)
+	}
+	if field := graphql.GetRootFieldContext(ctx); field != nil && field.Object == "Query" && sharedRootFields[field.Field.Name] {
+		return next(ctx)
+	}
+	graphql.AddError(ctx, forbidden("shared dashboards are read-only"))
+	return graphql.Null
+}
+
+// restrictSharedBreakdowns hides the breakdowns that a share link does not expose.
+func restrictSharedBreakdowns(ctx context.Context, next graphql.Resolver) (any, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil || !claims.IsShareLink() {
+		return next(ctx)
+	}
+	field := graphql.GetFieldContext(ctx)
+	if breakdown, ok := sharedBreakdownFields[field.Object+"."+field.Field.Name]; ok && !claims.CanViewBreakdown(string(breakdown)) {
+		return nil, forbidden("this breakdown is not shared")
+	}
+	return next(ctx)
+}
+
+// requireSharedFilter rejects filters on hidden breakdowns, which would otherwise reveal them
+// through the overview totals.
+func requireSharedFilter(claims *auth.Claims, filter *model.FilterInput) error {
+	if filter == nil || !claims.IsShareLink() {
+		return nil
+	}
+	used := map[share.Breakdown]bool{
+		share.BreakdownPages:     len(filter.Page) > 0,
+		share.BreakdownSources:   len(filter.Referrer) > 0 || len(filter.ReferrerDomain) > 0 || len(filter.Channel) > 0,
+		share.BreakdownUTM:       len(filter.UtmSource) > 0 || len(filter.UtmMedium) > 0 || len(filter.UtmCampaign) > 0 || len(filter.UtmTerm) > 0 || len(filter.UtmContent) > 0,
+		share.BreakdownDevices:   len(filter.Browser) > 0 || len(filter.Device) > 0 || len(filter.Os) > 0 || len(filter.ScreenSize) > 0,
+		share.BreakdownCountries: len(filter.Country) > 0,
+		share.BreakdownEvents:    len(filter.EventType) > 0 || len(filter.EventName) > 0 || len(filter.EventPath) > 0 || len(filter.EventDefinitionID) > 0,
+	}
+	for breakdown, inUse := range used {
+		if inUse && !claims.CanViewBreakdown(string(breakdown)) {
+			return forbidden("filters on hidden breakdowns are not allowed")
+		}
+	}
+	return nil
+}
+
+func buildGraphQLShareLink(link *share.Link) *model.ShareLink {
+	return &model.ShareLink{
+		ID:                strconv.FormatInt(link.ID, 10),
+		SiteID:            strconv.FormatInt(link.SiteID, 10),
+		Name:              link.Name,
+		Prefix:            link.Prefix,
+		PasswordProtected: link.PasswordProtected,
+		Breakdowns:        graphQLShareBreakdowns(link),
+		CreatedAt:         link.CreatedAt,
+	}
+}
+
+func graphQLShareBreakdowns(link *share.Link) []model.ShareBreakdown {
+	if !link.Restricted {
+		return nil
+	}
+	result := make([]model.ShareBreakdown, 0, len(link.Breakdowns))
+	for _, breakdown := range link.Breakdowns {
+		result = append(result, model.ShareBreakdown(strings.ToUpper(string(breakdown))))
+	}
+	return result
+}
+
+func parseShareBreakdowns(values []model.ShareBreakdown) []share.Breakdown {
+	if values == nil {
+		return nil
+	}
+	result := make([]share.Breakdown, 0, len(values))
+	for _, value := range values {
+		result = append(result, share.Breakdown(strings.ToLower(string(value))))
+	}
+	return result
+}
@@ -18,16 +18,21 @@ func (r *mutationResolver) CreateSite(ctx context.Context, input model.CreateSit
 		return nil, unauthenticated()
 	}
 
+	var timezone string
+	if input.Timezone != nil {
+		timezone = *input.Timezone
+	}
 	site, err := r.SiteService.Create(ctx, site.CreateSiteInput{
-		Domains: input.Domains,
-		Name:    input.Name,
-		UserID:  claims.UserID,
+		Domains:  input.Domains,
+		Name:     input.Name,
+		Timezone: timezone,
+		UserID:   claims.UserID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create site: %w", err)
 	}
 
-	return buildGraphQLSite(site), nil
+	return buildGraphQLSite(site, claims.UserID), nil
 }
 
 // UpdateSite is the resolver for the updateSite field.
@@ -45,6 +50,7 @@ func (r *mutationResolver) UpdateSite(ctx context.Context, id string, input mode
 	site, err := r.SiteService.Update(ctx, siteID, claims.UserID, site.UpdateSiteInput{
 		Name:             input.Name,
 		TrackCountry:     input.TrackCountry,
+		Timezone:         input.Timezone,
 		Domains:          input.Domains,
 		BlockedIPs:       input.BlockedIPs,
 		BlockedCountries: input.BlockedCountries,
@@ -57,7 +63,7 @@ func (r *mutationResolver) UpdateSite(ctx context.Context, id string, input mode
 		slog.WarnContext(ctx, "GeoIP synchronization failed after site update", "error", err)
 	}
 
-	return buildGraphQLSite(site), nil
+	return buildGraphQLSite(site, claims.UserID), nil
 }
 
 // DeleteSite is the resolver for the deleteSite field.
@@ -96,7 +102,82 @@ func (r *mutationResolver) RegenerateSiteKey(ctx context.Context, id string) (*m
 		return nil, fmt.Errorf("failed to regenerate site key: %w", err)
 	}
 
-	return buildGraphQLSite(site), nil
+	return buildGraphQLSite(site, claims.UserID), nil
+}
+
+// AddSiteMember is the resolver for the addSiteMember field.
+func (r *mutationResolver) AddSiteMember(ctx context.Context, siteID string, username string, role model.SiteRole) (*model.SiteMember, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+	siteRole, err := parseSiteRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.SiteService.AddMember(ctx, id, claims.UserID, username, siteRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add site member: %w", err)
+	}
+
+	return buildGraphQLSiteMember(member), nil
+}
+
+// UpdateSiteMemberRole is the resolver for the updateSiteMemberRole field.
+func (r *mutationResolver) UpdateSiteMemberRole(ctx context.Context, siteID string, userID string, role model.SiteRole) (*model.SiteMember, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+	memberUserID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid user ID")
+	}
+	siteRole, err := parseSiteRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.SiteService.UpdateMemberRole(ctx, id, claims.UserID, memberUserID, siteRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update site member: %w", err)
+	}
+
+	return buildGraphQLSiteMember(member), nil
+}
+
+// RemoveSiteMember is the resolver for the removeSiteMember field.
+func (r *mutationResolver) RemoveSiteMember(ctx context.Context, siteID string, userID string) (bool, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return false, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid site ID")
+	}
+	memberUserID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return false, badUserInput("invalid user ID")
+	}
+
+	if err := r.SiteService.RemoveMember(ctx, id, claims.UserID, memberUserID); err != nil {
+		return false, fmt.Errorf("failed to remove site member: %w", err)
+	}
+
+	return true, nil
 }
 
 // Sites is the resolver for the sites field.
@@ -107,14 +188,15 @@ func (r *queryResolver) Sites(ctx context.Context, paging model.PagingInput) ([]
 	}
 
 	limit, offset := normalizePaging(paging)
-	sites, err := r.SiteService.GetUserSites(ctx, claims.UserID, limit, offset)
+	// The API token site scope is applied before paging so that scoped tokens get full pages.
+	sites, err := r.SiteService.GetUserSites(ctx, claims.UserID, claims.SiteIDs, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sites: %w", err)
 	}
 
 	var result []*model.Site
 	for _, site := range sites {
-		result = append(result, buildGraphQLSite(site))
+		result = append(result, buildGraphQLSite(site, claims.UserID))
 	}
 
 	return result, nil
@@ -131,11 +213,41 @@ func (r *queryResolver) Site(ctx context.Context, id string) (*model.Site, error
 	if err != nil {
 		return nil, badUserInput("invalid site ID")
 	}
+	if !claims.CanAccessSite(siteID) {
+		return nil, fmt.Errorf("failed to get site: %w", site.ErrNotAuthorized)
+	}
 
 	site, err := r.SiteService.GetByID(ctx, siteID, claims.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get site: %w", err)
 	}
 
-	return buildGraphQLSite(site), nil
+	return buildGraphQLSite(site, claims.UserID), nil
+}
+
+// SiteMembers is the resolver for the siteMembers field.
+func (r *queryResolver) SiteMembers(ctx context.Context, siteID string) ([]*model.SiteMember, error) {
+	claims := auth.GetUserFromContext(ctx)
+	if claims == nil {
+		return nil, unauthenticated()
+	}
+
+	id, err := strconv.ParseInt(siteID, 10, 64)
+	if err != nil {
+		return nil, badUserInput("invalid site ID")
+	}
+	if !claims.CanAccessSite(id) {
+		return nil, fmt.Errorf("failed to get site members: %w", site.ErrNotAuthorized)
+	}
+
+	members, err := r.SiteService.ListMembers(ctx, id, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get site members: %w", err)
+	}
+
+	result := make([]*model.SiteMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, buildGraphQLSiteMember(member))
+	}
+	return result, nil
 }
@@ -1,21 +1,53 @@
 package graph
 
 import (
+	"context"
 	"strconv"
+	"time"
 
+	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/graph/model"
 	"github.com/lovely-eye/server/internal/site"
 )
 
-func buildGraphQLSite(site *site.Site) *model.Site {
+// requireSiteRole checks the caller's site role and, for API tokens, the token site scope.
+// A share link grants viewer access to its own site only.
+func (r *Resolver) requireSiteRole(ctx context.Context, claims *auth.Claims, siteID int64, role site.Role) error {
+	if !claims.CanAccessSite(siteID) {
+		return site.ErrNotAuthorized
+	}
+	if claims.IsShareLink() {
+		if role != site.RoleViewer {
+			return site.ErrNotAuthorized
+		}
+		_, err := r.SiteService.Location(ctx, siteID)
+		return err
+	}
+	return r.SiteService.RequireRole(ctx, siteID, claims.UserID, role)
+}
+
+// requireSiteLocation checks viewer access like requireSiteRole and returns the site time zone.
+func (r *Resolver) requireSiteLocation(ctx context.Context, claims *auth.Claims, siteID int64) (*time.Location, error) {
+	if !claims.CanAccessSite(siteID) {
+		return nil, site.ErrNotAuthorized
+	}
+	if claims.IsShareLink() {
+		return r.SiteService.Location(ctx, siteID)
+	}
+	return r.SiteService.RequireRoleLocation(ctx, siteID, claims.UserID, site.RoleViewer)
+}
+
+func buildGraphQLSite(site *site.Site, userID int64) *model.Site {
 	return &model.Site{
 		ID:               strconv.FormatInt(site.ID, 10),
 		Domains:          siteDomains(site),
 		Name:             site.Name,
 		PublicKey:        site.PublicKey,
 		TrackCountry:     site.TrackCountry,
+		Timezone:         site.Timezone,
 		BlockedIPs:       siteBlockedIPs(site),
 		BlockedCountries: siteBlockedCountries(site),
+		Role:             graphQLSiteRole(site.RoleFor(userID)),
 		CreatedAt:        site.CreatedAt,
 	}
 }
@@ -49,3 +81,36 @@ func siteBlockedCountries(site *site.Site) []string {
 	}
 	return countries
 }
+
+func buildGraphQLSiteMember(member *site.Member) *model.SiteMember {
+	return &model.SiteMember{
+		UserID:    strconv.FormatInt(member.UserID, 10),
+		Username:  member.Username,
+		Role:      graphQLSiteRole(member.Role),
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+func graphQLSiteRole(role site.Role) model.SiteRole {
+	switch role {
+	case site.RoleOwner:
+		return model.SiteRoleOwner
+	case site.RoleEditor:
+		return model.SiteRoleEditor
+	default:
+		return model.SiteRoleViewer
+	}
+}
+
+func parseSiteRole(role model.SiteRole) (site.Role, error) {
+	switch role {
+	case model.SiteRoleViewer:
+		return site.RoleViewer, nil
+	case model.SiteRoleEditor:
+		return site.RoleEditor, nil
+	case model.SiteRoleOwner:
+		return site.RoleOwner, nil
+	default:
+		return "", badUserInput("invalid site role")
+	}
+}
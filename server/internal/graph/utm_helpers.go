@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	analyticfeature "github.com/lovely-eye/server/internal/analytics"
+	"github.com/lovely-eye/server/internal/graph/model"
+)
+
+func (r *dashboardStatsResolver) utmStats(
+	ctx context.Context,
+	obj *model.DashboardStats,
+	paging model.PagingInput,
+	dimension analyticfeature.UTMDimension,
+) (*model.PagedUTMStats, error) {
+	limit, offset := normalizePaging(paging)
+	query := analyticfeature.Query{
+		SiteID: obj.SiteID,
+		From:   obj.From,
+		To:     obj.To,
+		Limit:  limit,
+		Offset: offset,
+		Filter: obj.Filter,
+	}
+	stats, total, err := r.AnalyticsService.GetUTMStatsWithFilterPaged(ctx, query, dimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utm %s stats: %w", dimension, err)
+	}
+
+	items := make([]*model.UTMStats, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, &model.UTMStats{
+			Value:      stat.Value,
+			Sessions:   stat.Sessions,
+			Visitors:   stat.Visitors,
+			BounceRate: stat.BounceRate,
+		})
+	}
+
+	return &model.PagedUTMStats{
+		Items: items,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovely-eye/server/internal/ingestkey"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ ingestkey.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) ListBySite(ctx context.Context, siteID int64) ([]*ingestkey.Key, error) {
+	var rows []*Key
+	if err := r.db.NewSelect().
+		Model(&rows).
+		Where("ik.site_id = ?", siteID).
+		Order("ik.created_at DESC", "ik.id DESC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get ingestion keys by site: %w", err)
+	}
+	result := make([]*ingestkey.Key, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, keyFromModel(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) CountBySite(ctx context.Context, siteID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*Key)(nil)).
+		Where("site_id = ?", siteID).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count ingestion keys: %w", err)
+	}
+	return count, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int64) (*ingestkey.Key, error) {
+	row := new(Key)
+	if err := r.db.NewSelect().Model(row).Where("ik.id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ingestkey.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get ingestion key: %w", err)
+	}
+	return keyFromModel(row), nil
+}
+
+func (r *Repository) Create(ctx context.Context, value *ingestkey.Key, secretHash string) error {
+	row := &Key{
+		SiteID:    value.SiteID,
+		Name:      value.Name,
+		Prefix:    value.Prefix,
+		TokenHash: secretHash,
+		CreatedAt: time.Now(),
+	}
+	if _, err := r.db.NewInsert().Model(row).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to insert ingestion key: %w", err)
+	}
+	value.ID = row.ID
+	value.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *Repository) GetByHash(ctx context.Context, secretHash string) (*ingestkey.Credential, error) {
+	row := new(Key)
+	if err := r.db.NewSelect().Model(row).Where("ik.token_hash = ?", secretHash).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ingestkey.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get ingestion key by hash: %w", err)
+	}
+
+	var publicKey string
+	err := r.db.NewSelect().
+		Table("sites").
+		Column("public_key").
+		Where("id = ?", row.SiteID).
+		Scan(ctx, &publicKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ingestkey.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get ingestion key site: %w", err)
+	}
+
+	return &ingestkey.Credential{
+		Key:           keyFromModel(row),
+		SitePublicKey: publicKey,
+	}, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.NewDelete().Model((*Key)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete ingestion key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete ingestion key rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ingestkey.ErrKeyNotFound
+	}
+	return nil
+}
+
+func (r *Repository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	if _, err := r.db.NewUpdate().
+		Model((*Key)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update ingestion key last use: %w", err)
+	}
+	return nil
+}
+
+func keyFromModel(row *Key) *ingestkey.Key {
+	value := &ingestkey.Key{
+		ID:        row.ID,
+		SiteID:    row.SiteID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		CreatedAt: row.CreatedAt,
+	}
+	if !row.LastUsedAt.IsZero() {
+		lastUsedAt := row.LastUsedAt
+		value.LastUsedAt = &lastUsedAt
+	}
+	return value
+}
@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/ingestkey"
+	"github.com/lovely-eye/server/internal/site"
+	"github.com/stretchr/testify/require"
+)
+
+type allowAllSites struct{}
+
+func (allowAllSites) RequireRole(context.Context, int64, int64, site.Role) error { return nil }
+
+func TestRepository_CreateAuthenticateRevokeKey(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	site := createTestSite(t, db)
+	service := ingestkey.NewService(New(db), allowAllSites{})
+	ctx := context.Background()
+
+	_, _, err := service.Create(ctx, ingestkey.CreateInput{UserID: site.UserID, SiteID: site.ID, Name: " "})
+	require.True(t, errors.Is(err, ingestkey.ErrInvalidKeyName))
+
+	key, secret, err := service.Create(ctx, ingestkey.CreateInput{UserID: site.UserID, SiteID: site.ID, Name: " Billing "})
+	require.NoError(t, err)
+	require.NotZero(t, key.ID)
+	require.Equal(t, "Billing", key.Name)
+	require.Equal(t, key.Prefix, secret[:len(key.Prefix)])
+	require.NotContains(t, secret, site.PublicKey)
+
+	credential, err := service.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, credential.Key.ID)
+	require.Equal(t, site.ID, credential.Key.SiteID)
+	require.Equal(t, site.PublicKey, credential.SitePublicKey)
+
+	keys, err := service.List(ctx, site.UserID, site.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].LastUsedAt)
+
+	_, err = service.Authenticate(ctx, site.PublicKey)
+	require.True(t, errors.Is(err, ingestkey.ErrInvalidKey))
+
+	require.NoError(t, service.Revoke(ctx, site.UserID, key.ID))
+	_, err = service.Authenticate(ctx, secret)
+	require.True(t, errors.Is(err, ingestkey.ErrInvalidKey))
+	err = service.Revoke(ctx, site.UserID, key.ID)
+	require.True(t, errors.Is(err, ingestkey.ErrKeyNotFound))
+}
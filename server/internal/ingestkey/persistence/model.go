@@ -0,0 +1,19 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Key struct {
+	bun.BaseModel `bun:"table:ingest_keys,alias:ik"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	SiteID     int64     `bun:"site_id,notnull"`
+	Name       string    `bun:"name,notnull,type:varchar(100)"`
+	Prefix     string    `bun:"prefix,notnull,type:varchar(16)"`
+	TokenHash  string    `bun:"token_hash,unique,notnull,type:varchar(64)"`
+	LastUsedAt time.Time `bun:"last_used_at,nullzero"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,170 @@
+package ingestkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	platformsecret "github.com/lovely-eye/server/internal/platform/secret"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+const (
+	maxKeyNameLength = 100
+	maxKeysPerSite   = 20
+
+	// secretPrefix tells ingestion keys apart from API tokens and share links in logs and secret scanners.
+	secretPrefix      = "lei_"
+	displayPrefixSize = len(secretPrefix) + 8
+
+	// lastUsedResolution bounds how often an active key rewrites its last-used time.
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrKeyNotFound    = errors.New("ingestion key not found")
+	ErrInvalidKey     = errors.New("invalid ingestion key")
+	ErrInvalidKeyName = errors.New("invalid ingestion key name")
+	ErrTooManyKeys    = errors.New("ingestion key limit of 20 per site reached")
+)
+
+// Key lets a trusted backend submit page views and events for one site. Only a hash of the
+// secret is stored, and the secret is unrelated to the site's public key.
+type Key struct {
+	ID         int64
+	SiteID     int64
+	Name       string
+	Prefix     string
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// Credential is a stored key together with the public key of the site it ingests into.
+type Credential struct {
+	Key           *Key
+	SitePublicKey string
+}
+
+type Store interface {
+	ListBySite(ctx context.Context, siteID int64) ([]*Key, error)
+	CountBySite(ctx context.Context, siteID int64) (int, error)
+	GetByID(ctx context.Context, id int64) (*Key, error)
+	Create(ctx context.Context, key *Key, secretHash string) error
+	GetByHash(ctx context.Context, secretHash string) (*Credential, error)
+	Delete(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// SiteAuthorizer confirms that a user owns a site before its ingestion keys are managed.
+type SiteAuthorizer interface {
+	RequireRole(ctx context.Context, id, userID int64, role site.Role) error
+}
+
+type Service struct {
+	store Store
+	sites SiteAuthorizer
+}
+
+func NewService(store Store, sites SiteAuthorizer) *Service {
+	return &Service{store: store, sites: sites}
+}
+
+type CreateInput struct {
+	UserID int64
+	SiteID int64
+	Name   string
+}
+
+// List returns a site's ingestion keys to its owners.
+func (s *Service) List(ctx context.Context, userID, siteID int64) ([]*Key, error) {
+	if err := s.sites.RequireRole(ctx, siteID, userID, site.RoleOwner); err != nil {
+		return nil, fmt.Errorf("failed to authorize ingestion keys: %w", err)
+	}
+	keys, err := s.store.ListBySite(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingestion keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Create stores a new key and returns it with its secret, which cannot be recovered later.
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Key, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxKeyNameLength {
+		return nil, "", ErrInvalidKeyName
+	}
+
+	if err := s.sites.RequireRole(ctx, input.SiteID, input.UserID, site.RoleOwner); err != nil {
+		return nil, "", fmt.Errorf("failed to authorize ingestion key: %w", err)
+	}
+	count, err := s.store.CountBySite(ctx, input.SiteID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to count ingestion keys: %w", err)
+	}
+	if count >= maxKeysPerSite {
+		return nil, "", ErrTooManyKeys
+	}
+
+	secret, err := platformsecret.Generate(secretPrefix)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate ingestion key secret: %w", err)
+	}
+	key := &Key{
+		SiteID: input.SiteID,
+		Name:   name,
+		Prefix: secret[:displayPrefixSize],
+	}
+	if err := s.store.Create(ctx, key, platformsecret.Hash(secret)); err != nil {
+		return nil, "", fmt.Errorf("failed to create ingestion key: %w", err)
+	}
+	return key, secret, nil
+}
+
+// Revoke deletes an ingestion key, which rejects the backend using it immediately.
+func (s *Service) Revoke(ctx context.Context, userID, id int64) error {
+	key, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return ErrKeyNotFound
+		}
+		return fmt.Errorf("failed to get ingestion key: %w", err)
+	}
+	if err := s.sites.RequireRole(ctx, key.SiteID, userID, site.RoleOwner); err != nil {
+		return fmt.Errorf("failed to authorize ingestion key: %w", err)
+	}
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return ErrKeyNotFound
+		}
+		return fmt.Errorf("failed to revoke ingestion key: %w", err)
+	}
+	return nil
+}
+
+// Authenticate resolves a presented secret into the key and the site it ingests into.
+func (s *Service) Authenticate(ctx context.Context, secret string) (*Credential, error) {
+	if !strings.HasPrefix(secret, secretPrefix) || len(secret) <= displayPrefixSize {
+		return nil, ErrInvalidKey
+	}
+
+	credential, err := s.store.GetByHash(ctx, platformsecret.Hash(secret))
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, fmt.Errorf("failed to get ingestion key: %w", err)
+	}
+
+	key := credential.Key
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.store.TouchLastUsed(ctx, key.ID, now); err != nil {
+			// Usage tracking is informational and must not reject a valid credential.
+			slog.WarnContext(ctx, "failed to record ingestion key use", "ingest_key_id", key.ID, "error", err)
+		}
+	}
+	return credential, nil
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -66,12 +68,36 @@ type AuthConfig struct {
 	RateLimitEnabled     bool
 	RateLimitAttempts    int
 	RateLimitWindow      time.Duration
+	OIDC                 OIDCConfig
+	// ProxyUserHeader enables reverse-proxy authentication: requests from TRUSTED_PROXY_CIDRS
+	// that carry this header are signed in as the named user.
+	ProxyUserHeader string
+}
+
+// OIDCConfig enables OpenID Connect single sign-on when IssuerURL is set.
+type OIDCConfig struct {
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	UsernameClaim string
+	// RoleClaim names a claim, such as groups, that grants admin when it contains one of
+	// AdminValues. Roles are left to Lovely Eye when it is empty.
+	RoleClaim         string
+	AdminValues       []string
+	LinkExistingUsers bool
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
 }
 
 type AnalyticsConfig struct {
 	IdentitySecret        string
 	MaxBodyBytes          int64
 	MaxPropertiesBytes    int
+	MaxBatchItems         int
 	MaxSinglePageDuration time.Duration
 	RateLimitEnabled      bool
 	RateLimitPerMinute    int
@@ -149,11 +175,24 @@ func Load() (Config, error) {
 			RateLimitEnabled:     reader.Bool("AUTH_RATE_LIMIT_ENABLED", true),
 			RateLimitAttempts:    reader.Int("AUTH_RATE_LIMIT_ATTEMPTS", 10),
 			RateLimitWindow:      reader.Duration("AUTH_RATE_LIMIT_WINDOW", 15*time.Minute),
+			OIDC: OIDCConfig{
+				IssuerURL:         getEnv("OIDC_ISSUER_URL", ""),
+				ClientID:          getEnv("OIDC_CLIENT_ID", ""),
+				ClientSecret:      getEnv("OIDC_CLIENT_SECRET", ""),
+				RedirectURL:       getEnv("OIDC_REDIRECT_URL", ""),
+				Scopes:            getEnvCSV("OIDC_SCOPES", "openid,profile,email"),
+				UsernameClaim:     getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
+				RoleClaim:         getEnv("OIDC_ROLE_CLAIM", ""),
+				AdminValues:       getEnvCSV("OIDC_ADMIN_VALUES", ""),
+				LinkExistingUsers: reader.Bool("OIDC_LINK_EXISTING_USERS", false),
+			},
+			ProxyUserHeader: strings.TrimSpace(getEnv("AUTH_PROXY_USER_HEADER", "")),
 		},
 		Analytics: AnalyticsConfig{
 			IdentitySecret:        identitySecret,
 			MaxBodyBytes:          int64(reader.Int("ANALYTICS_MAX_BODY_BYTES", 16*1024)),
 			MaxPropertiesBytes:    reader.Int("ANALYTICS_MAX_PROPERTIES_BYTES", 8*1024),
+			MaxBatchItems:         reader.Int("ANALYTICS_MAX_BATCH_ITEMS", 50),
 			MaxSinglePageDuration: reader.Duration("ANALYTICS_MAX_SINGLE_PAGE_DURATION", 4*time.Hour),
 			RateLimitEnabled:      reader.Bool("ANALYTICS_RATE_LIMIT_ENABLED", true),
 			RateLimitPerMinute:    reader.Int("ANALYTICS_RATE_LIMIT_PER_MINUTE", 120),
@@ -351,6 +390,7 @@ func (cfg Config) validate() error {
 	requirePositive("AUTH_RATE_LIMIT_WINDOW", int64(cfg.Auth.RateLimitWindow))
 	requirePositive("ANALYTICS_MAX_BODY_BYTES", cfg.Analytics.MaxBodyBytes)
 	requirePositive("ANALYTICS_MAX_PROPERTIES_BYTES", int64(cfg.Analytics.MaxPropertiesBytes))
+	requirePositive("ANALYTICS_MAX_BATCH_ITEMS", int64(cfg.Analytics.MaxBatchItems))
 	requirePositive("ANALYTICS_MAX_SINGLE_PAGE_DURATION", int64(cfg.Analytics.MaxSinglePageDuration))
 	requirePositive("ANALYTICS_RATE_LIMIT_PER_MINUTE", int64(cfg.Analytics.RateLimitPerMinute))
 	requirePositive("ANALYTICS_RATE_LIMIT_BURST", int64(cfg.Analytics.RateLimitBurst))
@@ -360,8 +400,29 @@ func (cfg Config) validate() error {
 	requirePositive("DASHBOARD_MAX_HOURLY_RANGE_DAYS", int64(cfg.Dashboard.MaxHourlyRangeDays))
 	requirePositive("DASHBOARD_MAX_FILTER_VALUES", int64(cfg.Dashboard.MaxFilterValues))
 	requirePositive("DASHBOARD_MAX_FILTER_STRING_LENGTH", int64(cfg.Dashboard.MaxFilterStringLength))
+	err = errors.Join(err, cfg.Auth.OIDC.validate())
 	if err != nil {
 		return fmt.Errorf("validate configuration: %w", err)
 	}
 	return nil
 }
+
+func (c OIDCConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	var err error
+	if c.ClientID == "" {
+		err = errors.Join(err, errors.New("OIDC_CLIENT_ID is required when OIDC_ISSUER_URL is set"))
+	}
+	if redirectURL, parseErr := url.Parse(c.RedirectURL); parseErr != nil || !redirectURL.IsAbs() {
+		err = errors.Join(err, errors.New("OIDC_REDIRECT_URL must be an absolute URL when OIDC_ISSUER_URL is set"))
+	}
+	if !slices.Contains(c.Scopes, "openid") {
+		err = errors.Join(err, errors.New("OIDC_SCOPES must include openid"))
+	}
+	if c.RoleClaim != "" && len(c.AdminValues) == 0 {
+		err = errors.Join(err, errors.New("OIDC_ADMIN_VALUES is required when OIDC_ROLE_CLAIM is set"))
+	}
+	return err
+}
@@ -102,6 +102,7 @@ func TestLoad_UsesAnalyticsHardeningDefaults(t *testing.T) {
 	require.Equal(t, 15*time.Minute, cfg.Auth.RateLimitWindow)
 	require.Equal(t, int64(16*1024), cfg.Analytics.MaxBodyBytes)
 	require.Equal(t, 8*1024, cfg.Analytics.MaxPropertiesBytes)
+	require.Equal(t, 50, cfg.Analytics.MaxBatchItems)
 	require.Equal(t, 4*time.Hour, cfg.Analytics.MaxSinglePageDuration)
 	require.True(t, cfg.Analytics.RateLimitEnabled)
 	require.Equal(t, 120, cfg.Analytics.RateLimitPerMinute)
@@ -120,6 +121,7 @@ func TestLoad_UsesAnalyticsHardeningOverrides(t *testing.T) {
 	t.Setenv("JWT_SECRET", strings.Repeat("j", 32))
 	t.Setenv("ANALYTICS_MAX_BODY_BYTES", "4096")
 	t.Setenv("ANALYTICS_MAX_PROPERTIES_BYTES", "1024")
+	t.Setenv("ANALYTICS_MAX_BATCH_ITEMS", "10")
 	t.Setenv("ANALYTICS_MAX_SINGLE_PAGE_DURATION", "2h")
 	t.Setenv("ANALYTICS_RATE_LIMIT_ENABLED", "false")
 	t.Setenv("ANALYTICS_RATE_LIMIT_PER_MINUTE", "12")
@@ -139,6 +141,7 @@ func TestLoad_UsesAnalyticsHardeningOverrides(t *testing.T) {
 
 	require.Equal(t, int64(4096), cfg.Analytics.MaxBodyBytes)
 	require.Equal(t, 1024, cfg.Analytics.MaxPropertiesBytes)
+	require.Equal(t, 10, cfg.Analytics.MaxBatchItems)
 	require.Equal(t, 2*time.Hour, cfg.Analytics.MaxSinglePageDuration)
 	require.False(t, cfg.Analytics.RateLimitEnabled)
 	require.Equal(t, 12, cfg.Analytics.RateLimitPerMinute)
@@ -192,6 +195,27 @@ func TestLoadRejectsInvalidEnvironmentValues(t *testing.T) {
 	}
 }
 
+func TestLoadValidatesOIDCOnlyWhenEnabled(t *testing.T) {
+	t.Setenv("JWT_SECRET", strings.Repeat("j", 32))
+	require.False(t, mustLoad(t).Auth.OIDC.Enabled())
+
+	t.Setenv("OIDC_ISSUER_URL", "https://id.example.com/realms/main")
+	t.Setenv("OIDC_ROLE_CLAIM", "groups")
+	_, err := Load()
+	require.ErrorContains(t, err, "OIDC_CLIENT_ID")
+	require.ErrorContains(t, err, "OIDC_REDIRECT_URL")
+	require.ErrorContains(t, err, "OIDC_ADMIN_VALUES")
+
+	t.Setenv("OIDC_CLIENT_ID", "lovely-eye")
+	t.Setenv("OIDC_REDIRECT_URL", "https://analytics.example.com/auth/oidc/callback")
+	t.Setenv("OIDC_ADMIN_VALUES", "admins, analytics-admins")
+	cfg := mustLoad(t)
+	require.True(t, cfg.Auth.OIDC.Enabled())
+	require.Equal(t, []string{"openid", "profile", "email"}, cfg.Auth.OIDC.Scopes)
+	require.Equal(t, "preferred_username", cfg.Auth.OIDC.UsernameClaim)
+	require.Equal(t, []string{"admins", "analytics-admins"}, cfg.Auth.OIDC.AdminValues)
+}
+
 func ptr[T any](value T) *T {
 	return &value
 }
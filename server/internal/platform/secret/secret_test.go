@@ -0,0 +1,25 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_PrefixesRandomSecrets(t *testing.T) {
+	first, err := Generate("le_")
+	require.NoError(t, err)
+	second, err := Generate("le_")
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(first, "le_"))
+	require.Len(t, first, len("le_")+43)
+	require.NotEqual(t, first, second)
+}
+
+func TestHash_IsStableAndDistinct(t *testing.T) {
+	require.Equal(t, Hash("le_abc"), Hash("le_abc"))
+	require.NotEqual(t, Hash("le_abc"), Hash("le_abd"))
+	require.Len(t, Hash("le_abc"), 64)
+}
@@ -40,6 +40,13 @@ var (
 	pathsProduct   = []string{"/", "/app", "/app/dashboard", "/settings", "/billing"}
 	pathsUpgrade   = []string{"/", "/pricing", "/checkout", "/billing"}
 	referrers      = []string{"https://google.com", "https://news.ycombinator.com", "https://github.com"}
+	// referrerChannels mirrors how the collector classifies the sample referrers.
+	referrerChannels = map[string]analyticspersistence.SessionChannel{
+		"":                             analyticspersistence.SessionChannelDirect,
+		"https://google.com":           analyticspersistence.SessionChannelSearch,
+		"https://news.ycombinator.com": analyticspersistence.SessionChannelSocial,
+		"https://github.com":           analyticspersistence.SessionChannelReferral,
+	}
 )
 
 type eventSeed struct {
@@ -232,23 +239,25 @@ func ensureEventDefinitions(ctx context.Context, db *bun.DB, siteID int64) ([]*e
 				{Key: "page", Type: "string"},
 			},
 		},
-		{
-			Name: "file_download",
-			Fields: []event.FieldInput{
-				{Key: "file", Type: "string", Required: true},
-				{Key: "success", Type: "bool"},
-			},
-		},
 	}
 
-	results := make([]*event.Definition, 0, len(definitions))
+	results := make([]*event.Definition, 0, len(definitions)+2)
 	for _, def := range definitions {
-		created, err := eventService.Upsert(ctx, siteID, def)
+		created, err := eventService.Upsert(ctx, siteID, 0, def)
 		if err != nil {
 			return nil, fmt.Errorf("upsert event definition %q: %w", def.Name, err)
 		}
 		results = append(results, created)
 	}
+	// Built-in definitions are reserved in the service, so they are stored the way collection creates them.
+	for _, name := range []string{event.OutboundLinkEvent, event.FileDownloadEvent} {
+		fields, _ := event.BuiltinFields(name)
+		created, err := eventRepo.Upsert(ctx, siteID, name, fields)
+		if err != nil {
+			return nil, fmt.Errorf("upsert built-in event definition %q: %w", name, err)
+		}
+		results = append(results, created)
+	}
 	return results, nil
 }
 
@@ -278,13 +287,14 @@ func seedData(ctx context.Context, db *bun.DB, siteID int64, defs []*event.Defin
 			events: []eventSeed{
 				{name: "video_play", path: "/docs", props: map[string]string{"video": "setup", "seconds": "120"}},
 				{name: "newsletter_subscribe", path: "/blog/launch", props: map[string]string{"source": "docs"}},
+				{name: event.OutboundLinkEvent, path: "/docs", props: map[string]string{"url": "https://github.com/lovely-eye/lovely-eye"}},
 			},
 		},
 		{
 			paths:    pathsProduct,
 			referrer: "",
 			events: []eventSeed{
-				{name: "file_download", path: "/app", props: map[string]string{"file": "report.pdf", "success": "true"}},
+				{name: event.FileDownloadEvent, path: "/app", props: map[string]string{"url": "http://localhost/files/report.pdf"}},
 			},
 		},
 		{
@@ -402,9 +412,13 @@ func applyPattern(
 		ExitDay:       exitUnix / 86400,
 		ExitPath:      paths[len(paths)-1],
 		Referrer:      pattern.referrer,
+		ReferrerHost:  strings.TrimPrefix(pattern.referrer, "https://"),
+		Channel:       referrerChannels[pattern.referrer],
 		UTMSource:     "",
 		UTMMedium:     "",
 		UTMCampaign:   "",
+		UTMTerm:       "",
+		UTMContent:    "",
 		Duration:      duration,
 		PageViewCount: len(paths),
 	}
@@ -510,10 +524,8 @@ func fallbackEventValue(field *event.Field) string {
 		return "Start Trial"
 	case "page":
 		return "/pricing"
-	case "file":
-		return "report.pdf"
-	case "success":
-		return "true"
+	case "url":
+		return "http://localhost/files/report.pdf"
 	default:
 		switch field.Type {
 		case event.FieldTypeInt:
@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lovely-eye/server/internal/share"
+	"github.com/uptrace/bun"
+)
+
+type Repository struct {
+	db *bun.DB
+}
+
+var _ share.Store = (*Repository)(nil)
+
+func New(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) ListBySite(ctx context.Context, siteID int64) ([]*share.Link, error) {
+	var rows []*Link
+	if err := r.db.NewSelect().
+		Model(&rows).
+		Where("sl.site_id = ?", siteID).
+		Order("sl.created_at DESC", "sl.id DESC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get share links by site: %w", err)
+	}
+	result := make([]*share.Link, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, linkFromModel(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) CountBySite(ctx context.Context, siteID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*Link)(nil)).
+		Where("site_id = ?", siteID).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count share links: %w", err)
+	}
+	return count, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int64) (*share.Link, error) {
+	row := new(Link)
+	if err := r.db.NewSelect().Model(row).Where("sl.id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, share.ErrLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return linkFromModel(row), nil
+}
+
+func (r *Repository) Create(ctx context.Context, value *share.Link, secretHash, passwordHash string) error {
+	breakdowns := make([]string, 0, len(value.Breakdowns))
+	for _, breakdown := range value.Breakdowns {
+		breakdowns = append(breakdowns, string(breakdown))
+	}
+	row := &Link{
+		SiteID:       value.SiteID,
+		Name:         value.Name,
+		Prefix:       value.Prefix,
+		TokenHash:    secretHash,
+		PasswordHash: passwordHash,
+		Restricted:   value.Restricted,
+		Breakdowns:   strings.Join(breakdowns, ","),
+		CreatedAt:    time.Now(),
+	}
+	if _, err := r.db.NewInsert().Model(row).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to insert share link: %w", err)
+	}
+	value.ID = row.ID
+	value.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *Repository) GetByHash(ctx context.Context, secretHash string) (*share.Credential, error) {
+	row := new(Link)
+	if err := r.db.NewSelect().Model(row).Where("sl.token_hash = ?", secretHash).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, share.ErrLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link by hash: %w", err)
+	}
+
+	var siteName string
+	err := r.db.NewSelect().
+		Table("sites").
+		Column("name").
+		Where("id = ?", row.SiteID).
+		Scan(ctx, &siteName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, share.ErrLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link site: %w", err)
+	}
+
+	return &share.Credential{
+		Link:         linkFromModel(row),
+		PasswordHash: row.PasswordHash,
+		SiteName:     siteName,
+	}, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.NewDelete().Model((*Link)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete share link: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete share link rows affected: %w", err)
+	}
+	if affected == 0 {
+		return share.ErrLinkNotFound
+	}
+	return nil
+}
+
+func linkFromModel(row *Link) *share.Link {
+	value := &share.Link{
+		ID:                row.ID,
+		SiteID:            row.SiteID,
+		Name:              row.Name,
+		Prefix:            row.Prefix,
+		PasswordProtected: row.PasswordHash != "",
+		Restricted:        row.Restricted,
+		CreatedAt:         row.CreatedAt,
+	}
+	if row.Restricted {
+		value.Breakdowns = []share.Breakdown{}
+		for _, breakdown := range strings.Split(row.Breakdowns, ",") {
+			if breakdown != "" {
+				value.Breakdowns = append(value.Breakdowns, share.Breakdown(breakdown))
+			}
+		}
+	}
+	return value
+}
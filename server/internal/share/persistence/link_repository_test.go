@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/share"
+	"github.com/lovely-eye/server/internal/site"
+	"github.com/stretchr/testify/require"
+)
+
+type allowAllSites struct{}
+
+func (allowAllSites) RequireRole(context.Context, int64, int64, site.Role) error { return nil }
+
+func TestRepository_CreateOpenRevokeLink(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	site := createTestSite(t, db)
+	service := share.NewService(New(db), allowAllSites{}, "secret")
+	ctx := context.Background()
+
+	link, secret, err := service.Create(ctx, share.CreateInput{
+		UserID:     site.UserID,
+		SiteID:     site.ID,
+		Name:       " Public ",
+		Breakdowns: []share.Breakdown{share.BreakdownPages, share.BreakdownCountries, share.BreakdownPages},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, link.ID)
+	require.Equal(t, "Public", link.Name)
+	require.Equal(t, []share.Breakdown{share.BreakdownPages, share.BreakdownCountries}, link.Breakdowns)
+	require.Equal(t, link.Prefix, secret[:len(link.Prefix)])
+
+	credential, err := service.Open(ctx, secret)
+	require.NoError(t, err)
+	require.Equal(t, "Share Test", credential.SiteName)
+	require.False(t, credential.Link.PasswordProtected)
+
+	claims, err := service.Authenticate(ctx, secret, "")
+	require.NoError(t, err)
+	require.Zero(t, claims.UserID)
+	require.Equal(t, link.ID, claims.ShareLinkID)
+	require.True(t, claims.CanAccessSite(site.ID))
+	require.False(t, claims.CanAccessSite(site.ID+1))
+	require.True(t, claims.CanViewBreakdown("pages"))
+	require.False(t, claims.CanViewBreakdown("sources"))
+
+	links, err := service.List(ctx, site.UserID, site.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.True(t, links[0].Restricted)
+	require.Equal(t, link.Breakdowns, links[0].Breakdowns)
+
+	require.NoError(t, service.Revoke(ctx, site.UserID, link.ID))
+	_, err = service.Authenticate(ctx, secret, "")
+	require.True(t, errors.Is(err, share.ErrInvalidLink))
+	err = service.Revoke(ctx, site.UserID, link.ID)
+	require.True(t, errors.Is(err, share.ErrLinkNotFound))
+}
+
+func TestRepository_ProtectedLinkNeedsUnlockKey(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	site := createTestSite(t, db)
+	service := share.NewService(New(db), allowAllSites{}, "secret")
+	ctx := context.Background()
+
+	_, _, err := service.Create(ctx, share.CreateInput{UserID: site.UserID, SiteID: site.ID, Name: "Short", Password: "short"})
+	require.True(t, errors.Is(err, share.ErrInvalidLinkPassword))
+
+	link, secret, err := service.Create(ctx, share.CreateInput{UserID: site.UserID, SiteID: site.ID, Name: "Team", Password: "correct horse"})
+	require.NoError(t, err)
+	require.True(t, link.PasswordProtected)
+	require.False(t, link.Restricted)
+
+	_, err = service.Authenticate(ctx, secret, "")
+	require.True(t, errors.Is(err, share.ErrWrongPassword))
+	_, err = service.Unlock(ctx, secret, "wrong horse")
+	require.True(t, errors.Is(err, share.ErrWrongPassword))
+
+	key, err := service.Unlock(ctx, secret, "correct horse")
+	require.NoError(t, err)
+	claims, err := service.Authenticate(ctx, secret, key)
+	require.NoError(t, err)
+	require.Nil(t, claims.Breakdowns)
+	require.True(t, claims.CanViewBreakdown("events"))
+}
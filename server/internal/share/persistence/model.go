@@ -0,0 +1,22 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Link struct {
+	bun.BaseModel `bun:"table:share_links,alias:sl"`
+
+	ID           int64  `bun:"id,pk,autoincrement"`
+	SiteID       int64  `bun:"site_id,notnull"`
+	Name         string `bun:"name,notnull,type:varchar(100)"`
+	Prefix       string `bun:"prefix,notnull,type:varchar(16)"`
+	TokenHash    string `bun:"token_hash,unique,notnull,type:varchar(64)"`
+	PasswordHash string `bun:"password_hash,nullzero,type:varchar(255)"`
+	Restricted   bool   `bun:"restricted,notnull,default:false"`
+	// Breakdowns is a comma-separated list that only applies when Restricted is set.
+	Breakdowns string    `bun:"breakdowns,notnull,type:varchar(255),default:''"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,290 @@
+package share
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	platformsecret "github.com/lovely-eye/server/internal/platform/secret"
+	"github.com/lovely-eye/server/internal/site"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	maxLinkNameLength = 100
+	maxLinksPerSite   = 20
+	minPasswordBytes  = 8
+	// maxPasswordBytes stays within bcrypt's input limit.
+	maxPasswordBytes = 72
+
+	// secretPrefix tells share links apart from API tokens in logs and secret scanners.
+	secretPrefix      = "les_"
+	displayPrefixSize = len(secretPrefix) + 8
+)
+
+var (
+	ErrLinkNotFound        = errors.New("share link not found")
+	ErrInvalidLink         = errors.New("invalid share link")
+	ErrInvalidLinkName     = errors.New("invalid share link name")
+	ErrInvalidLinkPassword = errors.New("share link password must be 8 to 72 bytes")
+	ErrWrongPassword       = errors.New("incorrect share link password")
+	ErrInvalidBreakdown    = errors.New("invalid share link breakdown")
+	ErrTooManyLinks        = errors.New("share link limit of 20 per site reached")
+)
+
+// Breakdown is a dashboard section that a share link may expose. The overview totals and the
+// visitor chart are always shown.
+type Breakdown string
+
+const (
+	BreakdownPages     Breakdown = "pages"
+	BreakdownSources   Breakdown = "sources"
+	BreakdownUTM       Breakdown = "utm"
+	BreakdownDevices   Breakdown = "devices"
+	BreakdownCountries Breakdown = "countries"
+	BreakdownGoals     Breakdown = "goals"
+	BreakdownFunnels   Breakdown = "funnels"
+	BreakdownEvents    Breakdown = "events"
+)
+
+var breakdowns = []Breakdown{
+	BreakdownPages,
+	BreakdownSources,
+	BreakdownUTM,
+	BreakdownDevices,
+	BreakdownCountries,
+	BreakdownGoals,
+	BreakdownFunnels,
+	BreakdownEvents,
+}
+
+// Link is a public read-only dashboard link for one site. Only a hash of the secret is stored.
+type Link struct {
+	ID                int64
+	SiteID            int64
+	Name              string
+	Prefix            string
+	PasswordProtected bool
+	// Restricted limits the link to Breakdowns; unrestricted links expose every breakdown.
+	Restricted bool
+	Breakdowns []Breakdown
+	CreatedAt  time.Time
+}
+
+// Allows reports whether the link exposes a breakdown.
+func (l *Link) Allows(breakdown Breakdown) bool {
+	return !l.Restricted || slices.Contains(l.Breakdowns, breakdown)
+}
+
+// Credential is a stored link together with what a visitor needs to open it.
+type Credential struct {
+	Link         *Link
+	PasswordHash string
+	SiteName     string
+}
+
+type Store interface {
+	ListBySite(ctx context.Context, siteID int64) ([]*Link, error)
+	CountBySite(ctx context.Context, siteID int64) (int, error)
+	GetByID(ctx context.Context, id int64) (*Link, error)
+	Create(ctx context.Context, link *Link, secretHash, passwordHash string) error
+	GetByHash(ctx context.Context, secretHash string) (*Credential, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// SiteAuthorizer confirms that a user owns a site before its dashboard is published.
+type SiteAuthorizer interface {
+	RequireRole(ctx context.Context, id, userID int64, role site.Role) error
+}
+
+type Service struct {
+	store Store
+	sites SiteAuthorizer
+	// unlockSecret signs the keys that visitors receive for password-protected links.
+	unlockSecret []byte
+}
+
+func NewService(store Store, sites SiteAuthorizer, unlockSecret string) *Service {
+	return &Service{store: store, sites: sites, unlockSecret: []byte(unlockSecret)}
+}
+
+type CreateInput struct {
+	UserID int64
+	SiteID int64
+	Name   string
+	// Password protects the link when set.
+	Password string
+	// Breakdowns limits the link to these breakdowns; nil exposes every breakdown.
+	Breakdowns []Breakdown
+}
+
+// List returns a site's share links to its owners.
+func (s *Service) List(ctx context.Context, userID, siteID int64) ([]*Link, error) {
+	if err := s.sites.RequireRole(ctx, siteID, userID, site.RoleOwner); err != nil {
+		return nil, fmt.Errorf("failed to authorize share links: %w", err)
+	}
+	links, err := s.store.ListBySite(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	return links, nil
+}
+
+// Create publishes a site dashboard and returns the link with its secret, which cannot be
+// recovered later.
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Link, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxLinkNameLength {
+		return nil, "", ErrInvalidLinkName
+	}
+	var passwordHash string
+	if input.Password != "" {
+		if len(input.Password) < minPasswordBytes || len(input.Password) > maxPasswordBytes {
+			return nil, "", ErrInvalidLinkPassword
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to hash share link password: %w", err)
+		}
+		passwordHash = string(hash)
+	}
+	var selected []Breakdown
+	if input.Breakdowns != nil {
+		for _, breakdown := range input.Breakdowns {
+			if !slices.Contains(breakdowns, breakdown) {
+				return nil, "", ErrInvalidBreakdown
+			}
+		}
+		// Keep a canonical order without duplicates.
+		selected = make([]Breakdown, 0, len(input.Breakdowns))
+		for _, breakdown := range breakdowns {
+			if slices.Contains(input.Breakdowns, breakdown) {
+				selected = append(selected, breakdown)
+			}
+		}
+	}
+
+	if err := s.sites.RequireRole(ctx, input.SiteID, input.UserID, site.RoleOwner); err != nil {
+		return nil, "", fmt.Errorf("failed to authorize share link: %w", err)
+	}
+	count, err := s.store.CountBySite(ctx, input.SiteID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to count share links: %w", err)
+	}
+	if count >= maxLinksPerSite {
+		return nil, "", ErrTooManyLinks
+	}
+
+	secret, err := platformsecret.Generate(secretPrefix)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate share link secret: %w", err)
+	}
+	link := &Link{
+		SiteID:            input.SiteID,
+		Name:              name,
+		Prefix:            secret[:displayPrefixSize],
+		PasswordProtected: passwordHash != "",
+		Restricted:        selected != nil,
+		Breakdowns:        selected,
+	}
+	if err := s.store.Create(ctx, link, platformsecret.Hash(secret), passwordHash); err != nil {
+		return nil, "", fmt.Errorf("failed to create share link: %w", err)
+	}
+	return link, secret, nil
+}
+
+// Revoke deletes a share link, which stops every visitor using it immediately.
+func (s *Service) Revoke(ctx context.Context, userID, id int64) error {
+	link, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrLinkNotFound) {
+			return ErrLinkNotFound
+		}
+		return fmt.Errorf("failed to get share link: %w", err)
+	}
+	if err := s.sites.RequireRole(ctx, link.SiteID, userID, site.RoleOwner); err != nil {
+		return fmt.Errorf("failed to authorize share link: %w", err)
+	}
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, ErrLinkNotFound) {
+			return ErrLinkNotFound
+		}
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	return nil
+}
+
+// Open describes the link behind a secret so that a visitor can tell whether it needs a password.
+func (s *Service) Open(ctx context.Context, secret string) (*Credential, error) {
+	return s.credential(ctx, secret)
+}
+
+// Unlock checks the password of a protected link and returns the key that Authenticate expects.
+// The key stops working when the link is revoked.
+func (s *Service) Unlock(ctx context.Context, secret, password string) (string, error) {
+	credential, err := s.credential(ctx, secret)
+	if err != nil {
+		return "", err
+	}
+	if !credential.Link.PasswordProtected {
+		return "", nil
+	}
+	if bcrypt.CompareHashAndPassword([]byte(credential.PasswordHash), []byte(password)) != nil {
+		return "", ErrWrongPassword
+	}
+	return s.unlockKey(credential), nil
+}
+
+// Authenticate resolves a share link secret into read-only claims for its site. Protected links
+// also need the key returned by Unlock.
+func (s *Service) Authenticate(ctx context.Context, secret, key string) (*auth.Claims, error) {
+	credential, err := s.credential(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Link.PasswordProtected && !hmac.Equal([]byte(key), []byte(s.unlockKey(credential))) {
+		return nil, ErrWrongPassword
+	}
+
+	link := credential.Link
+	claims := &auth.Claims{
+		ShareLinkID: link.ID,
+		SiteIDs:     []int64{link.SiteID},
+	}
+	if link.Restricted {
+		claims.Breakdowns = make([]string, 0, len(link.Breakdowns))
+		for _, breakdown := range link.Breakdowns {
+			claims.Breakdowns = append(claims.Breakdowns, string(breakdown))
+		}
+	}
+	return claims, nil
+}
+
+func (s *Service) credential(ctx context.Context, secret string) (*Credential, error) {
+	if !strings.HasPrefix(secret, secretPrefix) || len(secret) <= displayPrefixSize {
+		return nil, ErrInvalidLink
+	}
+	credential, err := s.store.GetByHash(ctx, platformsecret.Hash(secret))
+	if err != nil {
+		if errors.Is(err, ErrLinkNotFound) {
+			return nil, ErrInvalidLink
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return credential, nil
+}
+
+// unlockKey binds the key to the stored password hash so that keys are never reused across links.
+func (s *Service) unlockKey(credential *Credential) string {
+	mac := hmac.New(sha256.New, s.unlockSecret)
+	mac.Write([]byte(strconv.FormatInt(credential.Link.ID, 10) + ":" + credential.PasswordHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,196 @@
+package site
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const maxSiteMembers = 100
+
+var (
+	ErrInvalidRole      = errors.New("invalid site role")
+	ErrMemberNotFound   = errors.New("site member not found")
+	ErrMemberExists     = errors.New("user is already a member of this site")
+	ErrMemberUserAbsent = errors.New("user not found")
+	ErrTooManyMembers   = errors.New("site member limit of 100 reached")
+)
+
+// Role is a user's access level on a site. Each role includes the permissions of the roles below it.
+type Role string
+
+const (
+	// RoleViewer reads dashboards, goals, funnels, and event definitions.
+	RoleViewer Role = "viewer"
+	// RoleEditor also manages event definitions, goals, funnels, and site settings such as blocking, but
+	// not the site's domains or timezone.
+	RoleEditor Role = "editor"
+	// RoleOwner also changes domains and timezone, deletes the site, regenerates its key, and manages members.
+	RoleOwner Role = "owner"
+)
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows reports whether r grants the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+// Member grants a user other than the site creator access to a site.
+type Member struct {
+	ID        int64
+	SiteID    int64
+	UserID    int64
+	Username  string
+	Role      Role
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RoleFor returns the role userID holds on the site, or an empty role without access.
+// The site creator is always an owner.
+func (s *Site) RoleFor(userID int64) Role {
+	if s == nil {
+		return ""
+	}
+	if s.UserID == userID {
+		return RoleOwner
+	}
+	for _, member := range s.Members {
+		if member != nil && member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// RequireRole verifies that userID holds at least role on the site without loading its relations.
+func (s *Service) RequireRole(ctx context.Context, id, userID int64, role Role) error {
+	_, err := s.RequireRoleLocation(ctx, id, userID, role)
+	return err
+}
+
+// RequireRoleLocation verifies access like RequireRole and returns the site location
+// that dashboard date ranges and buckets are resolved in.
+func (s *Service) RequireRoleLocation(ctx context.Context, id, userID int64, role Role) (*time.Location, error) {
+	granted, timezone, err := s.store.GetAccess(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, ErrSiteNotFound) {
+			return nil, ErrSiteNotFound
+		}
+		return nil, fmt.Errorf("failed to get site access: %w", err)
+	}
+	if !granted.Allows(role) {
+		return nil, ErrNotAuthorized
+	}
+	return (&Site{Timezone: timezone}).Location(), nil
+}
+
+// Location returns the site location for callers that were authorized without a user role,
+// such as visitors of a shared dashboard.
+func (s *Service) Location(ctx context.Context, id int64) (*time.Location, error) {
+	_, timezone, err := s.store.GetAccess(ctx, id, 0)
+	if err != nil {
+		if errors.Is(err, ErrSiteNotFound) {
+			return nil, ErrSiteNotFound
+		}
+		return nil, fmt.Errorf("failed to get site location: %w", err)
+	}
+	return (&Site{Timezone: timezone}).Location(), nil
+}
+
+// ListMembers returns the explicit members of a site. The creator is reported by Site.UserID.
+func (s *Service) ListMembers(ctx context.Context, id, userID int64) ([]*Member, error) {
+	if err := s.RequireRole(ctx, id, userID, RoleViewer); err != nil {
+		return nil, err
+	}
+	members, err := s.store.ListMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list site members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember grants an existing user access to a site. Only owners manage members.
+func (s *Service) AddMember(ctx context.Context, id, userID int64, username string, role Role) (*Member, error) {
+	if role.rank() == 0 {
+		return nil, ErrInvalidRole
+	}
+	site, err := s.getAuthorizedSite(ctx, id, userID, RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	memberUserID, err := s.store.GetUserIDByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		if errors.Is(err, ErrMemberUserAbsent) {
+			return nil, ErrMemberUserAbsent
+		}
+		return nil, fmt.Errorf("failed to get member user: %w", err)
+	}
+	if site.RoleFor(memberUserID) != "" {
+		return nil, ErrMemberExists
+	}
+	if len(site.Members) >= maxSiteMembers {
+		return nil, ErrTooManyMembers
+	}
+
+	member := &Member{SiteID: site.ID, UserID: memberUserID, Role: role}
+	if err := s.store.AddMember(ctx, member); err != nil {
+		if errors.Is(err, ErrMemberExists) {
+			return nil, ErrMemberExists
+		}
+		return nil, fmt.Errorf("failed to add site member: %w", err)
+	}
+	member.Username = strings.TrimSpace(username)
+	return member, nil
+}
+
+// UpdateMemberRole changes the role of an explicit member. The creator's owner role is fixed.
+func (s *Service) UpdateMemberRole(ctx context.Context, id, userID, memberUserID int64, role Role) (*Member, error) {
+	if role.rank() == 0 {
+		return nil, ErrInvalidRole
+	}
+	if err := s.RequireRole(ctx, id, userID, RoleOwner); err != nil {
+		return nil, err
+	}
+	member, err := s.store.UpdateMemberRole(ctx, id, memberUserID, role)
+	if err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to update site member: %w", err)
+	}
+	return member, nil
+}
+
+// RemoveMember revokes an explicit membership. Owners may remove anyone; other members may leave.
+func (s *Service) RemoveMember(ctx context.Context, id, userID, memberUserID int64) error {
+	required := RoleOwner
+	if memberUserID == userID {
+		required = RoleViewer
+	}
+	if err := s.RequireRole(ctx, id, userID, required); err != nil {
+		return err
+	}
+	if err := s.store.RemoveMember(ctx, id, memberUserID); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("failed to remove site member: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sitefeature "github.com/lovely-eye/server/internal/site"
+	"github.com/uptrace/bun"
+)
+
+func orderedMembers(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("sm.id ASC")
+}
+
+type memberWithUsername struct {
+	Member   `bun:",extend"`
+	Username string `bun:"username"`
+}
+
+func (r *Repository) ListMembers(ctx context.Context, siteID int64) ([]*sitefeature.Member, error) {
+	var rows []*memberWithUsername
+	err := r.db.NewSelect().
+		Model(&rows).
+		ColumnExpr("sm.*").
+		ColumnExpr("u.username").
+		Join("JOIN users AS u ON u.id = sm.user_id").
+		Where("sm.site_id = ?", siteID).
+		Order("u.username ASC", "sm.id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get site members: %w", err)
+	}
+	result := make([]*sitefeature.Member, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, memberFromModel(&row.Member, row.Username))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetUserIDByUsername(ctx context.Context, username string) (int64, error) {
+	var userID int64
+	err := r.db.NewSelect().
+		Table("users").
+		Column("id").
+		Where("username = ?", username).
+		Scan(ctx, &userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, sitefeature.ErrMemberUserAbsent
+		}
+		return 0, fmt.Errorf("failed to get user by username: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *Repository) AddMember(ctx context.Context, member *sitefeature.Member) error {
+	exists, err := r.db.NewSelect().
+		Model((*Member)(nil)).
+		Where("site_id = ?", member.SiteID).
+		Where("user_id = ?", member.UserID).
+		Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check site member: %w", err)
+	}
+	if exists {
+		return sitefeature.ErrMemberExists
+	}
+
+	now := time.Now()
+	row := &Member{
+		SiteID:    member.SiteID,
+		UserID:    member.UserID,
+		Role:      string(member.Role),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := r.db.NewInsert().Model(row).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to insert site member: %w", err)
+	}
+	member.ID = row.ID
+	member.CreatedAt = row.CreatedAt
+	member.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *Repository) UpdateMemberRole(
+	ctx context.Context,
+	siteID,
+	userID int64,
+	role sitefeature.Role,
+) (*sitefeature.Member, error) {
+	result, err := r.db.NewUpdate().
+		Model((*Member)(nil)).
+		Set("role = ?", string(role)).
+		Set("updated_at = ?", time.Now()).
+		Where("site_id = ?", siteID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update site member role: %w", err)
+	}
+	if err := requireAffectedMember(result, "update site member role"); err != nil {
+		return nil, err
+	}
+
+	row := new(memberWithUsername)
+	err = r.db.NewSelect().
+		Model(row).
+		ColumnExpr("sm.*").
+		ColumnExpr("u.username").
+		Join("JOIN users AS u ON u.id = sm.user_id").
+		Where("sm.site_id = ?", siteID).
+		Where("sm.user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sitefeature.ErrMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get site member: %w", err)
+	}
+	return memberFromModel(&row.Member, row.Username), nil
+}
+
+func (r *Repository) RemoveMember(ctx context.Context, siteID, userID int64) error {
+	result, err := r.db.NewDelete().
+		Model((*Member)(nil)).
+		Where("site_id = ?", siteID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete site member: %w", err)
+	}
+	return requireAffectedMember(result, "delete site member")
+}
+
+func requireAffectedMember(result sql.Result, operation string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", operation, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", operation, sitefeature.ErrMemberNotFound)
+	}
+	return nil
+}
+
+func memberFromModel(row *Member, username string) *sitefeature.Member {
+	return &sitefeature.Member{
+		ID:        row.ID,
+		SiteID:    row.SiteID,
+		UserID:    row.UserID,
+		Username:  username,
+		Role:      sitefeature.Role(row.Role),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
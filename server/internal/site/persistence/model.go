@@ -14,12 +14,14 @@ type Site struct {
 	Name         string    `bun:"name,notnull"`
 	PublicKey    string    `bun:"public_key,unique,notnull"`
 	TrackCountry bool      `bun:"track_country,notnull,default:false"`
+	Timezone     string    `bun:"timezone,type:varchar(64),nullzero,notnull,default:'UTC'"`
 	CreatedAt    time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt    time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 
 	Domains          []*Domain         `bun:"rel:has-many,join:id=site_id"`
 	BlockedIPs       []*BlockedIP      `bun:"rel:has-many,join:id=site_id"`
 	BlockedCountries []*BlockedCountry `bun:"rel:has-many,join:id=site_id"`
+	Members          []*Member         `bun:"rel:has-many,join:id=site_id"`
 }
 
 type Domain struct {
@@ -52,3 +54,14 @@ type BlockedCountry struct {
 	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt   time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 }
+
+type Member struct {
+	bun.BaseModel `bun:"table:site_members,alias:sm"`
+
+	ID        int64     `bun:"id,pk,autoincrement"`
+	SiteID    int64     `bun:"site_id,notnull,unique:site_members_site_id_user_id"`
+	UserID    int64     `bun:"user_id,notnull,unique:site_members_site_id_user_id"`
+	Role      string    `bun:"role,type:varchar(16),notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -27,3 +27,27 @@ type ownedEventDefinition struct {
 type ownedEventDefinitionField struct {
 	bun.BaseModel `bun:"table:event_definition_fields,alias:edf"`
 }
+
+type ownedGoal struct {
+	bun.BaseModel `bun:"table:goals,alias:g"`
+}
+
+type ownedFunnel struct {
+	bun.BaseModel `bun:"table:funnels,alias:f"`
+}
+
+type ownedFunnelStep struct {
+	bun.BaseModel `bun:"table:funnel_steps,alias:fs"`
+}
+
+type ownedAPITokenSite struct {
+	bun.BaseModel `bun:"table:api_token_sites,alias:ats"`
+}
+
+type ownedShareLink struct {
+	bun.BaseModel `bun:"table:share_links,alias:sl"`
+}
+
+type ownedIngestKey struct {
+	bun.BaseModel `bun:"table:ingest_keys,alias:ik"`
+}
@@ -34,6 +34,7 @@ func (r *Repository) GetByID(ctx context.Context, id int64) (*sitefeature.Site,
 		Relation("BlockedCountries", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Order("country_code ASC")
 		}).
+		Relation("Members", orderedMembers).
 		Scan(ctx)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -44,20 +45,29 @@ func (r *Repository) GetByID(ctx context.Context, id int64) (*sitefeature.Site,
 	return siteFromModel(site), nil
 }
 
-func (r *Repository) GetOwnerID(ctx context.Context, id int64) (int64, error) {
-	var ownerID int64
+func (r *Repository) GetAccess(ctx context.Context, id, userID int64) (sitefeature.Role, string, error) {
+	var row struct {
+		UserID     int64
+		Timezone   string
+		MemberRole sql.NullString
+	}
 	err := r.db.NewSelect().
 		Model((*Site)(nil)).
-		Column("user_id").
-		Where("id = ?", id).
-		Scan(ctx, &ownerID)
+		Column("s.user_id", "s.timezone").
+		ColumnExpr("sm.role AS member_role").
+		Join("LEFT JOIN site_members AS sm ON sm.site_id = s.id AND sm.user_id = ?", userID).
+		Where("s.id = ?", id).
+		Scan(ctx, &row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, sitefeature.ErrSiteNotFound
+			return "", "", sitefeature.ErrSiteNotFound
 		}
-		return 0, fmt.Errorf("failed to get site owner: %w", err)
+		return "", "", fmt.Errorf("failed to get site access: %w", err)
+	}
+	if row.UserID == userID {
+		return sitefeature.RoleOwner, row.Timezone, nil
 	}
-	return ownerID, nil
+	return sitefeature.Role(row.MemberRole.String), row.Timezone, nil
 }
 
 func (r *Repository) GetByPublicKey(ctx context.Context, publicKey string) (*sitefeature.Site, error) {
@@ -139,13 +149,14 @@ func (r *Repository) DomainExistsForUser(
 func (r *Repository) GetByUserID(
 	ctx context.Context,
 	userID int64,
+	siteIDs []int64,
 	limit,
 	offset int,
 ) ([]*sitefeature.Site, error) {
 	var sites []*Site
 	q := r.db.NewSelect().
 		Model(&sites).
-		Where("user_id = ?", userID).
+		Where("s.user_id = ? OR s.id IN (SELECT site_id FROM site_members WHERE user_id = ?)", userID, userID).
 		Relation("Domains", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Order("position ASC", "id ASC")
 		}).
@@ -154,7 +165,12 @@ func (r *Repository) GetByUserID(
 		}).
 		Relation("BlockedCountries", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Order("country_code ASC")
-		})
+		}).
+		Relation("Members", orderedMembers).
+		Order("s.id ASC")
+	if siteIDs != nil {
+		q = q.Where("s.id IN (?)", bun.List(siteIDs))
+	}
 	if limit > 0 {
 		q = q.Limit(limit)
 	}
@@ -262,6 +278,49 @@ func deleteSiteAnalytics(ctx context.Context, tx bun.Tx, siteID int64) error {
 }
 
 func deleteSiteConfiguration(ctx context.Context, tx bun.Tx, siteID int64) error {
+	if _, err := tx.NewDelete().
+		Model((*Member)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site members: %w", err)
+	}
+	// Scoped API tokens keep their scope flag, so removing the last site leaves them without access.
+	if _, err := tx.NewDelete().
+		Model((*ownedAPITokenSite)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site API token scopes: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedShareLink)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site share links: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedIngestKey)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site ingestion keys: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedFunnelStep)(nil)).
+		Where("funnel_id IN (SELECT id FROM funnels WHERE site_id = ?)", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site funnel steps: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedFunnel)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site funnels: %w", err)
+	}
+	if _, err := tx.NewDelete().
+		Model((*ownedGoal)(nil)).
+		Where("site_id = ?", siteID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("delete site goals: %w", err)
+	}
 	if _, err := tx.NewDelete().
 		Model((*BlockedCountry)(nil)).
 		Where("site_id = ?", siteID).
@@ -381,6 +440,7 @@ func siteFromModel(row *Site) *sitefeature.Site {
 		Name:         row.Name,
 		PublicKey:    row.PublicKey,
 		TrackCountry: row.TrackCountry,
+		Timezone:     row.Timezone,
 		CreatedAt:    row.CreatedAt,
 		UpdatedAt:    row.UpdatedAt,
 	}
@@ -401,10 +461,12 @@ func siteFromModel(row *Site) *sitefeature.Site {
 		if blocked == nil {
 			continue
 		}
+		prefix, _ := sitefeature.ParseIPPrefix(blocked.IP)
 		site.BlockedIPs = append(site.BlockedIPs, &sitefeature.BlockedIP{
 			ID:        blocked.ID,
 			SiteID:    blocked.SiteID,
 			IP:        blocked.IP,
+			Prefix:    prefix,
 			CreatedAt: blocked.CreatedAt,
 			UpdatedAt: blocked.UpdatedAt,
 		})
@@ -421,6 +483,12 @@ func siteFromModel(row *Site) *sitefeature.Site {
 			UpdatedAt:   blocked.UpdatedAt,
 		})
 	}
+	for _, member := range row.Members {
+		if member == nil {
+			continue
+		}
+		site.Members = append(site.Members, memberFromModel(member, ""))
+	}
 	return site
 }
 
@@ -431,6 +499,7 @@ func siteModel(site *sitefeature.Site) *Site {
 		Name:         site.Name,
 		PublicKey:    site.PublicKey,
 		TrackCountry: site.TrackCountry,
+		Timezone:     site.Timezone,
 		CreatedAt:    site.CreatedAt,
 		UpdatedAt:    site.UpdatedAt,
 	}
@@ -440,10 +509,12 @@ func copySite(destination *sitefeature.Site, source *Site) {
 	domains := destination.Domains
 	blockedIPs := destination.BlockedIPs
 	blockedCountries := destination.BlockedCountries
+	members := destination.Members
 	*destination = *siteFromModel(source)
 	destination.Domains = domains
 	destination.BlockedIPs = blockedIPs
 	destination.BlockedCountries = blockedCountries
+	destination.Members = members
 }
 
 func replaceSiteDomains(ctx context.Context, tx bun.Tx, siteID int64, domains []string) error {
@@ -9,6 +9,10 @@ import (
 	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
 	"github.com/lovely-eye/server/internal/event"
 	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	"github.com/lovely-eye/server/internal/funnel"
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
+	"github.com/lovely-eye/server/internal/goal"
+	goalpersistence "github.com/lovely-eye/server/internal/goal/persistence"
 	sitefeature "github.com/lovely-eye/server/internal/site"
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
@@ -51,6 +55,23 @@ func TestRepository_DeleteRemovesOwnedData(t *testing.T) {
 	require.NoError(t, err)
 	_, err = db.NewInsert().Model(&BlockedCountry{SiteID: site.ID, CountryCode: "US"}).Exec(ctx)
 	require.NoError(t, err)
+	require.NoError(t, goalpersistence.New(db).Create(ctx, &goal.Goal{
+		SiteID: site.ID,
+		Name:   "Deleted goal",
+		Type:   goal.TypeEvent,
+		Value:  "deleted_event",
+	}))
+	require.NoError(t, funnelpersistence.New(db).Create(ctx, &funnel.Funnel{
+		SiteID: site.ID,
+		Name:   "Deleted funnel",
+		Steps: []funnel.Step{
+			{Type: funnel.StepTypePage, Value: "/delete"},
+			{Type: funnel.StepTypeEvent, Value: "deleted_event"},
+		},
+	}))
+
+	_, err = db.NewInsert().Model(&Member{SiteID: site.ID, UserID: site.UserID, Role: string(sitefeature.RoleViewer)}).Exec(ctx)
+	require.NoError(t, err)
 
 	require.NoError(t, siteRepo.Delete(ctx, site.ID))
 
@@ -60,11 +81,15 @@ func TestRepository_DeleteRemovesOwnedData(t *testing.T) {
 	requireModelTableEmpty(t, db, (*analyticspersistence.Event)(nil))
 	requireModelTableEmpty(t, db, (*eventpersistence.Field)(nil))
 	requireModelTableEmpty(t, db, (*eventpersistence.Definition)(nil))
+	requireModelTableEmpty(t, db, (*goalpersistence.Goal)(nil))
+	requireModelTableEmpty(t, db, (*funnelpersistence.Step)(nil))
+	requireModelTableEmpty(t, db, (*funnelpersistence.Funnel)(nil))
 	requireModelTableEmpty(t, db, (*analyticspersistence.Session)(nil))
 	requireModelTableEmpty(t, db, (*analyticspersistence.Client)(nil))
 	requireModelTableEmpty(t, db, (*BlockedIP)(nil))
 	requireModelTableEmpty(t, db, (*BlockedCountry)(nil))
 	requireModelTableEmpty(t, db, (*Domain)(nil))
+	requireModelTableEmpty(t, db, (*Member)(nil))
 }
 
 func TestRepository_GetByPublicKeyReturnsAnalyticsRelations(t *testing.T) {
@@ -6,7 +6,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/netip"
+	"slices"
+	"sync"
 	"time"
+
+	"github.com/lovely-eye/server/internal/audit"
 )
 
 var (
@@ -19,15 +24,23 @@ var (
 
 type Store interface {
 	GetByID(ctx context.Context, id int64) (*Site, error)
-	GetOwnerID(ctx context.Context, id int64) (int64, error)
+	// GetAccess returns the role userID holds on the site, empty without access, and the site timezone.
+	GetAccess(ctx context.Context, id, userID int64) (Role, string, error)
 	GetByPublicKey(ctx context.Context, publicKey string) (*Site, error)
-	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*Site, error)
+	// GetByUserID returns the sites a user created or is a member of. A non-nil siteIDs further limits
+	// the result to those sites before paging.
+	GetByUserID(ctx context.Context, userID int64, siteIDs []int64, limit, offset int) ([]*Site, error)
 	AnyGeoIPRequirement(ctx context.Context) (bool, error)
 	DomainExistsForUser(ctx context.Context, userID int64, domain string, excludedSiteID int64) (bool, error)
 	CreateWithDomains(ctx context.Context, site *Site, domains []string) error
 	Update(ctx context.Context, site *Site) error
 	UpdateWithRelations(ctx context.Context, site *Site, domains, blockedIPs, blockedCountries []string) error
 	Delete(ctx context.Context, id int64) error
+	ListMembers(ctx context.Context, siteID int64) ([]*Member, error)
+	GetUserIDByUsername(ctx context.Context, username string) (int64, error)
+	AddMember(ctx context.Context, member *Member) error
+	UpdateMemberRole(ctx context.Context, siteID, userID int64, role Role) (*Member, error)
+	RemoveMember(ctx context.Context, siteID, userID int64) error
 }
 
 type Site struct {
@@ -36,11 +49,40 @@ type Site struct {
 	Name             string
 	PublicKey        string
 	TrackCountry     bool
+	Timezone         string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	Domains          []*Domain
 	BlockedIPs       []*BlockedIP
 	BlockedCountries []*BlockedCountry
+	Members          []*Member
+}
+
+// Location resolves the IANA timezone that dashboard days and hours are bucketed in, falling back to UTC for unset or unknown zones.
+func (s *Site) Location() *time.Location {
+	if s == nil || s.Timezone == "" {
+		return time.UTC
+	}
+	location, err := loadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// locations caches resolved zones by name because every analytics request resolves the site timezone.
+var locations sync.Map
+
+func loadLocation(name string) (*time.Location, error) {
+	if cached, ok := locations.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+	return location, nil
 }
 
 type Domain struct {
@@ -53,9 +95,13 @@ type Domain struct {
 }
 
 type BlockedIP struct {
-	ID        int64
-	SiteID    int64
-	IP        string
+	ID     int64
+	SiteID int64
+	// IP is an address or a CIDR range.
+	IP string
+	// Prefix is IP parsed once when the site is loaded, so that collect requests only compare
+	// addresses. An unparsable legacy value leaves it invalid, which matches nothing.
+	Prefix    netip.Prefix
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -70,21 +116,36 @@ type BlockedCountry struct {
 
 type Service struct {
 	store Store
+	// auditLog is nil unless SetAuditRecorder was called.
+	auditLog audit.Recorder
 }
 
 func NewService(store Store) *Service {
 	return &Service{store: store}
 }
 
+// SetAuditRecorder records site updates, deletions, and key regeneration in the audit log.
+func (s *Service) SetAuditRecorder(recorder audit.Recorder) {
+	s.auditLog = recorder
+}
+
+func (s *Service) recordAudit(ctx context.Context, entry audit.Entry) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, entry)
+	}
+}
+
 type CreateSiteInput struct {
-	Domains []string
-	Name    string
-	UserID  int64
+	Domains  []string
+	Name     string
+	Timezone string
+	UserID   int64
 }
 
 type UpdateSiteInput struct {
 	Name             string
 	TrackCountry     *bool
+	Timezone         *string
 	Domains          []string
 	BlockedIPs       []string
 	BlockedCountries []string
@@ -101,6 +162,11 @@ func (s *Service) Create(ctx context.Context, input CreateSiteInput) (*Site, err
 		return nil, fmt.Errorf("failed to validate site name: %w", err)
 	}
 
+	timezone, err := ValidateTimezone(input.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate site timezone: %w", err)
+	}
+
 	for _, domain := range normalizedDomains {
 		exists, err := s.store.DomainExistsForUser(ctx, input.UserID, domain, 0)
 		if err != nil {
@@ -120,6 +186,7 @@ func (s *Service) Create(ctx context.Context, input CreateSiteInput) (*Site, err
 		UserID:    input.UserID,
 		Name:      validatedName,
 		PublicKey: publicKey,
+		Timezone:  timezone,
 	}
 
 	if err := s.store.CreateWithDomains(ctx, site, normalizedDomains); err != nil {
@@ -131,7 +198,7 @@ func (s *Service) Create(ctx context.Context, input CreateSiteInput) (*Site, err
 }
 
 func (s *Service) GetByID(ctx context.Context, id, userID int64) (*Site, error) {
-	return s.getAuthorizedSite(ctx, id, userID)
+	return s.getAuthorizedSite(ctx, id, userID, RoleViewer)
 }
 
 func (s *Service) GetByPublicKey(ctx context.Context, publicKey string) (*Site, error) {
@@ -145,8 +212,13 @@ func (s *Service) GetByPublicKey(ctx context.Context, publicKey string) (*Site,
 	return site, nil
 }
 
-func (s *Service) GetUserSites(ctx context.Context, userID int64, limit, offset int) ([]*Site, error) {
-	sites, err := s.store.GetByUserID(ctx, userID, limit, offset)
+// GetUserSites pages the user's sites. A non-nil siteIDs, such as an API token scope, limits them to
+// those sites.
+func (s *Service) GetUserSites(ctx context.Context, userID int64, siteIDs []int64, limit, offset int) ([]*Site, error) {
+	if siteIDs != nil && len(siteIDs) == 0 {
+		return nil, nil
+	}
+	sites, err := s.store.GetByUserID(ctx, userID, siteIDs, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user sites: %w", err)
 	}
@@ -159,15 +231,27 @@ func (s *Service) Update(ctx context.Context, id, userID int64, input UpdateSite
 		return nil, fmt.Errorf("failed to validate site name: %w", err)
 	}
 
-	site, err := s.getAuthorizedSite(ctx, id, userID)
+	site, err := s.getAuthorizedSite(ctx, id, userID, RoleEditor)
 	if err != nil {
 		return nil, err
 	}
+	before := *site
+	// Domains decide which pages may report to the site and the timezone shifts every dashboard day, so
+	// only owners change them. Editors may still resend the current values with their other settings.
+	ownerRequired := false
 
 	site.Name = validatedName
 	if input.TrackCountry != nil {
 		site.TrackCountry = *input.TrackCountry
 	}
+	if input.Timezone != nil {
+		timezone, err := ValidateTimezone(*input.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate site timezone: %w", err)
+		}
+		ownerRequired = ownerRequired || timezone != site.Timezone
+		site.Timezone = timezone
+	}
 
 	var normalizedDomains []string
 	if input.Domains != nil {
@@ -175,9 +259,15 @@ func (s *Service) Update(ctx context.Context, id, userID int64, input UpdateSite
 		if err != nil {
 			return nil, err
 		}
+		ownerRequired = ownerRequired || !slices.Equal(normalizedDomains, domainNames(site.Domains))
+	}
+	if ownerRequired && !site.RoleFor(userID).Allows(RoleOwner) {
+		return nil, ErrNotAuthorized
+	}
 
+	if input.Domains != nil {
 		for _, domain := range normalizedDomains {
-			exists, err := s.store.DomainExistsForUser(ctx, userID, domain, site.ID)
+			exists, err := s.store.DomainExistsForUser(ctx, site.UserID, domain, site.ID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to check domain availability: %w", err)
 			}
@@ -207,6 +297,7 @@ func (s *Service) Update(ctx context.Context, id, userID int64, input UpdateSite
 		if err := s.store.Update(ctx, site); err != nil {
 			return nil, classifySiteWriteError("update site", err)
 		}
+		s.recordUpdate(ctx, userID, &before, site)
 		return site, nil
 	}
 
@@ -223,11 +314,12 @@ func (s *Service) Update(ctx context.Context, id, userID int64, input UpdateSite
 	if input.BlockedCountries != nil {
 		site.BlockedCountries = buildBlockedCountries(site.ID, normalizedBlockedCountries)
 	}
+	s.recordUpdate(ctx, userID, &before, site)
 	return site, nil
 }
 
 func (s *Service) Delete(ctx context.Context, id, userID int64) error {
-	_, err := s.getAuthorizedSite(ctx, id, userID)
+	site, err := s.getAuthorizedSite(ctx, id, userID, RoleOwner)
 	if err != nil {
 		return err
 	}
@@ -235,11 +327,16 @@ func (s *Service) Delete(ctx context.Context, id, userID int64) error {
 	if err := s.store.Delete(ctx, id); err != nil {
 		return classifySiteWriteError("delete site", err)
 	}
+
+	entry := audit.Entry{ActorID: userID, Action: audit.ActionSiteDelete, SiteID: id}
+	entry.Diff("name", site.Name, nil)
+	entry.Diff("domains", domainNames(site.Domains), nil)
+	s.recordAudit(ctx, entry)
 	return nil
 }
 
 func (s *Service) RegeneratePublicKey(ctx context.Context, id, userID int64) (*Site, error) {
-	site, err := s.getAuthorizedSite(ctx, id, userID)
+	site, err := s.getAuthorizedSite(ctx, id, userID, RoleOwner)
 	if err != nil {
 		return nil, err
 	}
@@ -249,15 +346,34 @@ func (s *Service) RegeneratePublicKey(ctx context.Context, id, userID int64) (*S
 		return nil, err
 	}
 
+	previousKey := site.PublicKey
 	site.PublicKey = publicKey
 	if err := s.store.Update(ctx, site); err != nil {
 		return nil, classifySiteWriteError("update site public key", err)
 	}
 
+	entry := audit.Entry{ActorID: userID, Action: audit.ActionSiteKeyRegenerate, SiteID: id}
+	entry.Diff("publicKey", previousKey, site.PublicKey)
+	s.recordAudit(ctx, entry)
 	return site, nil
 }
 
-func (s *Service) getAuthorizedSite(ctx context.Context, id, userID int64) (*Site, error) {
+// recordUpdate audits the fields that an update changed. Relations that the update left out
+// keep their loaded values and therefore produce no change.
+func (s *Service) recordUpdate(ctx context.Context, userID int64, before, after *Site) {
+	entry := audit.Entry{ActorID: userID, Action: audit.ActionSiteUpdate, SiteID: after.ID}
+	entry.Diff("name", before.Name, after.Name)
+	entry.Diff("trackCountry", before.TrackCountry, after.TrackCountry)
+	entry.Diff("timezone", before.Timezone, after.Timezone)
+	entry.Diff("domains", domainNames(before.Domains), domainNames(after.Domains))
+	entry.Diff("blockedIPs", blockedIPValues(before.BlockedIPs), blockedIPValues(after.BlockedIPs))
+	entry.Diff("blockedCountries", blockedCountryCodes(before.BlockedCountries), blockedCountryCodes(after.BlockedCountries))
+	if len(entry.Changes) > 0 {
+		s.recordAudit(ctx, entry)
+	}
+}
+
+func (s *Service) getAuthorizedSite(ctx context.Context, id, userID int64, role Role) (*Site, error) {
 	site, err := s.store.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, ErrSiteNotFound) {
@@ -265,27 +381,12 @@ func (s *Service) getAuthorizedSite(ctx context.Context, id, userID int64) (*Sit
 		}
 		return nil, fmt.Errorf("failed to get site by id: %w", err)
 	}
-	if site.UserID != userID {
+	if !site.RoleFor(userID).Allows(role) {
 		return nil, ErrNotAuthorized
 	}
 	return site, nil
 }
 
-// RequireOwnership verifies access without loading relations that the caller does not consume.
-func (s *Service) RequireOwnership(ctx context.Context, id, userID int64) error {
-	ownerID, err := s.store.GetOwnerID(ctx, id)
-	if err != nil {
-		if errors.Is(err, ErrSiteNotFound) {
-			return ErrSiteNotFound
-		}
-		return fmt.Errorf("failed to get site owner: %w", err)
-	}
-	if ownerID != userID {
-		return ErrNotAuthorized
-	}
-	return nil
-}
-
 func classifySiteWriteError(operation string, err error) error {
 	if errors.Is(err, ErrSiteNotFound) {
 		return ErrSiteNotFound
@@ -335,12 +436,41 @@ func buildSiteDomains(siteID int64, domains []string) []*Domain {
 	return result
 }
 
+func domainNames(domains []*Domain) []string {
+	result := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, domain.Domain)
+	}
+	return result
+}
+
+// blockedIPValues sorts like the store so that reordering a list is not audited as a change.
+func blockedIPValues(ips []*BlockedIP) []string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		result = append(result, ip.IP)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func blockedCountryCodes(countries []*BlockedCountry) []string {
+	result := make([]string, 0, len(countries))
+	for _, country := range countries {
+		result = append(result, country.CountryCode)
+	}
+	slices.Sort(result)
+	return result
+}
+
 func buildBlockedIPs(siteID int64, ips []string) []*BlockedIP {
 	result := make([]*BlockedIP, 0, len(ips))
 	for _, ip := range ips {
+		prefix, _ := ParseIPPrefix(ip)
 		result = append(result, &BlockedIP{
 			SiteID: siteID,
 			IP:     ip,
+			Prefix: prefix,
 		})
 	}
 	return result
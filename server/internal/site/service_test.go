@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/lovely-eye/server/internal/audit"
+	auditpersistence "github.com/lovely-eye/server/internal/audit/persistence"
 	authpersistence "github.com/lovely-eye/server/internal/auth/persistence"
 	"github.com/lovely-eye/server/internal/platform/database"
 	"github.com/lovely-eye/server/internal/site"
@@ -111,6 +114,219 @@ func TestSiteServiceMissingMutationErrorsRemainStable(t *testing.T) {
 	}
 }
 
+func TestSiteServiceStoresTimezone(t *testing.T) {
+	service, _, userID := newSiteServiceTest(t)
+	ctx := context.Background()
+	created, err := service.Create(ctx, site.CreateSiteInput{
+		Domains: []string{"example.com"},
+		Name:    "Example",
+		UserID:  userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Timezone != "UTC" {
+		t.Fatalf("expected UTC default timezone, got %q", created.Timezone)
+	}
+
+	invalid := "Mars/Olympus_Mons"
+	if _, err := service.Update(ctx, created.ID, userID, site.UpdateSiteInput{Name: "Example", Timezone: &invalid}); !errors.Is(err, site.ErrInvalidTimezone) {
+		t.Fatalf("expected invalid-timezone error, got %v", err)
+	}
+
+	timezone := "America/Los_Angeles"
+	if _, err := service.Update(ctx, created.ID, userID, site.UpdateSiteInput{Name: "Example", Timezone: &timezone}); err != nil {
+		t.Fatal(err)
+	}
+	location, err := service.RequireRoleLocation(ctx, created.ID, userID, site.RoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.String() != timezone {
+		t.Fatalf("expected %s location, got %s", timezone, location)
+	}
+	if _, err := service.RequireRoleLocation(ctx, created.ID, userID+1, site.RoleViewer); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected not-authorized error, got %v", err)
+	}
+}
+
+func TestSiteServiceAuditsChanges(t *testing.T) {
+	service, db, userID := newSiteServiceTest(t)
+	auditService := audit.NewService(auditpersistence.New(db))
+	service.SetAuditRecorder(auditService)
+	ctx := context.Background()
+	created, err := service.Create(ctx, site.CreateSiteInput{
+		Domains: []string{"example.com"},
+		Name:    "Example",
+		UserID:  userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := site.UpdateSiteInput{Name: "Example", BlockedCountries: []string{"RU", "CN"}}
+	if _, err := service.Update(ctx, created.ID, userID, update); err != nil {
+		t.Fatal(err)
+	}
+	update.BlockedCountries = []string{"CN", "RU"}
+	if _, err := service.Update(ctx, created.ID, userID, update); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RegeneratePublicKey(ctx, created.ID, userID); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Delete(ctx, created.ID, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, total, err := auditService.List(ctx, audit.ListFilter{SiteID: created.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Fatalf("expected reordering to be skipped and 3 entries, got %d", total)
+	}
+	actions := []audit.Action{entries[0].Action, entries[1].Action, entries[2].Action}
+	expected := []audit.Action{audit.ActionSiteDelete, audit.ActionSiteKeyRegenerate, audit.ActionSiteUpdate}
+	if !slices.Equal(actions, expected) {
+		t.Fatalf("expected newest-first actions %v, got %v", expected, actions)
+	}
+	updated := entries[2]
+	if updated.ActorName != "site-test" || len(updated.Changes) != 1 {
+		t.Fatalf("expected one change by site-test, got %+v", updated)
+	}
+	change := updated.Changes[0]
+	if change.Field != "blockedCountries" || string(change.Before) != "[]" || string(change.After) != `["CN","RU"]` {
+		t.Fatalf("unexpected blocked country diff: %s %s -> %s", change.Field, change.Before, change.After)
+	}
+}
+
+func TestSiteServiceEnforcesMemberRoles(t *testing.T) {
+	service, db, ownerID := newSiteServiceTest(t)
+	ctx := context.Background()
+	created, err := service.Create(ctx, site.CreateSiteInput{
+		Domains: []string{"example.com"},
+		Name:    "Example",
+		UserID:  ownerID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member := &authpersistence.User{Username: "teammate", PasswordHash: "hash", Role: "user"}
+	if _, err := db.NewInsert().Model(member).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.GetByID(ctx, created.ID, member.ID); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected not-authorized error before invite, got %v", err)
+	}
+	if _, err := service.AddMember(ctx, created.ID, member.ID, "teammate", site.RoleViewer); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected only owners to add members, got %v", err)
+	}
+	if _, err := service.AddMember(ctx, created.ID, ownerID, "missing", site.RoleViewer); !errors.Is(err, site.ErrMemberUserAbsent) {
+		t.Fatalf("expected unknown-user error, got %v", err)
+	}
+	if _, err := service.AddMember(ctx, created.ID, ownerID, "site-test", site.RoleViewer); !errors.Is(err, site.ErrMemberExists) {
+		t.Fatalf("expected creator to already be a member, got %v", err)
+	}
+	if _, err := service.AddMember(ctx, created.ID, ownerID, "teammate", site.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := service.GetByID(ctx, created.ID, member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role := loaded.RoleFor(member.ID); role != site.RoleViewer {
+		t.Fatalf("expected viewer role, got %q", role)
+	}
+	sites, err := service.GetUserSites(ctx, member.ID, nil, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sites) != 1 || sites[0].ID != created.ID {
+		t.Fatalf("expected shared site in member site list, got %v", sites)
+	}
+	if _, err := service.Update(ctx, created.ID, member.ID, site.UpdateSiteInput{Name: "Renamed"}); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected viewers to be read-only, got %v", err)
+	}
+
+	if _, err := service.UpdateMemberRole(ctx, created.ID, ownerID, member.ID, site.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Update(ctx, created.ID, member.ID, site.UpdateSiteInput{Name: "Renamed"}); err != nil {
+		t.Fatalf("expected editors to update site settings, got %v", err)
+	}
+	timezone := "Europe/Berlin"
+	if _, err := service.Update(ctx, created.ID, member.ID, site.UpdateSiteInput{Name: "Renamed", Timezone: &timezone}); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected only owners to change the timezone, got %v", err)
+	}
+	if _, err := service.Update(ctx, created.ID, member.ID, site.UpdateSiteInput{
+		Name:    "Renamed",
+		Domains: []string{"example.com", "attacker.example"},
+	}); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected only owners to change domains, got %v", err)
+	}
+	blocked, err := service.Update(ctx, created.ID, member.ID, site.UpdateSiteInput{
+		Name:       "Renamed",
+		Domains:    []string{"Example.com"},
+		BlockedIPs: []string{"203.0.113.7"},
+	})
+	if err != nil {
+		t.Fatalf("expected editors to resend unchanged domains with blocking changes, got %v", err)
+	}
+	if len(blocked.BlockedIPs) != 1 || len(blocked.Domains) != 1 {
+		t.Fatalf("expected blocking update to keep domains, got %+v", blocked)
+	}
+	if _, err := service.Update(ctx, created.ID, ownerID, site.UpdateSiteInput{Name: "Renamed", Timezone: &timezone}); err != nil {
+		t.Fatalf("expected owners to change the timezone, got %v", err)
+	}
+	if _, err := service.RegeneratePublicKey(ctx, created.ID, member.ID); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected only owners to regenerate keys, got %v", err)
+	}
+	if err := service.Delete(ctx, created.ID, member.ID); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected only owners to delete sites, got %v", err)
+	}
+
+	if err := service.RemoveMember(ctx, created.ID, member.ID, ownerID); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected editors not to remove other members, got %v", err)
+	}
+	if err := service.RemoveMember(ctx, created.ID, member.ID, member.ID); err != nil {
+		t.Fatalf("expected members to leave a site, got %v", err)
+	}
+	if err := service.RequireRole(ctx, created.ID, member.ID, site.RoleViewer); !errors.Is(err, site.ErrNotAuthorized) {
+		t.Fatalf("expected removed member to lose access, got %v", err)
+	}
+}
+
+func TestSiteServiceScopesUserSitesBeforePaging(t *testing.T) {
+	service, _, ownerID := newSiteServiceTest(t)
+	ctx := context.Background()
+	var ids []int64
+	for _, domain := range []string{"a.example", "b.example", "c.example"} {
+		created, err := service.Create(ctx, site.CreateSiteInput{Domains: []string{domain}, Name: domain, UserID: ownerID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	sites, err := service.GetUserSites(ctx, ownerID, []int64{ids[1], ids[2]}, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sites) != 1 || sites[0].ID != ids[2] {
+		t.Fatalf("expected the second scoped site on the second page, got %v", sites)
+	}
+	sites, err = service.GetUserSites(ctx, ownerID, []int64{}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sites) != 0 {
+		t.Fatalf("expected an empty scope to list no sites, got %v", sites)
+	}
+}
+
 func newSiteServiceTest(t *testing.T) (*site.Service, *bun.DB, int64) {
 	t.Helper()
 	sqlDB, err := sql.Open("sqlite", ":memory:")
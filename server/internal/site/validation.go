@@ -2,7 +2,7 @@ package site
 
 import (
 	"errors"
-	"net"
+	"net/netip"
 	"regexp"
 	"strings"
 )
@@ -16,9 +16,11 @@ var (
 
 	ErrSiteNameTooLong = errors.New("site name must be between 1 and 100 characters")
 
-	ErrInvalidIPAddress = errors.New("invalid IP address")
+	ErrInvalidIPAddress = errors.New("invalid IP address or CIDR range")
 
 	ErrInvalidCountryCode = errors.New("invalid country code")
+
+	ErrInvalidTimezone = errors.New("invalid timezone")
 )
 
 // Domain regex pattern for valid domain names.
@@ -67,16 +69,45 @@ func ValidateSiteName(name string) (string, error) {
 	return name, nil
 }
 
+// ValidateIPAddress normalizes a blocked IP entry, which is a single address or a CIDR range.
+// Ranges lose their host bits, and single-address ranges such as /32 are stored as addresses.
 func ValidateIPAddress(ip string) (string, error) {
-	ip = strings.TrimSpace(ip)
-	if ip == "" {
-		return "", ErrInvalidIPAddress
+	prefix, err := ParseIPPrefix(ip)
+	if err != nil {
+		return "", err
+	}
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String(), nil
+	}
+	return prefix.String(), nil
+}
+
+// ParseIPPrefix parses a blocked IP entry into a masked prefix. A single address becomes a
+// full-length prefix, and IPv4-mapped IPv6 values become IPv4 so that they match IPv4 clients.
+func ParseIPPrefix(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil || addr.Zone() != "" {
+			return netip.Prefix{}, ErrInvalidIPAddress
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, ErrInvalidIPAddress
 	}
-	parsed := net.ParseIP(ip)
-	if parsed == nil {
-		return "", ErrInvalidIPAddress
+	addr, bits := prefix.Addr(), prefix.Bits()
+	if addr.Is4In6() {
+		const mappedPrefixBits = 96
+		if bits < mappedPrefixBits {
+			return netip.Prefix{}, ErrInvalidIPAddress
+		}
+		addr, bits = addr.Unmap(), bits-mappedPrefixBits
 	}
-	return parsed.String(), nil
+	return netip.PrefixFrom(addr, bits).Masked(), nil
 }
 
 func ValidateCountryCode(code string) (string, error) {
@@ -86,3 +117,19 @@ func ValidateCountryCode(code string) (string, error) {
 	}
 	return code, nil
 }
+
+// ValidateTimezone accepts an IANA zone name; an empty value means UTC.
+func ValidateTimezone(timezone string) (string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return "UTC", nil
+	}
+	// LoadLocation also accepts "Local" and relative paths, neither of which is portable.
+	if len(timezone) > 64 || timezone == "Local" || strings.Contains(timezone, "..") {
+		return "", ErrInvalidTimezone
+	}
+	if _, err := loadLocation(timezone); err != nil {
+		return "", ErrInvalidTimezone
+	}
+	return timezone, nil
+}
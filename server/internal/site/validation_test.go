@@ -3,6 +3,7 @@ package site
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestValidateDomain(t *testing.T) {
@@ -230,3 +231,82 @@ func TestValidateSiteName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIPAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantError error
+	}{
+		{name: "IPv4 address", input: " 203.0.113.10 ", want: "203.0.113.10"},
+		{name: "IPv6 address", input: "2001:DB8::1", want: "2001:db8::1"},
+		{name: "IPv4-mapped address", input: "::ffff:203.0.113.10", want: "203.0.113.10"},
+		{name: "IPv4 range", input: "10.0.0.0/8", want: "10.0.0.0/8"},
+		{name: "range with host bits", input: "192.168.1.77/24", want: "192.168.1.0/24"},
+		{name: "IPv6 prefix", input: "2001:db8:abcd::/48", want: "2001:db8:abcd::/48"},
+		{name: "IPv4-mapped range", input: "::ffff:10.0.0.0/104", want: "10.0.0.0/8"},
+		{name: "single-address range", input: "203.0.113.10/32", want: "203.0.113.10"},
+		{name: "empty", input: " ", wantError: ErrInvalidIPAddress},
+		{name: "hostname", input: "example.com", wantError: ErrInvalidIPAddress},
+		{name: "prefix too long", input: "10.0.0.0/33", wantError: ErrInvalidIPAddress},
+		{name: "missing prefix length", input: "10.0.0.0/", wantError: ErrInvalidIPAddress},
+		{name: "zoned address", input: "fe80::1%eth0", wantError: ErrInvalidIPAddress},
+		{name: "IPv4-mapped range wider than IPv4", input: "::ffff:0.0.0.0/80", wantError: ErrInvalidIPAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateIPAddress(tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("ValidateIPAddress() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ValidateIPAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantError error
+	}{
+		{name: "empty defaults to UTC", input: "", want: "UTC"},
+		{name: "IANA zone", input: " America/Los_Angeles ", want: "America/Los_Angeles"},
+		{name: "unknown zone", input: "Mars/Olympus_Mons", wantError: ErrInvalidTimezone},
+		{name: "local zone", input: "Local", wantError: ErrInvalidTimezone},
+		{name: "path traversal", input: "../etc/passwd", wantError: ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateTimezone(tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("ValidateTimezone() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ValidateTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSiteLocationReusesResolvedZone(t *testing.T) {
+	site := &Site{Timezone: "Europe/Berlin"}
+	first := site.Location()
+	if first.String() != "Europe/Berlin" {
+		t.Fatalf("Location() = %v, want Europe/Berlin", first)
+	}
+	if second := site.Location(); second != first {
+		t.Errorf("Location() resolved the zone again instead of reusing it")
+	}
+	if got := (&Site{Timezone: "Mars/Olympus_Mons"}).Location(); got != time.UTC {
+		t.Errorf("Location() = %v, want UTC for an unknown zone", got)
+	}
+}
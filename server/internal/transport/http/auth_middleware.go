@@ -3,49 +3,176 @@ package http
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/transport/http/clientip"
 )
 
+// proxyAuthProvider is the ExternalIdentity provider of users named by a trusted proxy header.
+const proxyAuthProvider = "proxy"
+
 type tokenService interface {
-	ValidateAccessToken(token string) (*auth.Claims, error)
+	ValidateAccessToken(ctx context.Context, token string) (*auth.Claims, error)
 	RefreshTokens(ctx context.Context, refreshToken string) (*auth.Tokens, error)
 }
 
+type apiTokenService interface {
+	Authenticate(ctx context.Context, secret string) (*auth.Claims, error)
+}
+
+type shareLinkService interface {
+	Authenticate(ctx context.Context, secret, key string) (*auth.Claims, error)
+}
+
+// Share link visitors send the link secret, plus the unlock key for password-protected links.
+const (
+	shareTokenHeader = "X-Share-Token"
+	shareKeyHeader   = "X-Share-Key"
+)
+
 type authMiddleware struct {
-	service tokenService
-	cookies *CookieManager
+	service    tokenService
+	apiTokens  apiTokenService
+	shareLinks shareLinkService
+	cookies    *CookieManager
+	// proxyAuth is nil unless reverse-proxy header authentication is enabled.
+	proxyAuth *proxyAuth
+}
+
+// proxyAuth trusts a user header set by an authenticating reverse proxy such as oauth2-proxy
+// or Authelia, but only on requests whose direct peer is a trusted proxy.
+type proxyAuth struct {
+	header   string
+	resolver *clientip.Resolver
+	logins   externalLoginService
 }
 
-func newAuthMiddleware(service tokenService, cookies *CookieManager) *authMiddleware {
-	return &authMiddleware{service: service, cookies: cookies}
+func newProxyAuth(header string, resolver *clientip.Resolver, logins externalLoginService) *proxyAuth {
+	return &proxyAuth{header: header, resolver: resolver, logins: logins}
 }
 
-// authenticate extracts and validates HttpOnly cookie authentication.
+// username returns the proxy-asserted user, or an empty string when the request did not come
+// through a trusted proxy.
+func (p *proxyAuth) username(r *http.Request) string {
+	if p == nil || !p.resolver.IsTrustedRemote(r.RemoteAddr) {
+		return ""
+	}
+	return strings.TrimSpace(r.Header.Get(p.header))
+}
+
+func newAuthMiddleware(
+	service tokenService,
+	apiTokens apiTokenService,
+	shareLinks shareLinkService,
+	cookies *CookieManager,
+) *authMiddleware {
+	return &authMiddleware{service: service, apiTokens: apiTokens, shareLinks: shareLinks, cookies: cookies}
+}
+
+// authenticate extracts and validates a bearer API token, a share link or HttpOnly cookie authentication.
 func (m *authMiddleware) authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		access, refresh := m.cookies.TokensFromRequest(r)
-		if access == "" {
-			next.ServeHTTP(w, r)
+		if secret, ok := bearerToken(r); ok {
+			// An explicit bearer credential never falls back to cookies, so a bad token stays unauthenticated.
+			claims, err := m.apiTokens.Authenticate(r.Context(), secret)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
 			return
 		}
-
-		claims, err := m.service.ValidateAccessToken(access)
-		if err != nil {
-			if errors.Is(err, auth.ErrExpiredToken) && refresh != "" {
-				if tokens, refreshErr := m.service.RefreshTokens(r.Context(), refresh); refreshErr == nil {
-					m.cookies.SetAuthCookies(w, tokens)
-					claims, _ = m.service.ValidateAccessToken(tokens.AccessToken)
-				}
-			}
-			if claims == nil {
+		if secret := strings.TrimSpace(r.Header.Get(shareTokenHeader)); secret != "" {
+			// A share link visitor sees only the shared dashboard, even when signed in.
+			claims, err := m.shareLinks.Authenticate(r.Context(), secret, r.Header.Get(shareKeyHeader))
+			if err != nil {
 				next.ServeHTTP(w, r)
 				return
 			}
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
+			return
+		}
+
+		claims := m.cookieClaims(w, r)
+		if proxyUser := m.proxyAuth.username(r); proxyUser != "" && (claims == nil || claims.Username != proxyUser) {
+			claims = m.proxyLogin(w, r, proxyUser, claims != nil)
+		}
+		if claims == nil {
+			next.ServeHTTP(w, r)
+			return
 		}
 
 		ctx := auth.ContextWithClaims(r.Context(), claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// cookieClaims validates the access cookie, refreshing it when it has expired.
+func (m *authMiddleware) cookieClaims(w http.ResponseWriter, r *http.Request) *auth.Claims {
+	access, refresh := m.cookies.TokensFromRequest(r)
+	if access == "" {
+		return nil
+	}
+
+	claims, err := m.service.ValidateAccessToken(r.Context(), access)
+	if err == nil {
+		return claims
+	}
+	if errors.Is(err, auth.ErrInvalidToken) {
+		// The session was revoked, for example by a password change on another device.
+		m.cookies.ClearAuthCookies(w)
+		return nil
+	}
+	if !errors.Is(err, auth.ErrExpiredToken) || refresh == "" {
+		return nil
+	}
+	tokens, refreshErr := m.service.RefreshTokens(r.Context(), refresh)
+	switch {
+	case refreshErr == nil:
+		m.cookies.SetAuthCookies(w, tokens)
+		claims, _ = m.service.ValidateAccessToken(r.Context(), tokens.AccessToken)
+		return claims
+	case errors.Is(refreshErr, auth.ErrInvalidToken), errors.Is(refreshErr, auth.ErrRefreshTokenReused):
+		// The session was revoked or expired, so drop cookies that can never refresh again.
+		m.cookies.ClearAuthCookies(w)
+	}
+	return nil
+}
+
+// proxyLogin starts a session for the proxy-asserted user, provisioning the account on first
+// use. The session cookies let later requests skip this until the proxy names another user.
+func (m *authMiddleware) proxyLogin(w http.ResponseWriter, r *http.Request, username string, hadSession bool) *auth.Claims {
+	_, tokens, err := m.proxyAuth.logins.LoginExternal(r.Context(), auth.ExternalIdentity{
+		Provider: proxyAuthProvider,
+		Subject:  username,
+		Username: username,
+		// The proxy authenticates usernames, so it speaks for local accounts of the same name.
+		LinkExistingUser: true,
+	}, r.UserAgent())
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to sign in proxy-authenticated user", "error", err)
+		if hadSession {
+			// The cookies belong to a different user than the proxy vouches for.
+			m.cookies.ClearAuthCookies(w)
+		}
+		return nil
+	}
+	m.cookies.SetAuthCookies(w, tokens)
+	claims, err := m.service.ValidateAccessToken(r.Context(), tokens.AccessToken)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
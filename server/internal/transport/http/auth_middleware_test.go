@@ -0,0 +1,263 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lovely-eye/server/internal/auth"
+	authpersistence "github.com/lovely-eye/server/internal/auth/persistence"
+	"github.com/lovely-eye/server/internal/platform/database"
+	"github.com/lovely-eye/server/internal/transport/http/clientip"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	_ "modernc.org/sqlite"
+)
+
+type stubTokenService struct {
+	claims *auth.Claims
+}
+
+func (s stubTokenService) ValidateAccessToken(context.Context, string) (*auth.Claims, error) {
+	return s.claims, nil
+}
+
+func (s stubTokenService) RefreshTokens(context.Context, string) (*auth.Tokens, error) {
+	return nil, errors.New("not supported")
+}
+
+type stubAPITokenService struct {
+	secret string
+	claims *auth.Claims
+}
+
+func (s stubAPITokenService) Authenticate(_ context.Context, secret string) (*auth.Claims, error) {
+	if secret != s.secret {
+		return nil, errors.New("invalid API token")
+	}
+	return s.claims, nil
+}
+
+type stubShareLinkService struct {
+	secret string
+	key    string
+	claims *auth.Claims
+}
+
+func (s stubShareLinkService) Authenticate(_ context.Context, secret, key string) (*auth.Claims, error) {
+	if secret != s.secret || key != s.key {
+		return nil, errors.New("invalid share link")
+	}
+	return s.claims, nil
+}
+
+func TestAuthMiddlewarePrefersBearerAPITokens(t *testing.T) {
+	cookies := newCookieTestManager("/")
+	session := &auth.Claims{UserID: 1, Username: "session"}
+	token := &auth.Claims{UserID: 1, Username: "token", APITokenID: 7}
+	middleware := newAuthMiddleware(
+		stubTokenService{claims: session},
+		stubAPITokenService{secret: "le_valid", claims: token},
+		stubShareLinkService{},
+		cookies,
+	)
+
+	var seen *auth.Claims
+	handler := middleware.authenticate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = auth.GetUserFromContext(r.Context())
+	}))
+	serve := func(authorization string) *auth.Claims {
+		seen = nil
+		request := httptest.NewRequest("POST", "/graphql", nil)
+		recorder := httptest.NewRecorder()
+		cookies.SetAuthCookies(recorder, &auth.Tokens{AccessToken: "access", RefreshToken: "refresh"})
+		for _, cookie := range recorder.Result().Cookies() {
+			request.AddCookie(cookie)
+		}
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		return seen
+	}
+
+	require.Same(t, session, serve(""))
+	require.Same(t, token, serve("Bearer le_valid"))
+	require.Nil(t, serve("bearer le_invalid"))
+}
+
+func TestAuthMiddlewareReplacesSessionWithShareLink(t *testing.T) {
+	cookies := newCookieTestManager("/")
+	session := &auth.Claims{UserID: 1, Username: "session"}
+	shared := &auth.Claims{ShareLinkID: 3, SiteIDs: []int64{5}}
+	middleware := newAuthMiddleware(
+		stubTokenService{claims: session},
+		stubAPITokenService{},
+		stubShareLinkService{secret: "les_valid", key: "unlock", claims: shared},
+		cookies,
+	)
+
+	var seen *auth.Claims
+	handler := middleware.authenticate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = auth.GetUserFromContext(r.Context())
+	}))
+	serve := func(secret, key string) *auth.Claims {
+		seen = nil
+		request := httptest.NewRequest("POST", "/graphql", nil)
+		recorder := httptest.NewRecorder()
+		cookies.SetAuthCookies(recorder, &auth.Tokens{AccessToken: "access", RefreshToken: "refresh"})
+		for _, cookie := range recorder.Result().Cookies() {
+			request.AddCookie(cookie)
+		}
+		request.Header.Set(shareTokenHeader, secret)
+		request.Header.Set(shareKeyHeader, key)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		return seen
+	}
+
+	require.Same(t, session, serve("", ""))
+	require.Same(t, shared, serve("les_valid", "unlock"))
+	require.Nil(t, serve("les_valid", "wrong"), "a rejected share link never falls back to the session")
+}
+
+func TestAuthMiddlewareRejectsOtherSessionsAfterPasswordChange(t *testing.T) {
+	ctx := context.Background()
+	users := authpersistence.New(setupAuthMiddlewareTestDB(t))
+	service := auth.NewService(users, users, auth.Config{
+		JWTSecret:         strings.Repeat("j", 32),
+		AccessTokenExpiry: 15 * time.Minute,
+		RefreshExpiry:     7 * 24 * time.Hour,
+		AllowRegistration: true,
+	})
+	user, current, err := service.Register(ctx, auth.RegisterInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, auth.LoginInput{Username: "admin", Password: "password123"})
+	require.NoError(t, err)
+
+	cookies := newCookieTestManager("/")
+	middleware := newAuthMiddleware(service, stubAPITokenService{}, stubShareLinkService{}, cookies)
+	var seen *auth.Claims
+	handler := middleware.authenticate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = auth.GetUserFromContext(r.Context())
+	}))
+	serve := func(tokens *auth.Tokens) (*auth.Claims, *httptest.ResponseRecorder) {
+		seen = nil
+		request := httptest.NewRequest("POST", "/graphql", nil)
+		recorder := httptest.NewRecorder()
+		cookies.SetAuthCookies(recorder, tokens)
+		for _, cookie := range recorder.Result().Cookies() {
+			request.AddCookie(cookie)
+		}
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return seen, recorder
+	}
+
+	claims, _ := serve(other.Tokens)
+	require.NotNil(t, claims)
+	claims, _ = serve(current)
+	require.NotNil(t, claims)
+
+	require.NoError(t, service.ChangePassword(ctx, user.ID, claims.SessionID, "password123", "new-password"))
+
+	claims, recorder := serve(other.Tokens)
+	require.Nil(t, claims, "the other session's unexpired access token must stop working")
+	require.NotEmpty(t, recorder.Result().Cookies())
+	for _, cookie := range recorder.Result().Cookies() {
+		require.Negative(t, cookie.MaxAge, "cookies of a revoked session are cleared")
+	}
+	claims, _ = serve(current)
+	require.NotNil(t, claims, "the session that changed the password stays signed in")
+}
+
+func setupAuthMiddlewareTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	sqldb, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	require.NoError(t, database.Migrate(context.Background(), db))
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db
+}
+
+type mapTokenService map[string]*auth.Claims
+
+func (s mapTokenService) ValidateAccessToken(_ context.Context, token string) (*auth.Claims, error) {
+	if claims, ok := s[token]; ok {
+		return claims, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func (s mapTokenService) RefreshTokens(context.Context, string) (*auth.Tokens, error) {
+	return nil, auth.ErrInvalidToken
+}
+
+type countingLoginService struct {
+	logins []auth.ExternalIdentity
+}
+
+func (s *countingLoginService) LoginExternal(
+	_ context.Context,
+	identity auth.ExternalIdentity,
+	_ string,
+) (*auth.User, *auth.Tokens, error) {
+	s.logins = append(s.logins, identity)
+	return &auth.User{Username: identity.Username}, &auth.Tokens{AccessToken: identity.Username + "-access"}, nil
+}
+
+func TestAuthMiddlewareTrustsProxyUserHeaderOnlyFromTrustedProxies(t *testing.T) {
+	cookies := newCookieTestManager("/")
+	tokens := mapTokenService{
+		"alice-access": {UserID: 1, Username: "alice"},
+		"bob-access":   {UserID: 2, Username: "bob"},
+	}
+	logins := &countingLoginService{}
+	middleware := newAuthMiddleware(tokens, stubAPITokenService{}, stubShareLinkService{}, cookies)
+	middleware.proxyAuth = newProxyAuth("Remote-User", clientip.MustNewResolver([]string{"10.0.0.0/8"}), logins)
+
+	var seen *auth.Claims
+	handler := middleware.authenticate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = auth.GetUserFromContext(r.Context())
+	}))
+	serve := func(remoteAddr, user string, session []*http.Cookie) *httptest.ResponseRecorder {
+		seen = nil
+		request := httptest.NewRequest("POST", "/graphql", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("Remote-User", user)
+		for _, cookie := range session {
+			request.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	serve("203.0.113.5:4000", "alice", nil)
+	require.Nil(t, seen, "an untrusted peer cannot assert a user")
+	require.Empty(t, logins.logins)
+
+	first := serve("10.1.2.3:4000", "alice", nil)
+	require.Equal(t, "alice", seen.Username)
+	require.Equal(t, []auth.ExternalIdentity{{
+		Provider: proxyAuthProvider, Subject: "alice", Username: "alice", LinkExistingUser: true,
+	}}, logins.logins)
+
+	session := first.Result().Cookies()
+	serve("10.1.2.3:4000", "alice", session)
+	require.Equal(t, "alice", seen.Username)
+	require.Len(t, logins.logins, 1, "the session cookie is reused while the proxy user is unchanged")
+
+	serve("10.1.2.3:4000", "bob", session)
+	require.Equal(t, "bob", seen.Username)
+	require.Len(t, logins.logins, 2)
+}
@@ -55,6 +55,13 @@ func (r *Resolver) GetClientIP(xForwardedFor, xRealIP, remoteAddr string) string
 	return remoteIP.String()
 }
 
+// IsTrustedRemote reports whether the direct peer is a trusted proxy, which makes the
+// headers it sets trustworthy.
+func (r *Resolver) IsTrustedRemote(remoteAddr string) bool {
+	remoteIP, ok := parseIP(hostFromRemoteAddr(remoteAddr))
+	return ok && r.isTrusted(remoteIP)
+}
+
 func parseTrustedPrefix(value string) (netip.Prefix, error) {
 	if strings.Contains(value, "/") {
 		prefix, err := netip.ParsePrefix(value)
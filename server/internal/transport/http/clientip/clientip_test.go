@@ -43,3 +43,12 @@ func TestNewResolverRejectsInvalidCIDR(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestResolverTrustsOnlyConfiguredRemotes(t *testing.T) {
+	resolver := MustNewResolver([]string{"10.0.0.0/8", "::1"})
+
+	require.True(t, resolver.IsTrustedRemote("10.1.2.3:12345"))
+	require.True(t, resolver.IsTrustedRemote("[::1]:8080"))
+	require.False(t, resolver.IsTrustedRemote("203.0.113.5:12345"))
+	require.False(t, resolver.IsTrustedRemote("not-an-ip"))
+}
@@ -25,6 +25,7 @@ type AnalyticsHandler struct {
 type AnalyticsHandlerConfig struct {
 	MaxBodyBytes       int64
 	MaxPropertiesBytes int
+	MaxBatchItems      int
 }
 
 func NewAnalyticsHandler(
@@ -40,6 +41,9 @@ func NewAnalyticsHandler(
 	if config.MaxPropertiesBytes <= 0 {
 		config.MaxPropertiesBytes = 8 * 1024
 	}
+	if config.MaxBatchItems <= 0 {
+		config.MaxBatchItems = 50
+	}
 	if ipResolver == nil {
 		ipResolver = clientip.MustNewResolver(nil)
 	}
@@ -61,6 +65,11 @@ type collectRequest struct {
 	UTMSource   string `json:"utm_source"`
 	UTMMedium   string `json:"utm_medium"`
 	UTMCampaign string `json:"utm_campaign"`
+	UTMTerm     string `json:"utm_term"`
+	UTMContent  string `json:"utm_content"`
+	// ViewportWidth is an optional, untrusted hint of the viewport width in CSS pixels. The tracker
+	// rounds it down to a screen size breakpoint before sending it.
+	ViewportWidth int `json:"viewport_width"`
 }
 
 const (
@@ -69,6 +78,9 @@ const (
 	maxUTMSourceLength   = 128
 	maxUTMMediumLength   = 128
 	maxUTMCampaignLength = 256
+	maxUTMTermLength     = 256
+	maxUTMContentLength  = 256
+	maxViewportWidth     = 16384
 )
 
 func (h *AnalyticsHandler) Collect(w http.ResponseWriter, r *http.Request) {
@@ -103,41 +115,8 @@ func (h *AnalyticsHandler) Collect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	r.Body = http.MaxBytesReader(w, r.Body, h.config.MaxBodyBytes)
 	var req collectRequest
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&req); err != nil {
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) {
-			respondError(w, http.StatusRequestEntityTooLarge, "request body is too large")
-			return
-		}
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Properties != "" {
-		if len(req.Properties) > h.config.MaxPropertiesBytes {
-			respondError(w, http.StatusRequestEntityTooLarge, "properties are too large")
-			return
-		}
-		var props map[string]interface{}
-		if err := json.Unmarshal([]byte(req.Properties), &props); err != nil || props == nil {
-			respondError(w, http.StatusBadRequest, "properties must be a JSON object")
-			return
-		}
-	}
-
-	if req.Path == "" {
-		respondError(w, http.StatusBadRequest, "path is required")
-		return
-	}
-	if exceedsCollectPersistenceLimits(req) {
-		respondError(w, http.StatusBadRequest, "request field is too long")
+	if !decodeCollectBody(w, r, h.config.MaxBodyBytes, &req) || !validateCollectRequest(w, req, h.config) {
 		return
 	}
 
@@ -153,16 +132,19 @@ func (h *AnalyticsHandler) Collect(w http.ResponseWriter, r *http.Request) {
 		})
 	} else {
 		err = h.analyticsService.CollectPageViewForSite(r.Context(), site, analytics.CollectInput{
-			Path:        req.Path,
-			Exit:        req.Exit,
-			Referrer:    req.Referrer,
-			UserAgent:   r.UserAgent(),
-			IP:          ip,
-			Origin:      r.Header.Get("Origin"),
-			Referer:     r.Header.Get("Referer"),
-			UTMSource:   req.UTMSource,
-			UTMMedium:   req.UTMMedium,
-			UTMCampaign: req.UTMCampaign,
+			Path:          req.Path,
+			Exit:          req.Exit,
+			Referrer:      req.Referrer,
+			UserAgent:     r.UserAgent(),
+			IP:            ip,
+			Origin:        r.Header.Get("Origin"),
+			Referer:       r.Header.Get("Referer"),
+			UTMSource:     req.UTMSource,
+			UTMMedium:     req.UTMMedium,
+			UTMCampaign:   req.UTMCampaign,
+			UTMTerm:       req.UTMTerm,
+			UTMContent:    req.UTMContent,
+			ViewportWidth: req.ViewportWidth,
 		})
 	}
 
@@ -174,12 +156,67 @@ func (h *AnalyticsHandler) Collect(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// decodeCollectBody reads exactly one JSON value into target and writes the error response when it cannot.
+func decodeCollectBody(w http.ResponseWriter, r *http.Request, maxBytes int64, target any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(target); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+			return false
+		}
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// validateCollectRequest applies the shape and size rules shared by every collect endpoint.
+func validateCollectRequest(w http.ResponseWriter, req collectRequest, config AnalyticsHandlerConfig) bool {
+	if status, message := collectRequestError(req, config); status != 0 {
+		respondError(w, status, message)
+		return false
+	}
+	return true
+}
+
+// collectRequestError returns the status and message that reject req, or a zero status when it is valid.
+func collectRequestError(req collectRequest, config AnalyticsHandlerConfig) (int, string) {
+	if req.Properties != "" {
+		if len(req.Properties) > config.MaxPropertiesBytes {
+			return http.StatusRequestEntityTooLarge, "properties are too large"
+		}
+		var props map[string]interface{}
+		if err := json.Unmarshal([]byte(req.Properties), &props); err != nil || props == nil {
+			return http.StatusBadRequest, "properties must be a JSON object"
+		}
+	}
+
+	if req.Path == "" {
+		return http.StatusBadRequest, "path is required"
+	}
+	if req.ViewportWidth < 0 || req.ViewportWidth > maxViewportWidth {
+		return http.StatusBadRequest, "viewport_width is out of range"
+	}
+	if exceedsCollectPersistenceLimits(req) {
+		return http.StatusBadRequest, "request field is too long"
+	}
+	return 0, ""
+}
+
 func exceedsCollectPersistenceLimits(req collectRequest) bool {
 	return utf8.RuneCountInString(req.Path) > maxPathLength ||
 		utf8.RuneCountInString(req.Referrer) > maxReferrerLength ||
 		utf8.RuneCountInString(req.UTMSource) > maxUTMSourceLength ||
 		utf8.RuneCountInString(req.UTMMedium) > maxUTMMediumLength ||
-		utf8.RuneCountInString(req.UTMCampaign) > maxUTMCampaignLength
+		utf8.RuneCountInString(req.UTMCampaign) > maxUTMCampaignLength ||
+		utf8.RuneCountInString(req.UTMTerm) > maxUTMTermLength ||
+		utf8.RuneCountInString(req.UTMContent) > maxUTMContentLength
 }
 
 func (h *AnalyticsHandler) handleAnalyticsPreflight(w http.ResponseWriter, r *http.Request) {
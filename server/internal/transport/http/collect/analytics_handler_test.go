@@ -145,6 +145,8 @@ func TestAnalyticsHandlerCollectRejectsValuesBeyondPersistenceLimits(t *testing.
 		`{"path":"/","utm_source":"` + strings.Repeat("s", 129) + `"}`,
 		`{"path":"/","utm_medium":"` + strings.Repeat("m", 129) + `"}`,
 		`{"path":"/","utm_campaign":"` + strings.Repeat("c", 257) + `"}`,
+		`{"path":"/","utm_term":"` + strings.Repeat("t", 257) + `"}`,
+		`{"path":"/","utm_content":"` + strings.Repeat("c", 257) + `"}`,
 	}
 	for _, body := range tests {
 		recorder := httptest.NewRecorder()
@@ -153,6 +155,32 @@ func TestAnalyticsHandlerCollectRejectsValuesBeyondPersistenceLimits(t *testing.
 	}
 }
 
+func TestAnalyticsHandlerCollectBucketsViewportWidth(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+
+	for _, body := range []string{`{"path":"/","viewport_width":-1}`, `{"path":"/","viewport_width":16385}`} {
+		rec := httptest.NewRecorder()
+		fixture.handler.Collect(rec, newAnalyticsCollectRequest(fixture.site.PublicKey, body))
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	rec := httptest.NewRecorder()
+	fixture.handler.Collect(rec, newAnalyticsCollectRequest(fixture.site.PublicKey, `{"path":"/pricing","viewport_width":800}`))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	var screenSize analyticspersistence.ClientScreenSize
+	err := fixture.db.NewSelect().
+		Model((*analyticspersistence.Client)(nil)).
+		Column("screen_size").
+		Where("site_id = ?", fixture.site.ID).
+		Scan(context.Background(), &screenSize)
+	require.NoError(t, err)
+	require.Equal(t, analyticspersistence.ClientScreenSizeMD, screenSize)
+}
+
 func TestAnalyticsHandlerCollectHonorsForwardedIPFromTrustedRemote(t *testing.T) {
 	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
 		MaxBodyBytes:       4096,
@@ -189,6 +217,25 @@ func TestAnalyticsHandlerCollectIgnoresForwardedIPFromUntrustedRemote(t *testing
 	require.Equal(t, 1, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
 }
 
+func TestAnalyticsHandlerCollectDropsClientsInBlockedRanges(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, clientip.MustNewResolver(nil))
+	insertAnalyticsHandlerBlockedIP(t, fixture.db, fixture.site.ID, "198.51.100.0/24")
+	insertAnalyticsHandlerBlockedIP(t, fixture.db, fixture.site.ID, "2001:db8:abcd::/48")
+
+	for _, remoteAddr := range []string{"198.51.100.77:12345", "[2001:db8:abcd:12::1]:12345", "198.51.101.1:12345", "[2001:db8:abce::1]:12345"} {
+		req := newAnalyticsCollectRequest(fixture.site.PublicKey, `{"path":"/pricing"}`)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		fixture.handler.Collect(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+	}
+
+	require.Equal(t, 2, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID), "only the addresses outside both ranges are counted")
+}
+
 func TestAnalyticsHandlerCollectLoadsSiteOnce(t *testing.T) {
 	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
 		MaxBodyBytes:       4096,
@@ -0,0 +1,184 @@
+package collect
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/lovely-eye/server/internal/analytics"
+)
+
+const errRateLimitExceeded = "rate limit exceeded"
+
+// batchItemResult reports the outcome of one batch item. Items rejected by the visitor rules, such
+// as bot filtering or IP blocking, are not accepted and carry no error.
+type batchItemResult struct {
+	Accepted bool   `json:"accepted"`
+	Error    string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Items []batchItemResult `json:"items"`
+}
+
+// CollectBatch accepts an array of collect requests from one visitor. Every item consumes the
+// same rate limit tokens as a single collect request and is validated on its own, and the
+// accepted items are recorded in one transaction. Per-item results are never returned, because
+// they would tell an anonymous caller whether its visitor or IP is blocked.
+func (h *AnalyticsHandler) CollectBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		h.handleAnalyticsPreflight(w, r)
+		return
+	}
+
+	siteKey := strings.TrimSpace(r.URL.Query().Get("site_key"))
+	if siteKey == "" {
+		respondError(w, http.StatusBadRequest, "site_key query parameter is required")
+		return
+	}
+
+	// The per-IP limit is checked before the body is read, as for a single collect request, so an
+	// exhausted client cannot make the server decode a full batch.
+	ip := h.clientIP(r)
+	if !h.allowCollect("ip|" + ip) {
+		respondError(w, http.StatusTooManyRequests, errRateLimitExceeded)
+		return
+	}
+
+	var rawItems []json.RawMessage
+	if !decodeCollectBody(w, r, batchBodyBytes(h.config), &rawItems) || !validateBatchSize(w, rawItems, h.config) {
+		return
+	}
+
+	results := make([]batchItemResult, len(rawItems))
+	// The first item uses the token taken above.
+	for i := 1; i < len(rawItems); i++ {
+		if !h.allowCollect("ip|" + ip) {
+			results[i].Error = errRateLimitExceeded
+		}
+	}
+
+	site, err := h.loadAnalyticsSite(r, siteKey)
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !h.applyAnalyticsCORSForSite(w, r, site) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var items []analytics.BatchItem
+	var indexes []int
+	for i, raw := range rawItems {
+		if results[i].Error != "" {
+			continue
+		}
+		if !h.allowCollect("site|" + site.PublicKey + "|ip|" + ip) {
+			results[i].Error = errRateLimitExceeded
+			continue
+		}
+		var req collectRequest
+		if message := decodeBatchItem(raw, h.config, &req); message != "" {
+			results[i].Error = message
+			continue
+		}
+		if _, message := collectRequestError(req, h.config); message != "" {
+			results[i].Error = message
+			continue
+		}
+		items = append(items, req.batchItem())
+		indexes = append(indexes, i)
+	}
+
+	if len(items) > 0 {
+		accepted, err := h.analyticsService.CollectBatchForSite(r.Context(), site, analytics.Visitor{
+			UserAgent: r.UserAgent(),
+			IP:        ip,
+			Origin:    r.Header.Get("Origin"),
+			Referer:   r.Header.Get("Referer"),
+		}, items)
+		recordBatchResults(results, indexes, accepted, err)
+	}
+
+	respondBatchStatus(w, results, http.StatusNoContent)
+}
+
+// batchBodyBytes allows every item of a full batch to use the single request body limit.
+func batchBodyBytes(config AnalyticsHandlerConfig) int64 {
+	return config.MaxBodyBytes * int64(config.MaxBatchItems)
+}
+
+func validateBatchSize(w http.ResponseWriter, rawItems []json.RawMessage, config AnalyticsHandlerConfig) bool {
+	if len(rawItems) == 0 || len(rawItems) > config.MaxBatchItems {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("batch must contain between 1 and %d items", config.MaxBatchItems))
+		return false
+	}
+	return true
+}
+
+// decodeBatchItem unmarshals one batch item into target, returning the message that rejects it.
+func decodeBatchItem(raw json.RawMessage, config AnalyticsHandlerConfig, target any) string {
+	if int64(len(raw)) > config.MaxBodyBytes {
+		return "request body is too large"
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return "Invalid request body"
+	}
+	return ""
+}
+
+func (req collectRequest) batchItem() analytics.BatchItem {
+	return analytics.BatchItem{
+		Name:          req.Name,
+		Path:          req.Path,
+		Properties:    req.Properties,
+		Referrer:      req.Referrer,
+		Exit:          req.Exit,
+		UTMSource:     req.UTMSource,
+		UTMMedium:     req.UTMMedium,
+		UTMCampaign:   req.UTMCampaign,
+		UTMTerm:       req.UTMTerm,
+		UTMContent:    req.UTMContent,
+		ViewportWidth: req.ViewportWidth,
+	}
+}
+
+// recordBatchResults copies the acceptance of the collected items back to their batch positions.
+func recordBatchResults(results []batchItemResult, indexes []int, accepted []bool, err error) {
+	for i, index := range indexes {
+		if err != nil {
+			results[index].Error = "failed to record analytics"
+			continue
+		}
+		results[index].Accepted = accepted[i]
+	}
+}
+
+// respondBatch writes the per-item results when the debug query parameter is set. Otherwise it
+// writes status, or 429 when the rate limits rejected every item. Only authenticated endpoints use
+// it.
+func respondBatch(w http.ResponseWriter, r *http.Request, results []batchItemResult, status int) {
+	if r.URL.Query().Get("debug") == "1" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(batchResponse{Items: results}); err != nil {
+			slog.WarnContext(r.Context(), "failed to write collect batch response", "error", err)
+		}
+		return
+	}
+	respondBatchStatus(w, results, status)
+}
+
+// respondBatchStatus writes status, or 429 when the rate limits rejected every item.
+func respondBatchStatus(w http.ResponseWriter, results []batchItemResult, status int) {
+	for _, result := range results {
+		if result.Error != errRateLimitExceeded {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	respondError(w, http.StatusTooManyRequests, errRateLimitExceeded)
+}
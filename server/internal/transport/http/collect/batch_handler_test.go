@@ -0,0 +1,141 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsHandlerCollectBatchHidesItemResults(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+	req := newAnalyticsCollectBatchRequest(fixture.site.PublicKey+"&debug=1", `[
+		{"path":"/pricing"},
+		{"name":"checkout_failed"},
+		{"name":"undefined_event","path":"/checkout"},
+		"not an object",
+		{"path":"/docs"}
+	]`)
+	rec := httptest.NewRecorder()
+
+	fixture.handler.CollectBatch(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Equal(t, 2, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func TestAnalyticsHandlerCollectBatchRecordsOneVisitorSession(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+	req := newAnalyticsCollectBatchRequest(fixture.site.PublicKey, `[{"path":"/pricing"},{"path":"/docs"},{"path":"/docs","exit":true}]`)
+	rec := httptest.NewRecorder()
+
+	fixture.handler.CollectBatch(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Equal(t, 2, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+	sessions, err := fixture.db.NewSelect().
+		Model((*analyticspersistence.Session)(nil)).
+		Where("site_id = ?", fixture.site.ID).
+		Count(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, sessions)
+}
+
+func TestAnalyticsHandlerCollectBatchRejectsEmptyAndOversizedBatches(t *testing.T) {
+	handler, site := newAnalyticsHandlerTestFixture(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+		MaxBatchItems:      2,
+	}, nil)
+
+	for _, body := range []string{`[]`, `{"path":"/pricing"}`, `[{"path":"/a"},{"path":"/b"},{"path":"/c"}]`} {
+		rec := httptest.NewRecorder()
+		handler.CollectBatch(rec, newAnalyticsCollectBatchRequest(site.PublicKey, body))
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestAnalyticsHandlerCollectBatchRateLimitsEachItem(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, NewRateLimiter(true, 1, 2), nil)
+
+	rec1 := httptest.NewRecorder()
+	fixture.handler.CollectBatch(rec1, newAnalyticsCollectBatchRequest(fixture.site.PublicKey, `[{"path":"/a"},{"path":"/b"},{"path":"/c"}]`))
+	require.Equal(t, http.StatusNoContent, rec1.Code)
+	require.Equal(t, 2, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+
+	// An exhausted client is refused before its body is decoded.
+	rec2 := httptest.NewRecorder()
+	fixture.handler.CollectBatch(rec2, newAnalyticsCollectBatchRequest(fixture.site.PublicKey, `not json`))
+	require.Equal(t, http.StatusTooManyRequests, rec2.Code)
+}
+
+func TestIngestHandlerIngestBatchRecordsEachVisitor(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+	req := newIngestRequest("Bearer "+fixture.secret, `[
+		{"path":"/checkout","ip":"198.51.100.7","user_agent":"`+ingestTestUserAgent+`"},
+		{"path":"/checkout","ip":"203.0.113.8","user_agent":"`+ingestTestUserAgent+`"},
+		{"path":"/receipt","ip":"198.51.100.7","user_agent":"`+ingestTestUserAgent+`"},
+		{"path":"/receipt","ip":"not-an-ip","user_agent":"`+ingestTestUserAgent+`"}
+	]`)
+	req.URL.Path = "/api/ingest/batch"
+	req.URL.RawQuery = "debug=1"
+	rec := httptest.NewRecorder()
+
+	fixture.handler.IngestBatch(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []batchItemResult{
+		{Accepted: true},
+		{Accepted: true},
+		{Accepted: true},
+		{Error: "ip must be the visitor's IP address"},
+	}, decodeBatchResponse(t, rec).Items)
+	require.Equal(t, 3, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+	sessions, err := fixture.db.NewSelect().
+		Model((*analyticspersistence.Session)(nil)).
+		Where("site_id = ?", fixture.site.ID).
+		Count(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions)
+}
+
+func TestIngestHandlerIngestBatchRequiresKey(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+
+	rec := httptest.NewRecorder()
+	fixture.handler.IngestBatch(rec, newIngestRequest("", `[{"path":"/checkout","ip":"198.51.100.7","user_agent":"`+ingestTestUserAgent+`"}]`))
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Zero(t, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func newAnalyticsCollectBatchRequest(siteKey string, body string) *http.Request {
+	req := newAnalyticsCollectRequest(siteKey, body)
+	req.URL.Path = "/api/collect/batch"
+	return req
+}
+
+func decodeBatchResponse(t *testing.T, rec *httptest.ResponseRecorder) batchResponse {
+	t.Helper()
+
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var response batchResponse
+	require.NoError(t, json.NewDecoder(strings.NewReader(rec.Body.String())).Decode(&response))
+	return response
+}
@@ -0,0 +1,243 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lovely-eye/server/internal/analytics"
+	"github.com/lovely-eye/server/internal/ingestkey"
+	"github.com/lovely-eye/server/internal/site"
+)
+
+const maxUserAgentLength = 1024
+
+type ingestKeyAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*ingestkey.Credential, error)
+}
+
+// IngestHandler accepts page views and events from trusted backends, such as payment webhooks,
+// that cannot run tracker.js. Backends authenticate with a site ingestion key and pass the
+// visitor's IP and user agent explicitly instead of relying on the request's own.
+type IngestHandler struct {
+	analyticsService *analytics.Service
+	siteService      *site.Service
+	keys             ingestKeyAuthenticator
+	config           AnalyticsHandlerConfig
+	rateLimiter      *RateLimiter
+}
+
+func NewIngestHandler(
+	analyticsService *analytics.Service,
+	siteService *site.Service,
+	keys ingestKeyAuthenticator,
+	config AnalyticsHandlerConfig,
+	rateLimiter *RateLimiter,
+) *IngestHandler {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 16 * 1024
+	}
+	if config.MaxPropertiesBytes <= 0 {
+		config.MaxPropertiesBytes = 8 * 1024
+	}
+	if config.MaxBatchItems <= 0 {
+		config.MaxBatchItems = 50
+	}
+	return &IngestHandler{
+		analyticsService: analyticsService,
+		siteService:      siteService,
+		keys:             keys,
+		config:           config,
+		rateLimiter:      rateLimiter,
+	}
+}
+
+type ingestRequest struct {
+	collectRequest
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+func (h *IngestHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	credential, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req ingestRequest
+	if !decodeCollectBody(w, r, h.config.MaxBodyBytes, &req) || !validateCollectRequest(w, req.collectRequest, h.config) {
+		return
+	}
+	visitor, message := ingestVisitor(req)
+	if message != "" {
+		respondError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	if !h.allowIngest(credential, visitor.IP) {
+		respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	site, err := h.siteService.GetByPublicKey(r.Context(), credential.SitePublicKey)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load ingestion site", "site_id", credential.Key.SiteID, "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to record analytics")
+		return
+	}
+
+	if req.Name != "" {
+		err = h.analyticsService.CollectServerEvent(r.Context(), site, analytics.EventInput{
+			Name:       req.Name,
+			Path:       req.Path,
+			Properties: req.Properties,
+			UserAgent:  visitor.UserAgent,
+			IP:         visitor.IP,
+		})
+	} else {
+		err = h.analyticsService.CollectServerPageView(r.Context(), site, analytics.CollectInput{
+			Path:          req.Path,
+			Exit:          req.Exit,
+			Referrer:      req.Referrer,
+			UserAgent:     visitor.UserAgent,
+			IP:            visitor.IP,
+			UTMSource:     req.UTMSource,
+			UTMMedium:     req.UTMMedium,
+			UTMCampaign:   req.UTMCampaign,
+			UTMTerm:       req.UTMTerm,
+			UTMContent:    req.UTMContent,
+			ViewportWidth: req.ViewportWidth,
+		})
+	}
+	if err != nil {
+		// Unlike the browser endpoint, backends can retry, so storage failures are reported.
+		slog.ErrorContext(r.Context(), "failed to record ingested analytics", "site_id", site.ID, "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to record analytics")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// IngestBatch accepts an array of ingest requests, each naming its own visitor. Items are
+// validated and rate limited on their own, and each visitor's accepted items are recorded in one
+// transaction. A storage failure returns 500 even when other visitors' items were recorded.
+func (h *IngestHandler) IngestBatch(w http.ResponseWriter, r *http.Request) {
+	credential, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var rawItems []json.RawMessage
+	if !decodeCollectBody(w, r, batchBodyBytes(h.config), &rawItems) || !validateBatchSize(w, rawItems, h.config) {
+		return
+	}
+
+	results := make([]batchItemResult, len(rawItems))
+	var visitors []analytics.Visitor
+	batches := make(map[analytics.Visitor]*visitorBatch)
+	for i, raw := range rawItems {
+		var req ingestRequest
+		if message := decodeBatchItem(raw, h.config, &req); message != "" {
+			results[i].Error = message
+			continue
+		}
+		if _, message := collectRequestError(req.collectRequest, h.config); message != "" {
+			results[i].Error = message
+			continue
+		}
+		visitor, message := ingestVisitor(req)
+		if message != "" {
+			results[i].Error = message
+			continue
+		}
+		if !h.allowIngest(credential, visitor.IP) {
+			results[i].Error = errRateLimitExceeded
+			continue
+		}
+
+		batch, ok := batches[visitor]
+		if !ok {
+			batch = &visitorBatch{}
+			batches[visitor] = batch
+			visitors = append(visitors, visitor)
+		}
+		batch.items = append(batch.items, req.batchItem())
+		batch.indexes = append(batch.indexes, i)
+	}
+
+	status := http.StatusNoContent
+	if len(visitors) > 0 {
+		site, err := h.siteService.GetByPublicKey(r.Context(), credential.SitePublicKey)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to load ingestion site", "site_id", credential.Key.SiteID, "error", err)
+			respondError(w, http.StatusInternalServerError, "failed to record analytics")
+			return
+		}
+		for _, visitor := range visitors {
+			batch := batches[visitor]
+			accepted, err := h.analyticsService.CollectServerBatch(r.Context(), site, visitor, batch.items)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to record ingested analytics batch", "site_id", site.ID, "error", err)
+				status = http.StatusInternalServerError
+			}
+			recordBatchResults(results, batch.indexes, accepted, err)
+		}
+	}
+
+	if status == http.StatusInternalServerError && r.URL.Query().Get("debug") != "1" {
+		respondError(w, status, "failed to record analytics")
+		return
+	}
+	respondBatch(w, r, results, status)
+}
+
+// visitorBatch holds the items of one visitor together with their positions in the request.
+type visitorBatch struct {
+	items   []analytics.BatchItem
+	indexes []int
+}
+
+// authenticate resolves the bearer ingestion key and writes the 401 response when it is missing or invalid.
+func (h *IngestHandler) authenticate(w http.ResponseWriter, r *http.Request) (*ingestkey.Credential, bool) {
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(secret) == "" {
+		respondError(w, http.StatusUnauthorized, "ingestion key is required")
+		return nil, false
+	}
+	credential, err := h.keys.Authenticate(r.Context(), strings.TrimSpace(secret))
+	if err != nil {
+		if !errors.Is(err, ingestkey.ErrInvalidKey) {
+			slog.ErrorContext(r.Context(), "failed to authenticate ingestion key", "error", err)
+		}
+		respondError(w, http.StatusUnauthorized, "invalid ingestion key")
+		return nil, false
+	}
+	return credential, true
+}
+
+// ingestVisitor validates the visitor fields of req, returning an error message when they are unusable.
+func ingestVisitor(req ingestRequest) (analytics.Visitor, string) {
+	ip, err := netip.ParseAddr(strings.TrimSpace(req.IP))
+	if err != nil {
+		return analytics.Visitor{}, "ip must be the visitor's IP address"
+	}
+	userAgent := strings.TrimSpace(req.UserAgent)
+	if userAgent == "" {
+		return analytics.Visitor{}, "user_agent is required"
+	}
+	if utf8.RuneCountInString(userAgent) > maxUserAgentLength {
+		return analytics.Visitor{}, "request field is too long"
+	}
+	return analytics.Visitor{UserAgent: userAgent, IP: ip.Unmap().String()}, ""
+}
+
+// allowIngest limits each visitor separately, because backends send every visitor from the same address.
+func (h *IngestHandler) allowIngest(credential *ingestkey.Credential, visitorIP string) bool {
+	return h.rateLimiter == nil || h.rateLimiter.Allow("ingest|"+credential.SitePublicKey+"|ip|"+visitorIP)
+}
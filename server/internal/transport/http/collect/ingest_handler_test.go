@@ -0,0 +1,153 @@
+package collect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/analytics"
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	"github.com/lovely-eye/server/internal/ingestkey"
+	ingestkeypersistence "github.com/lovely-eye/server/internal/ingestkey/persistence"
+	sitefeature "github.com/lovely-eye/server/internal/site"
+	sitepersistence "github.com/lovely-eye/server/internal/site/persistence"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+const ingestTestUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15"
+
+func TestIngestHandlerRejectsMissingAndForeignKeys(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+
+	for _, authorization := range []string{"", "Bearer lei_unknown-secret", "Bearer " + fixture.site.PublicKey} {
+		req := newIngestRequest(authorization, `{"path":"/checkout","ip":"198.51.100.7","user_agent":"`+ingestTestUserAgent+`"}`)
+		rec := httptest.NewRecorder()
+		fixture.handler.Ingest(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, "authorization %q", authorization)
+	}
+	require.Zero(t, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func TestIngestHandlerRequiresVisitorIPAndUserAgent(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+
+	for _, body := range []string{
+		`{"path":"/checkout","user_agent":"` + ingestTestUserAgent + `"}`,
+		`{"path":"/checkout","ip":"not-an-ip","user_agent":"` + ingestTestUserAgent + `"}`,
+		`{"path":"/checkout","ip":"198.51.100.7"}`,
+		`{"ip":"198.51.100.7","user_agent":"` + ingestTestUserAgent + `"}`,
+	} {
+		req := newIngestRequest("Bearer "+fixture.secret, body)
+		rec := httptest.NewRecorder()
+		fixture.handler.Ingest(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestIngestHandlerRecordsPageViewsWithoutBrowserOrigin(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+
+	for _, visitorIP := range []string{"198.51.100.7", "198.51.100.7", "203.0.113.8"} {
+		req := newIngestRequest("Bearer "+fixture.secret, `{"path":"/checkout","ip":"`+visitorIP+`","user_agent":"`+ingestTestUserAgent+`"}`)
+		rec := httptest.NewRecorder()
+		fixture.handler.Ingest(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+	}
+
+	require.Equal(t, 2, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID), "the repeated visitor page view is deduplicated")
+	sessions, err := fixture.db.NewSelect().
+		Model((*analyticspersistence.Session)(nil)).
+		Where("site_id = ?", fixture.site.ID).
+		Count(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions)
+}
+
+func TestIngestHandlerAppliesSiteBlocking(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, nil)
+	insertAnalyticsHandlerBlockedIP(t, fixture.db, fixture.site.ID, "198.51.100.0/24")
+
+	req := newIngestRequest("Bearer "+fixture.secret, `{"path":"/checkout","ip":"198.51.100.7","user_agent":"`+ingestTestUserAgent+`"}`)
+	rec := httptest.NewRecorder()
+	fixture.handler.Ingest(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Zero(t, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func TestIngestHandlerRateLimitsByVisitor(t *testing.T) {
+	fixture := newIngestHandlerTestFixture(t, NewRateLimiter(true, 1, 1))
+
+	for _, tc := range []struct {
+		visitorIP string
+		want      int
+	}{
+		{visitorIP: "198.51.100.7", want: http.StatusNoContent},
+		{visitorIP: "198.51.100.7", want: http.StatusTooManyRequests},
+		{visitorIP: "198.51.100.8", want: http.StatusNoContent},
+	} {
+		req := newIngestRequest("Bearer "+fixture.secret, `{"path":"/checkout","ip":"`+tc.visitorIP+`","user_agent":"`+ingestTestUserAgent+`"}`)
+		rec := httptest.NewRecorder()
+		fixture.handler.Ingest(rec, req)
+		require.Equal(t, tc.want, rec.Code, tc.visitorIP)
+	}
+}
+
+type ingestHandlerTestFixture struct {
+	handler *IngestHandler
+	site    *sitepersistence.Site
+	db      *bun.DB
+	secret  string
+}
+
+type allowAllIngestSites struct{}
+
+func (allowAllIngestSites) RequireRole(context.Context, int64, int64, sitefeature.Role) error {
+	return nil
+}
+
+func newIngestHandlerTestFixture(t *testing.T, limiter *RateLimiter) *ingestHandlerTestFixture {
+	t.Helper()
+
+	base := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+	siteRepo := sitepersistence.New(base.db)
+	analyticsService := analytics.NewService(
+		analyticspersistence.New(base.db),
+		siteRepo,
+		eventpersistence.New(base.db),
+		nil,
+		nil,
+		strings.Repeat("a", 32),
+	)
+	keys := ingestkey.NewService(ingestkeypersistence.New(base.db), allowAllIngestSites{})
+	_, secret, err := keys.Create(context.Background(), ingestkey.CreateInput{
+		UserID: base.site.UserID,
+		SiteID: base.site.ID,
+		Name:   "Billing",
+	})
+	require.NoError(t, err)
+
+	return &ingestHandlerTestFixture{
+		handler: NewIngestHandler(analyticsService, sitefeature.NewService(siteRepo), keys, AnalyticsHandlerConfig{}, limiter),
+		site:    base.site,
+		db:      base.db,
+		secret:  secret,
+	}
+}
+
+func newIngestRequest(authorization, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/ingest", strings.NewReader(body))
+	req.RemoteAddr = "10.1.2.3:443"
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return req
+}
@@ -0,0 +1,111 @@
+package collect
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lovely-eye/server/internal/analytics"
+)
+
+// transparentGIF is a 1x1 GIF whose only pixel is transparent.
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// Pixel records a page view for pages that cannot run tracker.js, such as visits with JavaScript
+// disabled. The page comes from the Referer header, so it applies the same origin check as Collect,
+// and UTM parameters are read from the Referer query. Browsers send only the origin as a
+// cross-origin Referer by default, so the embed asks for the full URL with referrerpolicy, and an
+// explicit p query parameter replaces the Referer path and query for pages that cannot rely on it.
+// The image is returned even when nothing is recorded so that an unknown site key or a blocked
+// visitor is not revealed.
+func (h *AnalyticsHandler) Pixel(w http.ResponseWriter, r *http.Request) {
+	siteKey := strings.TrimSpace(r.URL.Query().Get("site_key"))
+	if siteKey == "" {
+		respondError(w, http.StatusBadRequest, "site_key query parameter is required")
+		return
+	}
+
+	ip := h.clientIP(r)
+	if !h.allowCollect("ip|" + ip) {
+		respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	if req, ok := pixelRequest(r.Referer(), r.URL.Query().Get("p"), h.config); ok {
+		site, err := h.loadAnalyticsSite(r, siteKey)
+		if err == nil {
+			if !h.allowCollect("site|" + site.PublicKey + "|ip|" + ip) {
+				respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			// Failures stay silent exactly as in Collect.
+			_ = h.analyticsService.CollectPageViewForSite(r.Context(), site, analytics.CollectInput{
+				Path:        req.Path,
+				UserAgent:   r.UserAgent(),
+				IP:          ip,
+				Referer:     r.Referer(),
+				UTMSource:   req.UTMSource,
+				UTMMedium:   req.UTMMedium,
+				UTMCampaign: req.UTMCampaign,
+				UTMTerm:     req.UTMTerm,
+				UTMContent:  req.UTMContent,
+			})
+		}
+	}
+
+	respondPixel(w, r)
+}
+
+// pixelRequest derives a page view from the page URL in referer. A non-empty page, which must be an
+// absolute path, replaces the referer path and query. Like the tracker's default, the path excludes
+// the query string.
+func pixelRequest(referer, page string, config AnalyticsHandlerConfig) (collectRequest, bool) {
+	pageURL, err := url.Parse(referer)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return collectRequest{}, false
+	}
+	if page != "" {
+		pageRef, err := url.Parse(page)
+		if err != nil || pageRef.Scheme != "" || pageRef.Host != "" || !strings.HasPrefix(pageRef.Path, "/") {
+			return collectRequest{}, false
+		}
+		pageURL = pageURL.ResolveReference(pageRef)
+	}
+
+	path := pageURL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	query := pageURL.Query()
+	req := collectRequest{
+		Path:        path,
+		UTMSource:   query.Get("utm_source"),
+		UTMMedium:   query.Get("utm_medium"),
+		UTMCampaign: query.Get("utm_campaign"),
+		UTMTerm:     query.Get("utm_term"),
+		UTMContent:  query.Get("utm_content"),
+	}
+	if status, _ := collectRequestError(req, config); status != 0 {
+		return collectRequest{}, false
+	}
+	return req, true
+}
+
+func respondPixel(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Set("Content-Type", "image/gif")
+	header.Set("Content-Length", strconv.Itoa(len(transparentGIF)))
+	header.Set("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+	header.Set("Pragma", "no-cache")
+	header.Set("Expires", "0")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(transparentGIF); err != nil {
+		slog.WarnContext(r.Context(), "failed to write tracking pixel", "error", err)
+	}
+}
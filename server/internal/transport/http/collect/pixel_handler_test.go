@@ -0,0 +1,111 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsHandlerPixelRecordsRefererPageView(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+	req := newAnalyticsPixelRequest(fixture.site.PublicKey, "https://handler.test/landing?utm_source=newsletter&token=secret")
+	rec := httptest.NewRecorder()
+
+	fixture.handler.Pixel(rec, req)
+
+	requireTrackingPixel(t, rec)
+	require.Equal(t, 1, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+	var event analyticspersistence.Event
+	require.NoError(t, fixture.db.NewSelect().Model(&event).Scan(context.Background()))
+	require.Equal(t, "/landing", event.Path)
+	var session analyticspersistence.Session
+	require.NoError(t, fixture.db.NewSelect().Model(&session).Where("site_id = ?", fixture.site.ID).Scan(context.Background()))
+	require.Equal(t, "newsletter", session.UTMSource)
+}
+
+func TestAnalyticsHandlerPixelRecordsExplicitPageWithOriginReferer(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+	req := newAnalyticsPixelRequest(fixture.site.PublicKey+"&p=%2Fpromo%3Futm_source%3Demail", "https://handler.test/")
+	rec := httptest.NewRecorder()
+
+	fixture.handler.Pixel(rec, req)
+
+	requireTrackingPixel(t, rec)
+	var event analyticspersistence.Event
+	require.NoError(t, fixture.db.NewSelect().Model(&event).Scan(context.Background()))
+	require.Equal(t, "/promo", event.Path)
+	var session analyticspersistence.Session
+	require.NoError(t, fixture.db.NewSelect().Model(&session).Where("site_id = ?", fixture.site.ID).Scan(context.Background()))
+	require.Equal(t, "email", session.UTMSource)
+
+	rec = httptest.NewRecorder()
+	fixture.handler.Pixel(rec, newAnalyticsPixelRequest(fixture.site.PublicKey+"&p=https%3A%2F%2Fother.test%2Fpromo", "https://handler.test/"))
+	requireTrackingPixel(t, rec)
+	require.Equal(t, 1, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func TestAnalyticsHandlerPixelReturnsImageWithoutRecordingRejectedPages(t *testing.T) {
+	fixture := newAnalyticsHandlerTestFixtureWithResolver(t, AnalyticsHandlerConfig{
+		MaxBodyBytes:       4096,
+		MaxPropertiesBytes: 1024,
+	}, nil, nil)
+
+	for _, tc := range []struct {
+		siteKey string
+		referer string
+	}{
+		{siteKey: fixture.site.PublicKey, referer: ""},
+		{siteKey: fixture.site.PublicKey, referer: "https://other.test/landing"},
+		{siteKey: fixture.site.PublicKey, referer: "android-app://handler.test/landing"},
+		{siteKey: "missing-site-key", referer: "https://handler.test/landing"},
+	} {
+		rec := httptest.NewRecorder()
+		fixture.handler.Pixel(rec, newAnalyticsPixelRequest(tc.siteKey, tc.referer))
+		requireTrackingPixel(t, rec)
+	}
+	require.Zero(t, countAnalyticsHandlerPageViews(t, fixture.db, fixture.site.ID))
+}
+
+func TestAnalyticsHandlerPixelRequiresSiteKey(t *testing.T) {
+	handler := NewAnalyticsHandler(nil, nil, AnalyticsHandlerConfig{}, nil, nil)
+	rec := httptest.NewRecorder()
+
+	handler.Pixel(rec, httptest.NewRequest(http.MethodGet, "/api/pixel.gif", nil))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func newAnalyticsPixelRequest(siteKey, referer string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/pixel.gif?site_key="+siteKey, nil)
+	req.RemoteAddr = "203.0.113.10:12345"
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+	return req
+}
+
+func requireTrackingPixel(t *testing.T, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "image/gif", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Header().Get("Cache-Control"), "no-store")
+	image, err := gif.Decode(bytes.NewReader(rec.Body.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, 1, image.Bounds().Dx())
+	require.Equal(t, 1, image.Bounds().Dy())
+	_, _, _, alpha := image.At(0, 0).RGBA()
+	require.Zero(t, alpha)
+}
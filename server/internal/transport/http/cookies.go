@@ -13,6 +13,10 @@ import (
 const (
 	accessTokenCookiePrefix  = "le_access"
 	refreshTokenCookiePrefix = "le_refresh"
+	oidcStateCookiePrefix    = "le_oidc"
+
+	// oidcStateExpiry bounds how long a user may spend at the identity provider.
+	oidcStateExpiry = 10 * time.Minute
 )
 
 type CookieConfig struct {
@@ -29,6 +33,7 @@ type CookieManager struct {
 	path              string
 	accessCookieName  string
 	refreshCookieName string
+	oidcCookieName    string
 	accessExpiry      time.Duration
 	refreshExpiry     time.Duration
 }
@@ -41,6 +46,7 @@ func NewCookieManager(cfg CookieConfig) *CookieManager {
 		path:              path,
 		accessCookieName:  scopedCookieName(accessTokenCookiePrefix, path),
 		refreshCookieName: scopedCookieName(refreshTokenCookiePrefix, path),
+		oidcCookieName:    scopedCookieName(oidcStateCookiePrefix, path),
 		accessExpiry:      cfg.AccessTokenExpiry,
 		refreshExpiry:     cfg.RefreshExpiry,
 	}
@@ -66,11 +72,32 @@ func (m *CookieManager) TokensFromRequest(r *http.Request) (accessToken, refresh
 	return accessToken, refreshToken
 }
 
+// SetOIDCStateCookie keeps the pending login until the provider redirects back. It is always
+// SameSite=Lax because that redirect is a cross-site navigation.
+func (m *CookieManager) SetOIDCStateCookie(w http.ResponseWriter, value string) {
+	m.writeCookie(w, m.oidcCookieName, value, int(oidcStateExpiry.Seconds()), http.SameSiteLaxMode)
+}
+
+func (m *CookieManager) ClearOIDCStateCookie(w http.ResponseWriter) {
+	m.writeCookie(w, m.oidcCookieName, "", -1, http.SameSiteLaxMode)
+}
+
+func (m *CookieManager) OIDCStateFromRequest(r *http.Request) string {
+	if cookie, err := r.Cookie(m.oidcCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
 func (m *CookieManager) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
 	sameSite := http.SameSiteLaxMode
 	if m.secure {
 		sameSite = http.SameSiteStrictMode
 	}
+	m.writeCookie(w, name, value, maxAge, sameSite)
+}
+
+func (m *CookieManager) writeCookie(w http.ResponseWriter, name, value string, maxAge int, sameSite http.SameSite) {
 	// #nosec G124 -- Secure is configurable for local HTTP/test; production defaults it to true.
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
@@ -93,6 +93,18 @@ func readBoundedRequestBody(w http.ResponseWriter, r *http.Request, maxBodyBytes
 	return body, true
 }
 
+// authMutationNames are the mutations that check a password or second factor and therefore
+// share one rate limit.
+var authMutationNames = []string{
+	"login",
+	"register",
+	"changePassword",
+	"verifyTwoFactorLogin",
+	"enableTwoFactor",
+	"disableTwoFactor",
+	"unlockSharedDashboard",
+}
+
 func containsAuthMutation(body []byte) bool {
 	var payload struct {
 		Query string `json:"query"`
@@ -100,7 +112,12 @@ func containsAuthMutation(body []byte) bool {
 	if err := json.Unmarshal(body, &payload); err != nil {
 		return false
 	}
-	return containsGraphQLName(payload.Query, "login") || containsGraphQLName(payload.Query, "register")
+	for _, name := range authMutationNames {
+		if containsGraphQLName(payload.Query, name) {
+			return true
+		}
+	}
+	return false
 }
 
 func containsGraphQLName(query, name string) bool {
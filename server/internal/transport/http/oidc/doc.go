@@ -0,0 +1,5 @@
+// Package oidc implements the OpenID Connect authorization-code flow with PKCE for dashboard
+// single sign-on: discovery, the token exchange, and ID token validation against the
+// provider's JWKS. It knows nothing about Lovely Eye accounts; callers map the verified
+// claims to users.
+package oidc
@@ -0,0 +1,203 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderCompletesAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+
+	request, err := provider.NewAuthorizationRequest(context.Background())
+	require.NoError(t, err)
+	authURL, err := url.Parse(request.URL)
+	require.NoError(t, err)
+	query := authURL.Query()
+	require.Equal(t, issuer.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, "lovely-eye", query.Get("client_id"))
+	require.Equal(t, "openid profile email", query.Get("scope"))
+	require.Equal(t, request.State, query.Get("state"))
+	require.Equal(t, request.Nonce, query.Get("nonce"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	issuer.claims = issuer.validClaims(request.Nonce)
+	issuer.expectedChallenge = query.Get("code_challenge")
+	claims, err := provider.Exchange(context.Background(), "the-code", request.CodeVerifier, request.Nonce)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", claims.String("sub"))
+	require.Equal(t, "alice", claims.String("preferred_username"))
+	require.Equal(t, []string{"admins", "staff"}, claims.Values("groups"))
+
+	_, err = provider.Exchange(context.Background(), "the-code", "wrong-verifier", request.Nonce)
+	require.ErrorIs(t, err, ErrExchange)
+}
+
+func TestProviderRejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(issuer *fakeIssuer, claims jwt.MapClaims)
+	}{
+		{
+			name:   "nonce mismatch",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) { claims["nonce"] = "other" },
+		},
+		{
+			name:   "wrong audience",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) { claims["aud"] = "other-client" },
+		},
+		{
+			name:   "wrong issuer",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		},
+		{
+			name: "expired",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) {
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+		},
+		{
+			name:   "missing subject",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) { delete(claims, "sub") },
+		},
+		{
+			name:   "other authorized party",
+			mutate: func(_ *fakeIssuer, claims jwt.MapClaims) { claims["azp"] = "other-client" },
+		},
+		{
+			name: "unknown signing key",
+			mutate: func(issuer *fakeIssuer, _ jwt.MapClaims) {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+				issuer.signingKey = key
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			provider := issuer.provider()
+			request, err := provider.NewAuthorizationRequest(context.Background())
+			require.NoError(t, err)
+
+			issuer.claims = issuer.validClaims(request.Nonce)
+			tt.mutate(issuer, issuer.claims)
+			_, err = provider.Exchange(context.Background(), "the-code", request.CodeVerifier, request.Nonce)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestProviderRejectsMismatchedDiscoveryIssuer(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	issuer.reportedIssuer = "https://evil.example.com"
+
+	_, err := issuer.provider().NewAuthorizationRequest(context.Background())
+	require.ErrorIs(t, err, ErrDiscovery)
+}
+
+// fakeIssuer is a minimal OpenID provider that signs ID tokens with a generated RSA key.
+type fakeIssuer struct {
+	server            *httptest.Server
+	publishedKey      *rsa.PrivateKey
+	signingKey        *rsa.PrivateKey
+	reportedIssuer    string
+	claims            jwt.MapClaims
+	expectedChallenge string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := &fakeIssuer{publishedKey: key, signingKey: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		reported := issuer.reportedIssuer
+		if reported == "" {
+			reported = issuer.server.URL
+		}
+		writeJSON(t, w, map[string]string{
+			"issuer":                 reported,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		publicKey := issuer.publishedKey.PublicKey
+		writeJSON(t, w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, secret, ok := r.BasicAuth(); !ok || clientID != "lovely-eye" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if issuer.expectedChallenge != "" {
+			sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(sum[:]) != issuer.expectedChallenge {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.claims)
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(issuer.signingKey)
+		require.NoError(t, err)
+		writeJSON(t, w, map[string]string{"access_token": "opaque", "id_token": signed})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *fakeIssuer) provider() *Provider {
+	return NewProvider(Config{
+		IssuerURL:    i.server.URL,
+		ClientID:     "lovely-eye",
+		ClientSecret: "secret",
+		RedirectURL:  "https://analytics.example.com/auth/oidc/callback",
+	}, i.server.Client())
+}
+
+func (i *fakeIssuer) validClaims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":                i.server.URL,
+		"sub":                "user-1",
+		"aud":                "lovely-eye",
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"iat":                now.Unix(),
+		"nonce":              nonce,
+		"preferred_username": "alice",
+		"groups":             []string{"admins", "staff"},
+	}
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, value any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(value))
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/platform/config"
+	"github.com/lovely-eye/server/internal/transport/http/oidc"
+)
+
+type oidcProvider interface {
+	NewAuthorizationRequest(ctx context.Context) (*oidc.AuthorizationRequest, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (oidc.Claims, error)
+}
+
+type externalLoginService interface {
+	LoginExternal(ctx context.Context, identity auth.ExternalIdentity, userAgent string) (*auth.User, *auth.Tokens, error)
+}
+
+// oidcHandler signs dashboard users in through an OpenID provider and then issues the same
+// cookies as a password login.
+type oidcHandler struct {
+	provider oidcProvider
+	service  externalLoginService
+	cookies  *CookieManager
+	config   config.OIDCConfig
+	homePath string
+}
+
+func newOIDCHandler(
+	provider oidcProvider,
+	service externalLoginService,
+	cookies *CookieManager,
+	cfg config.OIDCConfig,
+	basePath string,
+) *oidcHandler {
+	return &oidcHandler{
+		provider: provider,
+		service:  service,
+		cookies:  cookies,
+		config:   cfg,
+		homePath: basePath + "/",
+	}
+}
+
+func (h *oidcHandler) Login(w http.ResponseWriter, r *http.Request) {
+	request, err := h.provider.NewAuthorizationRequest(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to start OIDC login", "error", err)
+		http.Error(w, "Single sign-on is unavailable", http.StatusBadGateway)
+		return
+	}
+	// The random values are base64url encoded, so a dot never occurs inside one of them.
+	h.cookies.SetOIDCStateCookie(w, strings.Join([]string{request.State, request.Nonce, request.CodeVerifier}, "."))
+	http.Redirect(w, r, request.URL, http.StatusFound)
+}
+
+func (h *oidcHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	// A state is single use, so drop it whatever the outcome.
+	pending := h.cookies.OIDCStateFromRequest(r)
+	h.cookies.ClearOIDCStateCookie(w)
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		slog.WarnContext(r.Context(), "OIDC provider rejected login",
+			"error", providerErr, "description", query.Get("error_description"))
+		http.Error(w, "Single sign-on was cancelled or denied", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(pending, ".")
+	if len(parts) != 3 {
+		http.Error(w, "Login expired, please sign in again", http.StatusBadRequest)
+		return
+	}
+	state, nonce, codeVerifier := parts[0], parts[1], parts[2]
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 || query.Get("code") == "" {
+		http.Error(w, "Invalid single sign-on response", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := h.provider.Exchange(r.Context(), query.Get("code"), codeVerifier, nonce)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to complete OIDC login", "error", err)
+		status := http.StatusBadGateway
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, "Single sign-on failed", status)
+		return
+	}
+
+	_, tokens, err := h.service.LoginExternal(r.Context(), h.identity(claims), r.UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrIdentityConflict):
+			http.Error(w, "An account with this username already exists", http.StatusConflict)
+		case errors.Is(err, auth.ErrInvalidExternalIdentity):
+			slog.WarnContext(r.Context(), "OIDC identity is missing a usable username",
+				"claim", h.config.UsernameClaim)
+			http.Error(w, "The identity provider did not supply a valid username", http.StatusForbidden)
+		default:
+			slog.ErrorContext(r.Context(), "failed to sign in OIDC user", "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.cookies.SetAuthCookies(w, tokens)
+	http.Redirect(w, r, h.homePath, http.StatusFound)
+}
+
+func (h *oidcHandler) identity(claims oidc.Claims) auth.ExternalIdentity {
+	identity := auth.ExternalIdentity{
+		Provider:         strings.TrimSuffix(h.config.IssuerURL, "/"),
+		Subject:          claims.String("sub"),
+		Username:         claims.String(h.config.UsernameClaim),
+		LinkExistingUser: h.config.LinkExistingUsers,
+	}
+	if h.config.RoleClaim != "" {
+		identity.Role = auth.RoleUser
+		for _, value := range claims.Values(h.config.RoleClaim) {
+			if slices.Contains(h.config.AdminValues, value) {
+				identity.Role = auth.RoleAdmin
+				break
+			}
+		}
+	}
+	return identity
+}
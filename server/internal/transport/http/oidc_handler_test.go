@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/lovely-eye/server/internal/auth"
+	"github.com/lovely-eye/server/internal/platform/config"
+	"github.com/lovely-eye/server/internal/transport/http/oidc"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOIDCProvider struct {
+	claims oidc.Claims
+	// exchanged records the verifier and nonce of the last code exchange.
+	exchanged []string
+}
+
+func (p *stubOIDCProvider) NewAuthorizationRequest(context.Context) (*oidc.AuthorizationRequest, error) {
+	return &oidc.AuthorizationRequest{
+		URL:          "https://id.example.com/authorize?state=state-1",
+		State:        "state-1",
+		Nonce:        "nonce-1",
+		CodeVerifier: "verifier-1",
+	}, nil
+}
+
+func (p *stubOIDCProvider) Exchange(_ context.Context, _ string, codeVerifier, nonce string) (oidc.Claims, error) {
+	p.exchanged = []string{codeVerifier, nonce}
+	return p.claims, nil
+}
+
+type stubExternalLoginService struct {
+	identity auth.ExternalIdentity
+}
+
+func (s *stubExternalLoginService) LoginExternal(
+	_ context.Context,
+	identity auth.ExternalIdentity,
+	_ string,
+) (*auth.User, *auth.Tokens, error) {
+	s.identity = identity
+	return &auth.User{ID: 1, Username: identity.Username}, &auth.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func TestOIDCHandlerSignsInAfterMatchingCallback(t *testing.T) {
+	provider := &stubOIDCProvider{claims: oidc.Claims{
+		"sub":                "user-1",
+		"preferred_username": "alice",
+		"groups":             []any{"staff", "analytics-admins"},
+	}}
+	service := &stubExternalLoginService{}
+	cookies := newCookieTestManager("/analytics")
+	handler := newOIDCHandler(provider, service, cookies, config.OIDCConfig{
+		IssuerURL:     "https://id.example.com/",
+		UsernameClaim: "preferred_username",
+		RoleClaim:     "groups",
+		AdminValues:   []string{"analytics-admins"},
+	}, "/analytics")
+
+	loginRecorder := httptest.NewRecorder()
+	handler.Login(loginRecorder, httptest.NewRequest(http.MethodGet, "/analytics/auth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, loginRecorder.Code)
+	require.Equal(t, "https://id.example.com/authorize?state=state-1", loginRecorder.Header().Get("Location"))
+	stateCookies := loginRecorder.Result().Cookies()
+	require.Len(t, stateCookies, 1)
+	require.Equal(t, http.SameSiteLaxMode, stateCookies[0].SameSite)
+	require.Equal(t, "/analytics", stateCookies[0].Path)
+	require.True(t, stateCookies[0].HttpOnly)
+
+	callback := func(state string) *httptest.ResponseRecorder {
+		query := url.Values{"state": {state}, "code": {"code-1"}}
+		request := httptest.NewRequest(http.MethodGet, "/analytics/auth/oidc/callback?"+query.Encode(), nil)
+		request.AddCookie(stateCookies[0])
+		recorder := httptest.NewRecorder()
+		handler.Callback(recorder, request)
+		return recorder
+	}
+
+	rejected := callback("forged")
+	require.Equal(t, http.StatusBadRequest, rejected.Code)
+	require.Nil(t, provider.exchanged)
+
+	accepted := callback("state-1")
+	require.Equal(t, http.StatusFound, accepted.Code)
+	require.Equal(t, "/analytics/", accepted.Header().Get("Location"))
+	require.Equal(t, []string{"verifier-1", "nonce-1"}, provider.exchanged)
+	require.Equal(t, auth.ExternalIdentity{
+		Provider: "https://id.example.com",
+		Subject:  "user-1",
+		Username: "alice",
+		Role:     auth.RoleAdmin,
+	}, service.identity)
+
+	request := httptest.NewRequest(http.MethodGet, "/analytics/graphql", nil)
+	for _, cookie := range accepted.Result().Cookies() {
+		if cookie.MaxAge > 0 {
+			request.AddCookie(cookie)
+		}
+	}
+	access, refresh := cookies.TokensFromRequest(request)
+	require.Equal(t, "access", access)
+	require.Equal(t, "refresh", refresh)
+	require.Empty(t, cookies.OIDCStateFromRequest(request), "the state cookie is cleared")
+}
+
+func TestOIDCHandlerRejectsCallbackWithoutPendingLogin(t *testing.T) {
+	provider := &stubOIDCProvider{}
+	handler := newOIDCHandler(provider, &stubExternalLoginService{}, newCookieTestManager("/"), config.OIDCConfig{}, "")
+
+	recorder := httptest.NewRecorder()
+	handler.Callback(recorder, httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?state=state-1&code=code-1", nil))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Nil(t, provider.exchanged)
+}
@@ -6,16 +6,23 @@ import (
 	"time"
 
 	"github.com/lovely-eye/server/internal/analytics"
+	"github.com/lovely-eye/server/internal/apitoken"
+	"github.com/lovely-eye/server/internal/audit"
 	"github.com/lovely-eye/server/internal/auth"
 	"github.com/lovely-eye/server/internal/country"
 	"github.com/lovely-eye/server/internal/dashboard"
 	"github.com/lovely-eye/server/internal/event"
+	"github.com/lovely-eye/server/internal/funnel"
+	"github.com/lovely-eye/server/internal/goal"
 	"github.com/lovely-eye/server/internal/graph"
+	"github.com/lovely-eye/server/internal/ingestkey"
 	"github.com/lovely-eye/server/internal/platform/config"
+	"github.com/lovely-eye/server/internal/share"
 	"github.com/lovely-eye/server/internal/site"
 	"github.com/lovely-eye/server/internal/transport/http/clientip"
 	"github.com/lovely-eye/server/internal/transport/http/collect"
 	transportmiddleware "github.com/lovely-eye/server/internal/transport/http/middleware"
+	"github.com/lovely-eye/server/internal/transport/http/oidc"
 	"github.com/uptrace/bun"
 )
 
@@ -31,6 +38,14 @@ type Services struct {
 	Analytics       *analytics.Service
 	Country         *country.Service
 	EventDefinition *event.Service
+	Goal            *goal.Service
+	Funnel          *funnel.Service
+	APIToken        *apitoken.Service
+	Share           *share.Service
+	IngestKey       *ingestkey.Service
+	Audit           *audit.Service
+	// OIDC is nil unless single sign-on is configured.
+	OIDC *oidc.Provider
 }
 
 type Options struct {
@@ -76,10 +91,22 @@ func buildHTTPHandler(
 		collect.AnalyticsHandlerConfig{
 			MaxBodyBytes:       cfg.Analytics.MaxBodyBytes,
 			MaxPropertiesBytes: cfg.Analytics.MaxPropertiesBytes,
+			MaxBatchItems:      cfg.Analytics.MaxBatchItems,
 		},
 		ipResolver,
 		collectRateLimiter,
 	)
+	ingestHandler := collect.NewIngestHandler(
+		deps.Analytics,
+		deps.Site,
+		deps.IngestKey,
+		collect.AnalyticsHandlerConfig{
+			MaxBodyBytes:       cfg.Analytics.MaxBodyBytes,
+			MaxPropertiesBytes: cfg.Analytics.MaxPropertiesBytes,
+			MaxBatchItems:      cfg.Analytics.MaxBatchItems,
+		},
+		collectRateLimiter,
+	)
 
 	resolver := graph.NewResolver(
 		deps.Auth,
@@ -88,6 +115,12 @@ func buildHTTPHandler(
 		deps.Analytics,
 		deps.Country,
 		deps.EventDefinition,
+		deps.Goal,
+		deps.Funnel,
+		deps.APIToken,
+		deps.Share,
+		deps.IngestKey,
+		deps.Audit,
 		graph.DashboardLimits{
 			MaxDailyRangeDays:     cfg.Dashboard.MaxDailyRangeDays,
 			MaxHourlyRangeDays:    cfg.Dashboard.MaxHourlyRangeDays,
@@ -96,7 +129,10 @@ func buildHTTPHandler(
 		},
 	)
 
-	authMiddleware := newAuthMiddleware(deps.Auth, deps.AuthCookies)
+	authMiddleware := newAuthMiddleware(deps.Auth, deps.APIToken, deps.Share, deps.AuthCookies)
+	if cfg.Auth.ProxyUserHeader != "" {
+		authMiddleware.proxyAuth = newProxyAuth(cfg.Auth.ProxyUserHeader, ipResolver, deps.Auth)
+	}
 	mux := http.NewServeMux()
 	basePath := cfg.Server.BasePath
 	if basePath == "/" {
@@ -104,6 +140,17 @@ func buildHTTPHandler(
 	}
 	mux.HandleFunc("POST "+basePath+"/api/collect", analyticsHandler.Collect)
 	mux.HandleFunc("OPTIONS "+basePath+"/api/collect", analyticsHandler.Collect)
+	mux.HandleFunc("POST "+basePath+"/api/collect/batch", analyticsHandler.CollectBatch)
+	mux.HandleFunc("OPTIONS "+basePath+"/api/collect/batch", analyticsHandler.CollectBatch)
+	mux.HandleFunc("GET "+basePath+"/api/pixel.gif", analyticsHandler.Pixel)
+	mux.HandleFunc("POST "+basePath+"/api/ingest", ingestHandler.Ingest)
+	mux.HandleFunc("POST "+basePath+"/api/ingest/batch", ingestHandler.IngestBatch)
+
+	if deps.OIDC != nil {
+		oidcHandler := newOIDCHandler(deps.OIDC, deps.Auth, deps.AuthCookies, cfg.Auth.OIDC, basePath)
+		mux.HandleFunc("GET "+basePath+"/auth/oidc/login", oidcHandler.Login)
+		mux.HandleFunc("GET "+basePath+"/auth/oidc/callback", oidcHandler.Callback)
+	}
 
 	authRateLimiter := transportmiddleware.NewAuthRateLimiter(
 		cfg.Auth.RateLimitEnabled,
@@ -103,6 +103,14 @@ func execSQLiteMigrationFile(t *testing.T, db *sql.DB, relativePath string) {
 		bytes, err = os.ReadFile(sqliteAnalyticsClientDimensionsUp)
 	case sqliteAnalyticsClientDimensionsDown:
 		bytes, err = os.ReadFile(sqliteAnalyticsClientDimensionsDown)
+	case sqliteSessionReferrerChannelUp:
+		bytes, err = os.ReadFile(sqliteSessionReferrerChannelUp)
+	case sqliteSessionReferrerChannelDown:
+		bytes, err = os.ReadFile(sqliteSessionReferrerChannelDown)
+	case sqliteRepairBuiltinEventFieldsUp:
+		bytes, err = os.ReadFile(sqliteRepairBuiltinEventFieldsUp)
+	case sqliteRepairBuiltinEventFieldsDown:
+		bytes, err = os.ReadFile(sqliteRepairBuiltinEventFieldsDown)
 	default:
 		t.Fatalf("unsupported migration path: %s", relativePath)
 	}
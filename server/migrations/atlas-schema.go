@@ -12,9 +12,15 @@ import (
 	_ "ariga.io/atlas/sdk/recordriver"
 
 	analyticspersistence "github.com/lovely-eye/server/internal/analytics/persistence"
+	apitokenpersistence "github.com/lovely-eye/server/internal/apitoken/persistence"
+	auditpersistence "github.com/lovely-eye/server/internal/audit/persistence"
 	authpersistence "github.com/lovely-eye/server/internal/auth/persistence"
 	countrypersistence "github.com/lovely-eye/server/internal/country/persistence"
 	eventpersistence "github.com/lovely-eye/server/internal/event/persistence"
+	funnelpersistence "github.com/lovely-eye/server/internal/funnel/persistence"
+	goalpersistence "github.com/lovely-eye/server/internal/goal/persistence"
+	ingestkeypersistence "github.com/lovely-eye/server/internal/ingestkey/persistence"
+	sharepersistence "github.com/lovely-eye/server/internal/share/persistence"
 	sitepersistence "github.com/lovely-eye/server/internal/site/persistence"
 )
 
@@ -37,10 +43,14 @@ func main() {
 
 	stmts, err := bunschema.New(d).Load(
 		&authpersistence.User{},
+		&authpersistence.Session{},
+		&authpersistence.RecoveryCode{},
+		&authpersistence.Identity{},
 		&sitepersistence.Site{},
 		&sitepersistence.Domain{},
 		&sitepersistence.BlockedIP{},
 		&sitepersistence.BlockedCountry{},
+		&sitepersistence.Member{},
 		&countrypersistence.Country{},
 		&analyticspersistence.Client{},
 		&analyticspersistence.Session{},
@@ -48,6 +58,15 @@ func main() {
 		&eventpersistence.Definition{},
 		&eventpersistence.Field{},
 		&analyticspersistence.EventData{},
+		&goalpersistence.Goal{},
+		&funnelpersistence.Funnel{},
+		&funnelpersistence.Step{},
+		&apitokenpersistence.APIToken{},
+		&apitokenpersistence.APITokenSite{},
+		&sharepersistence.Link{},
+		&ingestkeypersistence.Key{},
+		&auditpersistence.Entry{},
+		&auditpersistence.Change{},
 	)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load schema: %v\n", err)
@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteRepairBuiltinEventFieldsUp   = "sqlite/20260814120000_repair_builtin_event_fields.up.sql"
+	sqliteRepairBuiltinEventFieldsDown = "sqlite/20260814120000_repair_builtin_event_fields.down.sql"
+)
+
+func TestSQLiteRepairBuiltinEventFieldsMigration(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	schema := `
+CREATE TABLE event_definitions (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name varchar NOT NULL
+);
+CREATE TABLE event_definition_fields (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  event_definition_id integer NOT NULL,
+  key varchar NOT NULL,
+  type integer NOT NULL,
+  required boolean NOT NULL DEFAULT false,
+  max_length integer NOT NULL DEFAULT 500,
+  created_at timestamp NOT NULL DEFAULT (current_timestamp),
+  updated_at timestamp NOT NULL DEFAULT (current_timestamp)
+);
+`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	if _, err := db.Exec(`
+INSERT INTO event_definitions (id, name) VALUES (1, 'file_download'), (2, 'outbound_link'), (3, 'signup');
+INSERT INTO event_definition_fields (id, event_definition_id, key, type, required, max_length) VALUES
+  (1, 1, 'file', 0, true, 500),
+  (2, 2, 'url', 1, false, 64),
+  (3, 3, 'plan', 0, true, 100);
+`); err != nil {
+		t.Fatalf("insert legacy event definitions: %v", err)
+	}
+
+	execSQLiteMigrationFile(t, db, sqliteRepairBuiltinEventFieldsUp)
+
+	type field struct {
+		definitionID int64
+		key          string
+		fieldType    int
+		required     bool
+		maxLength    int
+	}
+	rows, err := db.Query(`SELECT event_definition_id, key, type, required, max_length FROM event_definition_fields ORDER BY event_definition_id, key`)
+	if err != nil {
+		t.Fatalf("select migrated fields: %v", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	var got []field
+	for rows.Next() {
+		var f field
+		if err := rows.Scan(&f.definitionID, &f.key, &f.fieldType, &f.required, &f.maxLength); err != nil {
+			t.Fatalf("scan migrated field: %v", err)
+		}
+		got = append(got, f)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterate migrated fields: %v", err)
+	}
+
+	want := []field{
+		{definitionID: 1, key: "file", fieldType: 0, required: false, maxLength: 500},
+		{definitionID: 1, key: "url", fieldType: 0, required: true, maxLength: 2048},
+		{definitionID: 2, key: "url", fieldType: 0, required: true, maxLength: 2048},
+		{definitionID: 3, key: "plan", fieldType: 0, required: true, maxLength: 100},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("migrated fields = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("migrated field %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	execSQLiteMigrationFile(t, db, sqliteRepairBuiltinEventFieldsDown)
+}
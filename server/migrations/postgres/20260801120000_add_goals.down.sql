@@ -0,0 +1,2 @@
+-- reverse: create "goals" table
+DROP TABLE "public"."goals";
@@ -0,0 +1,13 @@
+-- create "goals" table
+CREATE TABLE "public"."goals" (
+  "id" bigserial NOT NULL,
+  "site_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "type" smallint NOT NULL,
+  "value" character varying(2048) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "goals_site_id_name" UNIQUE ("site_id", "name"),
+  CONSTRAINT "goals_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
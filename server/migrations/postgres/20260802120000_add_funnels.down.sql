@@ -0,0 +1,4 @@
+-- reverse: create "funnel_steps" table
+DROP TABLE "public"."funnel_steps";
+-- reverse: create "funnels" table
+DROP TABLE "public"."funnels";
@@ -0,0 +1,22 @@
+-- create "funnels" table
+CREATE TABLE "public"."funnels" (
+  "id" bigserial NOT NULL,
+  "site_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "funnels_site_id_name" UNIQUE ("site_id", "name"),
+  CONSTRAINT "funnels_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create "funnel_steps" table
+CREATE TABLE "public"."funnel_steps" (
+  "id" bigserial NOT NULL,
+  "funnel_id" bigint NOT NULL,
+  "position" bigint NOT NULL,
+  "type" smallint NOT NULL,
+  "value" character varying(2048) NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "funnel_steps_funnel_id_position" UNIQUE ("funnel_id", "position"),
+  CONSTRAINT "funnel_steps_funnel_id_fkey" FOREIGN KEY ("funnel_id") REFERENCES "public"."funnels" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
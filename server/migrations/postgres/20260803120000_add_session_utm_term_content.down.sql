@@ -0,0 +1,2 @@
+-- reverse: add "utm_term" and "utm_content" columns to table: "sessions"
+ALTER TABLE "public"."sessions" DROP COLUMN "utm_content", DROP COLUMN "utm_term";
@@ -0,0 +1,2 @@
+-- add "utm_term" and "utm_content" columns to table: "sessions"
+ALTER TABLE "public"."sessions" ADD COLUMN "utm_term" character varying(256) NULL, ADD COLUMN "utm_content" character varying(256) NULL;
@@ -0,0 +1,2 @@
+-- reverse: add "referrer_host" and "channel" columns to table: "sessions"
+ALTER TABLE "public"."sessions" DROP COLUMN "channel", DROP COLUMN "referrer_host";
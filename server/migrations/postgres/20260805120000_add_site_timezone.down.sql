@@ -0,0 +1,2 @@
+-- reverse: add "timezone" column to table: "sites"
+ALTER TABLE "public"."sites" DROP COLUMN "timezone";
@@ -0,0 +1,2 @@
+-- add "timezone" column to table: "sites"
+ALTER TABLE "public"."sites" ADD COLUMN "timezone" character varying(64) NOT NULL DEFAULT 'UTC';
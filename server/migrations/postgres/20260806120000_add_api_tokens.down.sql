@@ -0,0 +1,6 @@
+-- reverse: create "api_token_sites" table
+DROP TABLE "public"."api_token_sites";
+-- reverse: create index "api_tokens_user_id" to table: "api_tokens"
+DROP INDEX "public"."api_tokens_user_id";
+-- reverse: create "api_tokens" table
+DROP TABLE "public"."api_tokens";
@@ -0,0 +1,26 @@
+-- create "api_tokens" table
+CREATE TABLE "public"."api_tokens" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "prefix" character varying(16) NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "site_scoped" boolean NOT NULL DEFAULT false,
+  "last_used_at" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "api_tokens_token_hash_key" UNIQUE ("token_hash"),
+  CONSTRAINT "api_tokens_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "api_tokens_user_id" to table: "api_tokens"
+CREATE INDEX "api_tokens_user_id" ON "public"."api_tokens" ("user_id");
+-- create "api_token_sites" table
+CREATE TABLE "public"."api_token_sites" (
+  "id" bigserial NOT NULL,
+  "api_token_id" bigint NOT NULL,
+  "site_id" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "api_token_sites_api_token_id_site_id" UNIQUE ("api_token_id", "site_id"),
+  CONSTRAINT "api_token_sites_api_token_id_fkey" FOREIGN KEY ("api_token_id") REFERENCES "public"."api_tokens" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "api_token_sites_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
@@ -0,0 +1,4 @@
+-- reverse: create index "site_members_user_id" to table: "site_members"
+DROP INDEX "public"."site_members_user_id";
+-- reverse: create "site_members" table
+DROP TABLE "public"."site_members";
@@ -0,0 +1,15 @@
+-- create "site_members" table
+CREATE TABLE "public"."site_members" (
+  "id" bigserial NOT NULL,
+  "site_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "role" character varying(16) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "site_members_site_id_user_id" UNIQUE ("site_id", "user_id"),
+  CONSTRAINT "site_members_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "site_members_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "site_members_user_id" to table: "site_members"
+CREATE INDEX "site_members_user_id" ON "public"."site_members" ("user_id");
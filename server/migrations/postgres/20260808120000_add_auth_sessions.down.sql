@@ -0,0 +1,4 @@
+-- reverse: create index "auth_sessions_user_id" to table: "auth_sessions"
+DROP INDEX "public"."auth_sessions_user_id";
+-- reverse: create "auth_sessions" table
+DROP TABLE "public"."auth_sessions";
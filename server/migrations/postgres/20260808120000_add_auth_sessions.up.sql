@@ -0,0 +1,16 @@
+-- create "auth_sessions" table
+CREATE TABLE "public"."auth_sessions" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "generation" bigint NOT NULL DEFAULT 1,
+  "user_agent" character varying(256) NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "last_used_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "rotated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "auth_sessions_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "auth_sessions_user_id" to table: "auth_sessions"
+CREATE INDEX "auth_sessions_user_id" ON "public"."auth_sessions" ("user_id");
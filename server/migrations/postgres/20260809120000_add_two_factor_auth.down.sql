@@ -0,0 +1,6 @@
+-- reverse: create index "user_recovery_codes_user_id" to table: "user_recovery_codes"
+DROP INDEX "public"."user_recovery_codes_user_id";
+-- reverse: create "user_recovery_codes" table
+DROP TABLE "public"."user_recovery_codes";
+-- reverse: add "totp_secret", "totp_enabled_at", and "totp_last_step" columns to table: "users"
+ALTER TABLE "public"."users" DROP COLUMN "totp_last_step", DROP COLUMN "totp_enabled_at", DROP COLUMN "totp_secret";
@@ -0,0 +1,14 @@
+-- add "totp_secret", "totp_enabled_at", and "totp_last_step" columns to table: "users"
+ALTER TABLE "public"."users" ADD COLUMN "totp_secret" character varying(64) NOT NULL DEFAULT '', ADD COLUMN "totp_enabled_at" timestamptz NULL, ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+-- create "user_recovery_codes" table
+CREATE TABLE "public"."user_recovery_codes" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "code_hash" character varying(64) NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "user_recovery_codes_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "user_recovery_codes_user_id" to table: "user_recovery_codes"
+CREATE INDEX "user_recovery_codes_user_id" ON "public"."user_recovery_codes" ("user_id");
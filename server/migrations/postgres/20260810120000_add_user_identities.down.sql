@@ -0,0 +1,4 @@
+-- reverse: create index "user_identities_user_id" to table: "user_identities"
+DROP INDEX "public"."user_identities_user_id";
+-- reverse: create "user_identities" table
+DROP TABLE "public"."user_identities";
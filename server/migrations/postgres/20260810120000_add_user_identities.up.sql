@@ -0,0 +1,13 @@
+-- create "user_identities" table
+CREATE TABLE "public"."user_identities" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "provider" character varying(255) NOT NULL,
+  "subject" character varying(255) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "user_identities_provider_subject" UNIQUE ("provider", "subject"),
+  CONSTRAINT "user_identities_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "user_identities_user_id" to table: "user_identities"
+CREATE INDEX "user_identities_user_id" ON "public"."user_identities" ("user_id");
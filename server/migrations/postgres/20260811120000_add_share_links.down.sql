@@ -0,0 +1,4 @@
+-- reverse: create index "share_links_site_id" to table: "share_links"
+DROP INDEX "public"."share_links_site_id";
+-- reverse: create "share_links" table
+DROP TABLE "public"."share_links";
@@ -0,0 +1,17 @@
+-- create "share_links" table
+CREATE TABLE "public"."share_links" (
+  "id" bigserial NOT NULL,
+  "site_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "prefix" character varying(16) NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "password_hash" character varying(255) NULL,
+  "restricted" boolean NOT NULL DEFAULT false,
+  "breakdowns" character varying(255) NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "share_links_token_hash_key" UNIQUE ("token_hash"),
+  CONSTRAINT "share_links_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "share_links_site_id" to table: "share_links"
+CREATE INDEX "share_links_site_id" ON "public"."share_links" ("site_id");
@@ -0,0 +1,10 @@
+-- reverse: create index "audit_changes_entry_id" to table: "audit_changes"
+DROP INDEX "public"."audit_changes_entry_id";
+-- reverse: create "audit_changes" table
+DROP TABLE "public"."audit_changes";
+-- reverse: create index "audit_entries_site_id_created_at" to table: "audit_entries"
+DROP INDEX "public"."audit_entries_site_id_created_at";
+-- reverse: create index "audit_entries_created_at" to table: "audit_entries"
+DROP INDEX "public"."audit_entries_created_at";
+-- reverse: create "audit_entries" table
+DROP TABLE "public"."audit_entries";
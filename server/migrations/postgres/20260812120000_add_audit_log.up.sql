@@ -0,0 +1,27 @@
+-- create "audit_entries" table
+CREATE TABLE "public"."audit_entries" (
+  "id" bigserial NOT NULL,
+  "actor_id" bigint NULL,
+  "actor_name" character varying(128) NOT NULL DEFAULT '',
+  "action" character varying(64) NOT NULL,
+  "site_id" bigint NULL,
+  "target_user_id" bigint NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+-- create index "audit_entries_created_at" to table: "audit_entries"
+CREATE INDEX "audit_entries_created_at" ON "public"."audit_entries" ("created_at");
+-- create index "audit_entries_site_id_created_at" to table: "audit_entries"
+CREATE INDEX "audit_entries_site_id_created_at" ON "public"."audit_entries" ("site_id", "created_at");
+-- create "audit_changes" table
+CREATE TABLE "public"."audit_changes" (
+  "id" bigserial NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "field" character varying(64) NOT NULL,
+  "before_value" text NULL,
+  "after_value" text NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "audit_changes_entry_id_fkey" FOREIGN KEY ("entry_id") REFERENCES "public"."audit_entries" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "audit_changes_entry_id" to table: "audit_changes"
+CREATE INDEX "audit_changes_entry_id" ON "public"."audit_changes" ("entry_id");
@@ -0,0 +1,4 @@
+-- reverse: create index "ingest_keys_site_id" to table: "ingest_keys"
+DROP INDEX "public"."ingest_keys_site_id";
+-- reverse: create "ingest_keys" table
+DROP TABLE "public"."ingest_keys";
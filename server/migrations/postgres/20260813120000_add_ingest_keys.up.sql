@@ -0,0 +1,15 @@
+-- create "ingest_keys" table
+CREATE TABLE "public"."ingest_keys" (
+  "id" bigserial NOT NULL,
+  "site_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "prefix" character varying(16) NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "last_used_at" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "ingest_keys_token_hash_key" UNIQUE ("token_hash"),
+  CONSTRAINT "ingest_keys_site_id_fkey" FOREIGN KEY ("site_id") REFERENCES "public"."sites" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "ingest_keys_site_id" to table: "ingest_keys"
+CREATE INDEX "ingest_keys_site_id" ON "public"."ingest_keys" ("site_id");
@@ -0,0 +1,11 @@
+-- make fields other than "url" optional on the built-in "outbound_link" and "file_download" events
+UPDATE "public"."event_definition_fields" SET "required" = false, "updated_at" = CURRENT_TIMESTAMP
+WHERE "key" <> 'url' AND "event_definition_id" IN (SELECT "id" FROM "public"."event_definitions" WHERE "name" IN ('outbound_link', 'file_download'));
+-- give an existing "url" field of the built-in events the built-in shape
+UPDATE "public"."event_definition_fields" SET "type" = 0, "required" = true, "max_length" = 2048, "updated_at" = CURRENT_TIMESTAMP
+WHERE "key" = 'url' AND "event_definition_id" IN (SELECT "id" FROM "public"."event_definitions" WHERE "name" IN ('outbound_link', 'file_download'));
+-- add the "url" field to built-in events that lack it
+INSERT INTO "public"."event_definition_fields" ("event_definition_id", "key", "type", "required", "max_length")
+SELECT "d"."id", 'url', 0, true, 2048 FROM "public"."event_definitions" "d"
+WHERE "d"."name" IN ('outbound_link', 'file_download')
+  AND NOT EXISTS (SELECT 1 FROM "public"."event_definition_fields" "f" WHERE "f"."event_definition_id" = "d"."id" AND "f"."key" = 'url');
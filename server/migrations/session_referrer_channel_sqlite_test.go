@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteSessionReferrerChannelUp   = "sqlite/20260804120000_add_session_referrer_channel.up.sql"
+	sqliteSessionReferrerChannelDown = "sqlite/20260804120000_add_session_referrer_channel.down.sql"
+)
+
+func TestSQLiteSessionReferrerChannelMigrationBackfill(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	schema := `
+CREATE TABLE sessions (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  referrer varchar NULL,
+  utm_source varchar NULL,
+  utm_medium varchar NULL
+);
+`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	if _, err := db.Exec(`
+INSERT INTO sessions (id, referrer, utm_source, utm_medium) VALUES
+  (1, 'https://www.Google.de/search?q=lovely', NULL, NULL),
+  (2, 'https://l.facebook.com/l.php?u=x', NULL, NULL),
+  (3, 'https://user@blog.example.com:8443/post#top', NULL, NULL),
+  (4, NULL, NULL, NULL),
+  (5, 'https://www.google.com/', 'google', 'cpc'),
+  (6, '', 'newsletter', NULL),
+  (7, 'https://mail.google.com/mail/u/0/', NULL, NULL);
+`); err != nil {
+		t.Fatalf("insert sessions: %v", err)
+	}
+
+	execSQLiteMigrationFile(t, db, sqliteSessionReferrerChannelUp)
+
+	want := map[int]struct {
+		host    string
+		channel int
+	}{
+		1: {host: "google.de", channel: 5},
+		2: {host: "l.facebook.com", channel: 6},
+		3: {host: "blog.example.com", channel: 4},
+		4: {host: "", channel: 1},
+		5: {host: "google.com", channel: 3},
+		6: {host: "", channel: 2},
+		7: {host: "mail.google.com", channel: 2},
+	}
+	for id, expected := range want {
+		var host sql.NullString
+		var channel int
+		if err := db.QueryRow(`SELECT referrer_host, channel FROM sessions WHERE id = ?`, id).Scan(&host, &channel); err != nil {
+			t.Fatalf("select migrated session %d: %v", id, err)
+		}
+		if host.String != expected.host || channel != expected.channel {
+			t.Fatalf("session %d: referrer_host=%q channel=%d, want %q and %d", id, host.String, channel, expected.host, expected.channel)
+		}
+	}
+
+	execSQLiteMigrationFile(t, db, sqliteSessionReferrerChannelDown)
+}
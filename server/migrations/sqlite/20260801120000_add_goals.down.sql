@@ -0,0 +1,4 @@
+-- reverse: create index "goals_site_id_name" to table: "goals"
+DROP INDEX `goals_site_id_name`;
+-- reverse: create "goals" table
+DROP TABLE `goals`;
@@ -0,0 +1,13 @@
+-- create "goals" table
+CREATE TABLE `goals` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `site_id` integer NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `type` integer NOT NULL,
+  `value` varchar(2048) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `updated_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "goals_site_id_name" to table: "goals"
+CREATE UNIQUE INDEX `goals_site_id_name` ON `goals` (`site_id`, `name`);
@@ -0,0 +1,8 @@
+-- reverse: create index "funnel_steps_funnel_id_position" to table: "funnel_steps"
+DROP INDEX `funnel_steps_funnel_id_position`;
+-- reverse: create "funnel_steps" table
+DROP TABLE `funnel_steps`;
+-- reverse: create index "funnels_site_id_name" to table: "funnels"
+DROP INDEX `funnels_site_id_name`;
+-- reverse: create "funnels" table
+DROP TABLE `funnels`;
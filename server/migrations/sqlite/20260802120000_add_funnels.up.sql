@@ -0,0 +1,22 @@
+-- create "funnels" table
+CREATE TABLE `funnels` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `site_id` integer NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `updated_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "funnels_site_id_name" to table: "funnels"
+CREATE UNIQUE INDEX `funnels_site_id_name` ON `funnels` (`site_id`, `name`);
+-- create "funnel_steps" table
+CREATE TABLE `funnel_steps` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `funnel_id` integer NOT NULL,
+  `position` integer NOT NULL,
+  `type` integer NOT NULL,
+  `value` varchar(2048) NOT NULL,
+  CONSTRAINT `0` FOREIGN KEY (`funnel_id`) REFERENCES `funnels` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "funnel_steps_funnel_id_position" to table: "funnel_steps"
+CREATE UNIQUE INDEX `funnel_steps_funnel_id_position` ON `funnel_steps` (`funnel_id`, `position`);
@@ -0,0 +1,3 @@
+-- reverse: add "utm_term" and "utm_content" columns to table: "sessions"
+ALTER TABLE `sessions` DROP COLUMN `utm_content`;
+ALTER TABLE `sessions` DROP COLUMN `utm_term`;
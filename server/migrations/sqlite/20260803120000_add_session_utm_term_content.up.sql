@@ -0,0 +1,3 @@
+-- add "utm_term" and "utm_content" columns to table: "sessions"
+ALTER TABLE `sessions` ADD COLUMN `utm_term` varchar NULL;
+ALTER TABLE `sessions` ADD COLUMN `utm_content` varchar NULL;
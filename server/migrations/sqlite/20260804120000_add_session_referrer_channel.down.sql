@@ -0,0 +1,3 @@
+-- reverse: add "referrer_host" and "channel" columns to table: "sessions"
+ALTER TABLE `sessions` DROP COLUMN `channel`;
+ALTER TABLE `sessions` DROP COLUMN `referrer_host`;
@@ -0,0 +1,66 @@
+-- add "referrer_host" and "channel" columns to table: "sessions"
+ALTER TABLE `sessions` ADD COLUMN `referrer_host` varchar NULL;
+ALTER TABLE `sessions` ADD COLUMN `channel` integer NOT NULL DEFAULT 0;
+-- backfill "referrer_host" by stripping scheme, path, credentials, port and a leading www./m. label
+UPDATE `sessions` SET `referrer_host` = lower(trim(COALESCE(`referrer`, '')));
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, instr(`referrer_host`, '://') + 3) WHERE instr(`referrer_host`, '://') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 1, instr(`referrer_host`, '/') - 1) WHERE instr(`referrer_host`, '/') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 1, instr(`referrer_host`, '?') - 1) WHERE instr(`referrer_host`, '?') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 1, instr(`referrer_host`, '#') - 1) WHERE instr(`referrer_host`, '#') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, instr(`referrer_host`, '@') + 1) WHERE instr(`referrer_host`, '@') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 1, instr(`referrer_host`, ':') - 1) WHERE instr(`referrer_host`, ':') > 0;
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 5) WHERE substr(`referrer_host`, 1, 4) = 'www.';
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 3) WHERE substr(`referrer_host`, 1, 2) = 'm.';
+UPDATE `sessions` SET `referrer_host` = substr(`referrer_host`, 1, 255) WHERE length(`referrer_host`) > 255;
+-- backfill "channel" from utm_medium, the referrer host, then utm_source
+UPDATE `sessions` SET `channel` = CASE
+  WHEN lower(trim(COALESCE(`utm_medium`, ''))) IN ('cpc', 'ppc', 'cpm', 'cpv', 'cpa', 'paid', 'paidsearch', 'paid_search', 'paid-search', 'paidsocial', 'paid_social', 'paid-social', 'display', 'banner', 'retargeting') THEN 3
+  WHEN lower(trim(COALESCE(`utm_medium`, ''))) IN ('email', 'e-mail', 'newsletter') THEN 2
+  WHEN lower(trim(COALESCE(`utm_medium`, ''))) IN ('social', 'social-media', 'social_media', 'social-network', 'social_network', 'sm') THEN 6
+  WHEN lower(trim(COALESCE(`utm_medium`, ''))) = 'organic' THEN 5
+  WHEN COALESCE(`referrer_host`, '') IN ('mail.google.com', 'mail.yahoo.com', 'mail.proton.me', 'mail.aol.com', 'outlook.live.com', 'outlook.office.com', 'outlook.office365.com', 'app.fastmail.com') THEN 2
+  WHEN (COALESCE(`referrer_host`, '') <> '' AND (COALESCE(`referrer_host`, '') IN ('search.brave.com', 'search.yahoo.com')
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'google.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'bing.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'duckduckgo.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'yahoo.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'yandex.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'baidu.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'ecosia.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'qwant.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'startpage.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'naver.%'
+      OR COALESCE(`referrer_host`, '') || '.' LIKE 'seznam.%')) THEN 5
+  WHEN (COALESCE(`referrer_host`, '') <> '' AND ('.' || COALESCE(`referrer_host`, '') LIKE '%.facebook.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.instagram.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.linkedin.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.lnkd.in'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.t.co'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.twitter.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.x.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.reddit.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.news.ycombinator.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.pinterest.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.youtube.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.tiktok.com'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.threads.net'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.bsky.app'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.mastodon.social'
+      OR '.' || COALESCE(`referrer_host`, '') LIKE '%.quora.com')) THEN 6
+  WHEN lower(trim(COALESCE(`utm_source`, ''))) IN ('email', 'e-mail', 'newsletter') THEN 2
+  WHEN (lower(trim(COALESCE(`utm_source`, ''))) <> '' AND (lower(trim(COALESCE(`utm_source`, ''))) IN ('search.brave.com', 'search.yahoo.com')
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'google.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'bing.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'duckduckgo.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'yahoo.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'yandex.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'baidu.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'ecosia.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'qwant.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'startpage.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'naver.%'
+      OR lower(trim(COALESCE(`utm_source`, ''))) || '.' LIKE 'seznam.%')) THEN 5
+  WHEN lower(trim(COALESCE(`utm_source`, ''))) IN ('facebook', 'instagram', 'linkedin', 'twitter', 'x', 'reddit', 'hackernews', 'pinterest', 'youtube', 'tiktok', 'threads', 'bluesky', 'mastodon') THEN 6
+  WHEN COALESCE(`referrer_host`, '') <> '' OR lower(trim(COALESCE(`utm_source`, ''))) <> '' OR lower(trim(COALESCE(`utm_medium`, ''))) <> '' THEN 4
+  ELSE 1
+END;
@@ -0,0 +1,2 @@
+-- reverse: add "timezone" column to table: "sites"
+ALTER TABLE `sites` DROP COLUMN `timezone`;
@@ -0,0 +1,2 @@
+-- add "timezone" column to table: "sites"
+ALTER TABLE `sites` ADD COLUMN `timezone` varchar NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,10 @@
+-- reverse: create index "api_token_sites_api_token_id_site_id" to table: "api_token_sites"
+DROP INDEX `api_token_sites_api_token_id_site_id`;
+-- reverse: create "api_token_sites" table
+DROP TABLE `api_token_sites`;
+-- reverse: create index "api_tokens_user_id" to table: "api_tokens"
+DROP INDEX `api_tokens_user_id`;
+-- reverse: create index "api_tokens_token_hash_key" to table: "api_tokens"
+DROP INDEX `api_tokens_token_hash_key`;
+-- reverse: create "api_tokens" table
+DROP TABLE `api_tokens`;
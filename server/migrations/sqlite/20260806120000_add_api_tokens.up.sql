@@ -0,0 +1,26 @@
+-- create "api_tokens" table
+CREATE TABLE `api_tokens` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `prefix` varchar(16) NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `site_scoped` boolean NOT NULL DEFAULT false,
+  `last_used_at` timestamp NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "api_tokens_token_hash_key" to table: "api_tokens"
+CREATE UNIQUE INDEX `api_tokens_token_hash_key` ON `api_tokens` (`token_hash`);
+-- create index "api_tokens_user_id" to table: "api_tokens"
+CREATE INDEX `api_tokens_user_id` ON `api_tokens` (`user_id`);
+-- create "api_token_sites" table
+CREATE TABLE `api_token_sites` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `api_token_id` integer NOT NULL,
+  `site_id` integer NOT NULL,
+  CONSTRAINT `0` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT `1` FOREIGN KEY (`api_token_id`) REFERENCES `api_tokens` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "api_token_sites_api_token_id_site_id" to table: "api_token_sites"
+CREATE UNIQUE INDEX `api_token_sites_api_token_id_site_id` ON `api_token_sites` (`api_token_id`, `site_id`);
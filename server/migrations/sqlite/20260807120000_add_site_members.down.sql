@@ -0,0 +1,6 @@
+-- reverse: create index "site_members_user_id" to table: "site_members"
+DROP INDEX `site_members_user_id`;
+-- reverse: create index "site_members_site_id_user_id" to table: "site_members"
+DROP INDEX `site_members_site_id_user_id`;
+-- reverse: create "site_members" table
+DROP TABLE `site_members`;
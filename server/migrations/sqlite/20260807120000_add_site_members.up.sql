@@ -0,0 +1,15 @@
+-- create "site_members" table
+CREATE TABLE `site_members` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `site_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `role` varchar(16) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `updated_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT `1` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "site_members_site_id_user_id" to table: "site_members"
+CREATE UNIQUE INDEX `site_members_site_id_user_id` ON `site_members` (`site_id`, `user_id`);
+-- create index "site_members_user_id" to table: "site_members"
+CREATE INDEX `site_members_user_id` ON `site_members` (`user_id`);
@@ -0,0 +1,15 @@
+-- create "auth_sessions" table
+CREATE TABLE `auth_sessions` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `generation` integer NOT NULL DEFAULT 1,
+  `user_agent` varchar(256) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `last_used_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `rotated_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  `expires_at` timestamp NOT NULL,
+  `revoked_at` timestamp NULL,
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "auth_sessions_user_id" to table: "auth_sessions"
+CREATE INDEX `auth_sessions_user_id` ON `auth_sessions` (`user_id`);
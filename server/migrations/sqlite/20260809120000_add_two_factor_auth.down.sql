@@ -0,0 +1,10 @@
+-- reverse: create index "user_recovery_codes_user_id" to table: "user_recovery_codes"
+DROP INDEX `user_recovery_codes_user_id`;
+-- reverse: create "user_recovery_codes" table
+DROP TABLE `user_recovery_codes`;
+-- reverse: add "totp_last_step" column to table: "users"
+ALTER TABLE `users` DROP COLUMN `totp_last_step`;
+-- reverse: add "totp_enabled_at" column to table: "users"
+ALTER TABLE `users` DROP COLUMN `totp_enabled_at`;
+-- reverse: add "totp_secret" column to table: "users"
+ALTER TABLE `users` DROP COLUMN `totp_secret`;
@@ -0,0 +1,17 @@
+-- add "totp_secret" column to table: "users"
+ALTER TABLE `users` ADD COLUMN `totp_secret` varchar(64) NOT NULL DEFAULT '';
+-- add "totp_enabled_at" column to table: "users"
+ALTER TABLE `users` ADD COLUMN `totp_enabled_at` timestamp NULL;
+-- add "totp_last_step" column to table: "users"
+ALTER TABLE `users` ADD COLUMN `totp_last_step` integer NOT NULL DEFAULT 0;
+-- create "user_recovery_codes" table
+CREATE TABLE `user_recovery_codes` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `code_hash` varchar(64) NOT NULL,
+  `used_at` timestamp NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "user_recovery_codes_user_id" to table: "user_recovery_codes"
+CREATE INDEX `user_recovery_codes_user_id` ON `user_recovery_codes` (`user_id`);
@@ -0,0 +1,6 @@
+-- reverse: create index "user_identities_user_id" to table: "user_identities"
+DROP INDEX `user_identities_user_id`;
+-- reverse: create index "user_identities_provider_subject" to table: "user_identities"
+DROP INDEX `user_identities_provider_subject`;
+-- reverse: create "user_identities" table
+DROP TABLE `user_identities`;
@@ -0,0 +1,13 @@
+-- create "user_identities" table
+CREATE TABLE `user_identities` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `provider` varchar(255) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "user_identities_provider_subject" to table: "user_identities"
+CREATE UNIQUE INDEX `user_identities_provider_subject` ON `user_identities` (`provider`, `subject`);
+-- create index "user_identities_user_id" to table: "user_identities"
+CREATE INDEX `user_identities_user_id` ON `user_identities` (`user_id`);
@@ -0,0 +1,6 @@
+-- reverse: create index "share_links_site_id" to table: "share_links"
+DROP INDEX `share_links_site_id`;
+-- reverse: create index "share_links_token_hash_key" to table: "share_links"
+DROP INDEX `share_links_token_hash_key`;
+-- reverse: create "share_links" table
+DROP TABLE `share_links`;
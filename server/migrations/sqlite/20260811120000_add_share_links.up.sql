@@ -0,0 +1,17 @@
+-- create "share_links" table
+CREATE TABLE `share_links` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `site_id` integer NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `prefix` varchar(16) NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `password_hash` varchar(255) NULL,
+  `restricted` boolean NOT NULL DEFAULT false,
+  `breakdowns` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "share_links_token_hash_key" to table: "share_links"
+CREATE UNIQUE INDEX `share_links_token_hash_key` ON `share_links` (`token_hash`);
+-- create index "share_links_site_id" to table: "share_links"
+CREATE INDEX `share_links_site_id` ON `share_links` (`site_id`);
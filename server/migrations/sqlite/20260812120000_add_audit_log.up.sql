@@ -0,0 +1,25 @@
+-- create "audit_entries" table
+CREATE TABLE `audit_entries` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `actor_id` integer NULL,
+  `actor_name` varchar(128) NOT NULL DEFAULT '',
+  `action` varchar(64) NOT NULL,
+  `site_id` integer NULL,
+  `target_user_id` integer NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp)
+);
+-- create index "audit_entries_created_at" to table: "audit_entries"
+CREATE INDEX `audit_entries_created_at` ON `audit_entries` (`created_at`);
+-- create index "audit_entries_site_id_created_at" to table: "audit_entries"
+CREATE INDEX `audit_entries_site_id_created_at` ON `audit_entries` (`site_id`, `created_at`);
+-- create "audit_changes" table
+CREATE TABLE `audit_changes` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `entry_id` integer NOT NULL,
+  `field` varchar(64) NOT NULL,
+  `before_value` text NULL,
+  `after_value` text NULL,
+  CONSTRAINT `0` FOREIGN KEY (`entry_id`) REFERENCES `audit_entries` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "audit_changes_entry_id" to table: "audit_changes"
+CREATE INDEX `audit_changes_entry_id` ON `audit_changes` (`entry_id`);
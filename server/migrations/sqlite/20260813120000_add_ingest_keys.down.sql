@@ -0,0 +1,6 @@
+-- reverse: create index "ingest_keys_site_id" to table: "ingest_keys"
+DROP INDEX `ingest_keys_site_id`;
+-- reverse: create index "ingest_keys_token_hash_key" to table: "ingest_keys"
+DROP INDEX `ingest_keys_token_hash_key`;
+-- reverse: create "ingest_keys" table
+DROP TABLE `ingest_keys`;
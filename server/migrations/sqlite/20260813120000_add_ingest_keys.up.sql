@@ -0,0 +1,15 @@
+-- create "ingest_keys" table
+CREATE TABLE `ingest_keys` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `site_id` integer NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `prefix` varchar(16) NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `last_used_at` timestamp NULL,
+  `created_at` timestamp NOT NULL DEFAULT (current_timestamp),
+  CONSTRAINT `0` FOREIGN KEY (`site_id`) REFERENCES `sites` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- create index "ingest_keys_token_hash_key" to table: "ingest_keys"
+CREATE UNIQUE INDEX `ingest_keys_token_hash_key` ON `ingest_keys` (`token_hash`);
+-- create index "ingest_keys_site_id" to table: "ingest_keys"
+CREATE INDEX `ingest_keys_site_id` ON `ingest_keys` (`site_id`);
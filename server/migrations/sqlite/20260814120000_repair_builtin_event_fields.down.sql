@@ -0,0 +1 @@
+-- reverse: the built-in event field repair only rewrites data, and the previous field shapes are not kept
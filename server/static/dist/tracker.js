@@ -1 +1 @@
-"use strict";(()=>{(()=>{let o=document.currentScript,d=o?.getAttribute("data-site-key")??"",m=o?.getAttribute("data-api-url")??o?.src?.replace(/\/[^/]*$/,"")??"",h=o?.getAttribute("data-include-query")==="true";if(!d||!m)return;let u="",s=!1,p=()=>h?window.location.pathname+window.location.search:window.location.pathname,w=()=>{let t=document.referrer;if(!t)return"";try{return new URL(t).hostname===window.location.hostname?"":t}catch{return t}},i=(t,r,e)=>{typeof e=="string"&&(t[r]=e)},_=t=>{if(typeof t=="string")return t;if(t!==void 0)return JSON.stringify(t)},S=t=>{let r=new URLSearchParams(window.location.search),e=w();e&&(t.referrer=e);let n=r.get("utm_source"),c=r.get("utm_medium"),y=r.get("utm_campaign");n&&(t.utm_source=n),c&&(t.utm_medium=c),y&&(t.utm_campaign=y)},k=(t,r=!1)=>{let e={path:p()};if(r&&S(e),!t)return e;i(e,"name",t.name),i(e,"path",t.path),i(e,"referrer",t.referrer),i(e,"utm_source",t.utm_source),i(e,"utm_medium",t.utm_medium),i(e,"utm_campaign",t.utm_campaign);let n=_(t.properties);return n!==void 0&&(e.properties=n),e},l=(t,r)=>{let e=`${m}${t}?site_key=${encodeURIComponent(d)}`,n=JSON.stringify(r);if(navigator.sendBeacon){let c=new Blob([n],{type:"text/plain;charset=UTF-8"});navigator.sendBeacon(e,c)}else fetch(e,{method:"POST",headers:{"Content-Type":"text/plain;charset=UTF-8"},body:n,keepalive:!0}).catch(()=>{})},a=t=>{let r=k(t,u===""&&!t?.name);r.path===u&&!r.name||(u=r.path,s=!1,l("/api/collect",r))},g=()=>{if(s)return;let t=p();t&&(s=!0,l("/api/collect",{path:t,exit:!0}))},f=()=>{a(),document.addEventListener("visibilitychange",()=>{document.visibilityState==="hidden"?g():s=!1});let t=history.pushState;history.pushState=function(...e){t.apply(this,e),a()};let r=history.replaceState;history.replaceState=function(...e){r.apply(this,e),a()},window.addEventListener("popstate",()=>{a()}),window.addEventListener("pagehide",g)};window.lovelyEye={track:a},document.readyState==="complete"?f():window.addEventListener("load",f)})();})();
+"use strict";(()=>{var I="outbound_link",U="file_download",M=[1200,992,768,576,320],P="data-le-prop-",H=100,D=10,W=100,B=500,C=8*1024,V=["pdf","zip","gz","tgz","rar","7z","dmg","exe","msi","pkg","deb","rpm","apk","csv","xls","xlsx","doc","docx","ppt","pptx","txt","epub","mp3","mp4","wav","mov"];(()=>{let a=document.currentScript,d=a?.getAttribute("data-site-key")??"",l=a?.getAttribute("data-api-url")??a?.src?.replace(/\/[^/]*$/,"")??"",b=a?.getAttribute("data-include-query")==="true",p=a?.getAttribute("data-track-outbound")==="true",g=a?.getAttribute("data-track-clicks")==="true";if(!d||!l)return;let f=(t=>!t||t==="false"?[]:t==="true"?V:t.split(",").map(e=>e.trim().replace(/^\./,"").toLowerCase()).filter(e=>e!==""))(a?.getAttribute("data-track-downloads")),m="",u=!1,h=()=>b?window.location.pathname+window.location.search:window.location.pathname,S=()=>{let t=document.referrer;if(!t)return"";try{return new URL(t).hostname===window.location.hostname?"":t}catch{return t}},v=()=>{let t=window.innerWidth;return M.find(e=>t>=e)??(t>0?1:0)},i=(t,e,n)=>{typeof n=="string"&&(t[e]=n)},A=t=>{if(typeof t=="string")return t;if(t!==void 0)return JSON.stringify(t)},L=t=>{let e=new URLSearchParams(window.location.search),n=S();n&&(t.referrer=n);let r=e.get("utm_source"),s=e.get("utm_medium"),c=e.get("utm_campaign"),T=e.get("utm_term"),k=e.get("utm_content");r&&(t.utm_source=r),s&&(t.utm_medium=s),c&&(t.utm_campaign=c),T&&(t.utm_term=T),k&&(t.utm_content=k)},x=(t,e=!1)=>{let n={path:h()};if(e&&L(n),!t)return n;i(n,"name",t.name),i(n,"path",t.path),i(n,"referrer",t.referrer),i(n,"utm_source",t.utm_source),i(n,"utm_medium",t.utm_medium),i(n,"utm_campaign",t.utm_campaign),i(n,"utm_term",t.utm_term),i(n,"utm_content",t.utm_content);let r=A(t.properties);return r!==void 0&&(n.properties=r),n},_=(t,e)=>{let n=`${l}${t}?site_key=${encodeURIComponent(d)}`,r=JSON.stringify(e);if(navigator.sendBeacon){let s=new Blob([r],{type:"text/plain;charset=UTF-8"});navigator.sendBeacon(n,s)}else fetch(n,{method:"POST",headers:{"Content-Type":"text/plain;charset=UTF-8"},body:r,keepalive:!0}).catch(()=>{})},o=t=>{let e=x(t,m===""&&!t?.name);if(!(e.path===m&&!e.name)){if(m=e.path,u=!1,!e.name){let n=v();n>0&&(e.viewport_width=n)}_("/api/collect",e)}},E=()=>{if(u)return;let t=h();t&&(u=!0,_("/api/collect",{path:t,exit:!0}))},R=t=>{let e=/\.([^./]+)$/.exec(t.pathname)?.[1]?.toLowerCase();return e!==void 0&&f.includes(e)},O=t=>{if(t.protocol!=="http:"&&t.protocol!=="https:")return;let e=t.origin+t.pathname;R(t)?o({name:U,properties:{url:e}}):p&&t.host!==window.location.host&&o({name:I,properties:{url:e}})},N=t=>{let e=t.getAttribute("data-le-event")?.trim();if(!e||e.length>H)return;let n={},r=0;for(let s of Array.from(t.attributes)){if(r>=D)break;if(!s.name.startsWith(P))continue;let c=s.name.slice(P.length);if(!(!c||c.length>W)){if(n[c]=s.value.slice(0,B),new TextEncoder().encode(JSON.stringify(n)).length>C){delete n[c];break}r++}}o({name:e,properties:n})},y=t=>{if(t.type==="auxclick"&&t.button!==1||!(t.target instanceof Element))return;let e=g?t.target.closest("[data-le-event]"):null;if(e){N(e);return}let n=t.target.closest("a[href]");n instanceof HTMLAnchorElement&&O(n)},w=()=>{o(),(p||g||f.length>0)&&(document.addEventListener("click",y,!0),document.addEventListener("auxclick",y,!0)),document.addEventListener("visibilitychange",()=>{document.visibilityState==="hidden"?E():u=!1});let t=history.pushState;history.pushState=function(...n){t.apply(this,n),o()};let e=history.replaceState;history.replaceState=function(...n){e.apply(this,n),o()},window.addEventListener("popstate",()=>{o()}),window.addEventListener("pagehide",E)};window.lovelyEye={track:o},document.readyState==="complete"?w():window.addEventListener("load",w)})();})();
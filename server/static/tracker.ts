@@ -10,6 +10,8 @@ type TrackInput = {
   utm_source?: string;
   utm_medium?: string;
   utm_campaign?: string;
+  utm_term?: string;
+  utm_content?: string;
 };
 
 type TrackPayload = {
@@ -18,12 +20,32 @@ type TrackPayload = {
   properties?: string;
   referrer?: string;
   exit?: true;
+  viewport_width?: number;
   utm_source?: string;
   utm_medium?: string;
   utm_campaign?: string;
+  utm_term?: string;
+  utm_content?: string;
 };
 
-type PayloadStringKey = 'name' | 'path' | 'referrer' | 'utm_source' | 'utm_medium' | 'utm_campaign';
+type PayloadStringKey = 'name' | 'path' | 'referrer' | 'utm_source' | 'utm_medium' | 'utm_campaign' | 'utm_term' | 'utm_content';
+
+const OUTBOUND_LINK_EVENT = 'outbound_link';
+const FILE_DOWNLOAD_EVENT = 'file_download';
+// Lower bounds of the server's screen size buckets, widest first.
+const VIEWPORT_BREAKPOINTS = [1200, 992, 768, 576, 320];
+const PROPERTY_ATTRIBUTE_PREFIX = 'data-le-prop-';
+// Tagged clicks stay within the server's event name, field key, and default field length limits, and
+// below the default ANALYTICS_MAX_PROPERTIES_BYTES, so that one oversized attribute does not drop the click.
+const MAX_EVENT_NAME_LENGTH = 100;
+const MAX_TAGGED_PROPERTIES = 10;
+const MAX_PROPERTY_KEY_LENGTH = 100;
+const MAX_PROPERTY_VALUE_LENGTH = 500;
+const MAX_PROPERTIES_BYTES = 8 * 1024;
+const DEFAULT_DOWNLOAD_EXTENSIONS = [
+  'pdf', 'zip', 'gz', 'tgz', 'rar', '7z', 'dmg', 'exe', 'msi', 'pkg', 'deb', 'rpm', 'apk',
+  'csv', 'xls', 'xlsx', 'doc', 'docx', 'ppt', 'pptx', 'txt', 'epub', 'mp3', 'mp4', 'wav', 'mov',
+];
 
 declare global {
   interface Window {
@@ -38,9 +60,23 @@ declare global {
   const siteKey = script?.getAttribute('data-site-key') ?? '';
   const apiUrl = script?.getAttribute('data-api-url') ?? script?.src?.replace(/\/[^/]*$/, '') ?? '';
   const includeQuery = script?.getAttribute('data-include-query') === 'true';
+  const trackOutbound = script?.getAttribute('data-track-outbound') === 'true';
+  const trackTagged = script?.getAttribute('data-track-clicks') === 'true';
 
   if (!siteKey || !apiUrl) return;
 
+  // data-track-downloads is "true" for the default extensions or a comma-separated list of its own.
+  const getDownloadExtensions = (value: string | null | undefined): string[] => {
+    if (!value || value === 'false') return [];
+    if (value === 'true') return DEFAULT_DOWNLOAD_EXTENSIONS;
+    return value
+      .split(',')
+      .map((extension) => extension.trim().replace(/^\./, '').toLowerCase())
+      .filter((extension) => extension !== '');
+  };
+
+  const downloadExtensions = getDownloadExtensions(script?.getAttribute('data-track-downloads'));
+
   let lastPath = '';
   let exitSent = false;
 
@@ -59,6 +95,12 @@ declare global {
     }
   };
 
+  // The exact width would help fingerprinting, so only the lower bound of its bucket is sent.
+  const getViewportWidth = (): number => {
+    const width = window.innerWidth;
+    return VIEWPORT_BREAKPOINTS.find((breakpoint) => width >= breakpoint) ?? (width > 0 ? 1 : 0);
+  };
+
   const assignStringOverride = (
     payload: TrackPayload,
     key: PayloadStringKey,
@@ -89,9 +131,13 @@ declare global {
     const utmSource = params.get('utm_source');
     const utmMedium = params.get('utm_medium');
     const utmCampaign = params.get('utm_campaign');
+    const utmTerm = params.get('utm_term');
+    const utmContent = params.get('utm_content');
     if (utmSource) payload.utm_source = utmSource;
     if (utmMedium) payload.utm_medium = utmMedium;
     if (utmCampaign) payload.utm_campaign = utmCampaign;
+    if (utmTerm) payload.utm_term = utmTerm;
+    if (utmContent) payload.utm_content = utmContent;
   };
 
   const buildPayload = (data?: TrackInput, includeAttribution = false): TrackPayload => {
@@ -109,6 +155,8 @@ declare global {
     assignStringOverride(payload, 'utm_source', data.utm_source);
     assignStringOverride(payload, 'utm_medium', data.utm_medium);
     assignStringOverride(payload, 'utm_campaign', data.utm_campaign);
+    assignStringOverride(payload, 'utm_term', data.utm_term);
+    assignStringOverride(payload, 'utm_content', data.utm_content);
 
     const properties = getPropertiesValue(data.properties);
     if (properties !== undefined) {
@@ -140,6 +188,10 @@ declare global {
     if (payload.path === lastPath && !payload.name) return;
     lastPath = payload.path;
     exitSent = false;
+    if (!payload.name) {
+      const viewportWidth = getViewportWidth();
+      if (viewportWidth > 0) payload.viewport_width = viewportWidth;
+    }
     send('/api/collect', payload);
   };
 
@@ -151,9 +203,64 @@ declare global {
     send('/api/collect', { path, exit: true });
   };
 
+  const isDownload = (link: HTMLAnchorElement): boolean => {
+    const extension = /\.([^./]+)$/.exec(link.pathname)?.[1]?.toLowerCase();
+    return extension !== undefined && downloadExtensions.includes(extension);
+  };
+
+  // Link URLs are sent without their query string or fragment, which can carry tokens.
+  const trackLink = (link: HTMLAnchorElement): void => {
+    if (link.protocol !== 'http:' && link.protocol !== 'https:') return;
+    const url = link.origin + link.pathname;
+    if (isDownload(link)) {
+      track({ name: FILE_DOWNLOAD_EVENT, properties: { url } });
+    } else if (trackOutbound && link.host !== window.location.host) {
+      track({ name: OUTBOUND_LINK_EVENT, properties: { url } });
+    }
+  };
+
+  const trackTaggedElement = (element: Element): void => {
+    const name = element.getAttribute('data-le-event')?.trim();
+    if (!name || name.length > MAX_EVENT_NAME_LENGTH) return;
+    const properties: Record<string, string> = {};
+    let count = 0;
+    for (const attribute of Array.from(element.attributes)) {
+      if (count >= MAX_TAGGED_PROPERTIES) break;
+      if (!attribute.name.startsWith(PROPERTY_ATTRIBUTE_PREFIX)) continue;
+      const key = attribute.name.slice(PROPERTY_ATTRIBUTE_PREFIX.length);
+      if (!key || key.length > MAX_PROPERTY_KEY_LENGTH) continue;
+      properties[key] = attribute.value.slice(0, MAX_PROPERTY_VALUE_LENGTH);
+      if (new TextEncoder().encode(JSON.stringify(properties)).length > MAX_PROPERTIES_BYTES) {
+        delete properties[key];
+        break;
+      }
+      count++;
+    }
+    track({ name, properties });
+  };
+
+  const handleClick = (event: MouseEvent): void => {
+    if (event.type === 'auxclick' && event.button !== 1) return;
+    if (!(event.target instanceof Element)) return;
+
+    const tagged = trackTagged ? event.target.closest('[data-le-event]') : null;
+    if (tagged) {
+      trackTaggedElement(tagged);
+      return;
+    }
+    const link = event.target.closest('a[href]');
+    if (link instanceof HTMLAnchorElement) trackLink(link);
+  };
+
   const init = (): void => {
     track();
 
+    if (trackOutbound || trackTagged || downloadExtensions.length > 0) {
+      // Capture clicks so that page handlers that stop propagation do not hide them.
+      document.addEventListener('click', handleClick, true);
+      document.addEventListener('auxclick', handleClick, true);
+    }
+
     document.addEventListener('visibilitychange', () => {
       if (document.visibilityState === 'hidden') {
         trackExit();